@@ -0,0 +1,58 @@
+package v1
+
+import "time"
+
+type CreateAccessRequestRequest struct {
+	RoleId        uint   `json:"roleId" binding:"required"`
+	Reason        string `json:"reason" binding:"required,max=255" example:"负责本周的线上值班"`
+	DurationHours int    `json:"durationHours" binding:"omitempty,min=1,max=720" example:"8"` // 为空表示长期拥有
+}
+type DecideAccessRequestRequest struct {
+	Comment string `json:"comment" binding:"max=255"`
+}
+type ListAccessRequestsRequest struct {
+	PageRequest
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected cancelled"`
+	UserId string `form:"userId"`
+	RoleId uint   `form:"roleId"`
+}
+
+type AccessRequestEventData struct {
+	FromStatus string    `json:"fromStatus"` // 创建申请时为空
+	ToStatus   string    `json:"toStatus"`
+	ActorId    string    `json:"actorId"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+type AccessRequestData struct {
+	Id            uint       `json:"id"`
+	UserId        string     `json:"userId"`
+	RoleId        uint       `json:"roleId"`
+	RoleLabel     string     `json:"roleLabel"`
+	RoleName      string     `json:"roleName"`
+	Reason        string     `json:"reason"`
+	DurationHours int        `json:"durationHours"`
+	Status        string     `json:"status"` // pending、approved、rejected或cancelled
+	DecidedBy     string     `json:"decidedBy"`
+	DecidedAt     *time.Time `json:"decidedAt"`
+	GrantId       uint       `json:"grantId,omitempty"` // 限时申请批准后创建的临时授权
+	CreatedAt     time.Time  `json:"createdAt"`
+	// 只在查询单个申请时返回
+	Events []AccessRequestEventData `json:"events,omitempty"`
+}
+type AccessRequestResponse struct {
+	Response
+	Data AccessRequestData
+}
+type AccessRequestsResponse struct {
+	Response
+	Data []AccessRequestData
+}
+type ListAccessRequestsResponseData struct {
+	List  []AccessRequestData `json:"list"`
+	Total int64               `json:"total"`
+}
+type ListAccessRequestsResponse struct {
+	Response
+	Data ListAccessRequestsResponseData
+}
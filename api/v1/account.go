@@ -0,0 +1,19 @@
+package v1
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"` // 邮件链接中的token
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"1234@gmail.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"` // 邮件链接中的token
+	Password string `json:"password" binding:"required" example:"123456"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required" example:"123456"`
+	NewPassword string `json:"newPassword" binding:"required" example:"N3w-passw0rd"`
+}
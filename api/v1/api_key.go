@@ -0,0 +1,54 @@
+package v1
+
+import "time"
+
+type CreateApiKeyRequest struct {
+	Name          string `json:"name" binding:"required,max=64" example:"ci-deploy"`
+	Scopes        []uint `json:"scopes" binding:"required,min=1"`            // 可访问的api权限ID，不能超出所属用户拥有的权限
+	ExpiresInDays int    `json:"expiresInDays" binding:"min=0" example:"90"` // 0表示使用默认有效期
+}
+
+type ApiKeyScope struct {
+	PermissionId   uint   `json:"permissionId"`
+	PermissionName string `json:"permissionName"`
+	Path           string `json:"path"`
+}
+
+type ApiKeyData struct {
+	KeyId      string        `json:"keyId"`
+	Prefix     string        `json:"prefix" example:"ak_3fK9"` // 密钥开头的字符，便于辨认
+	Name       string        `json:"name"`
+	UserId     string        `json:"userId"`
+	Scopes     []ApiKeyScope `json:"scopes"`
+	ExpiresAt  *time.Time    `json:"expiresAt"`
+	LastUsedAt *time.Time    `json:"lastUsedAt"`
+	LastUsedIp string        `json:"lastUsedIp"`
+	RevokedAt  *time.Time    `json:"revokedAt"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+type ListApiKeysResponse struct {
+	Response
+	Data []ApiKeyData
+}
+
+type CreateApiKeyData struct {
+	ApiKeyData
+	Key string `json:"key"` // 完整密钥仅在创建时返回一次，调用接口时放在X-API-Key请求头中
+}
+type CreateApiKeyResponse struct {
+	Response
+	Data CreateApiKeyData
+}
+
+type CreateServiceAccountRequest struct {
+	Nickname string   `json:"nickname" binding:"required,max=64" example:"deploy-bot"`
+	Roles    []string `json:"roles"` // 角色标识，服务账号的API密钥权限不能超出这些角色的权限
+}
+type ServiceAccountData struct {
+	UserId   string `json:"userId"`
+	Nickname string `json:"nickname"`
+}
+type ServiceAccountResponse struct {
+	Response
+	Data ServiceAccountData
+}
@@ -0,0 +1,60 @@
+package v1
+
+import "time"
+
+type CreateDeptRequest struct {
+	ParentId uint   `json:"parentId"` // 0表示顶级部门
+	DeptName string `json:"deptName" binding:"required,max=64" example:"研发部"`
+	LeaderId string `json:"leaderId"`                                                            // 负责人的用户ID，为空表示未指定
+	Sort     int    `json:"sort"`                                                                // 同级部门按升序排列
+	Status   string `json:"status" binding:"omitempty,oneof=enabled disabled" example:"enabled"` // 为空时为enabled
+}
+type UpdateDeptRequest struct {
+	ParentId uint   `json:"parentId"` // 修改后整个子树随之移动
+	DeptName string `json:"deptName" binding:"required,max=64" example:"研发部"`
+	LeaderId string `json:"leaderId"`
+	Sort     int    `json:"sort"`
+	Status   string `json:"status" binding:"omitempty,oneof=enabled disabled" example:"enabled"`
+}
+type DeptUsersRequest struct {
+	UserIds []string `json:"userIds" binding:"required,min=1,max=500"`
+}
+type ListDeptUsersRequest struct {
+	PageRequest
+	IncludeChildren bool `form:"includeChildren"` // 包括下级部门的成员
+}
+
+type DeptData struct {
+	Id        uint        `json:"id"`
+	ParentId  uint        `json:"parentId"`
+	DeptName  string      `json:"deptName"`
+	LeaderId  string      `json:"leaderId"`
+	Sort      int         `json:"sort"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+	Children  []*DeptData `json:"children,omitempty"` // 只在部门树中返回
+}
+type DeptResponse struct {
+	Response
+	Data DeptData
+}
+type DeptTreeResponse struct {
+	Response
+	Data []DeptData
+}
+type DeptUserData struct {
+	UserId         string `json:"userId"`
+	Nickname       string `json:"nickname"`
+	Email          string `json:"email"`
+	ServiceAccount bool   `json:"serviceAccount"`
+	DeptId         uint   `json:"deptId"`
+}
+type ListDeptUsersResponseData struct {
+	List  []DeptUserData `json:"list"`
+	Total int64          `json:"total"`
+}
+type ListDeptUsersResponse struct {
+	Response
+	Data ListDeptUsersResponseData
+}
@@ -5,6 +5,7 @@ var (
 	ErrSuccess             = newError(0, "ok")
 	ErrBadRequest          = newError(400, "Bad Request")
 	ErrUnauthorized        = newError(401, "Unauthorized")
+	ErrForbidden           = newError(403, "Forbidden")
 	ErrNotFound            = newError(404, "Not Found")
 	ErrInternalServerError = newError(500, "Internal Server Error")
 
@@ -14,4 +15,77 @@ var (
 	// more biz errors
 	ErrEmailAlreadyUse = newError(1001, "The email is already in use.")
 	ErrSortParams      = newError(1002, "The sort parameter is invalid.")
+
+	// token errors
+	ErrRefreshTokenInvalid = newError(1101, "The refresh token is invalid or expired.")
+	ErrRefreshTokenReused  = newError(1102, "The refresh token has already been used.")
+	ErrTokenRevoked        = newError(1103, "The token has been revoked.")
+
+	// two-factor errors
+	ErrTwoFactorCodeInvalid    = newError(1201, "The verification code is invalid.")
+	ErrTwoFactorAlreadyEnabled = newError(1202, "Two-factor authentication is already enabled.")
+	ErrTwoFactorNotEnabled     = newError(1203, "Two-factor authentication is not enabled.")
+	ErrTwoFactorRequired       = newError(1204, "Two-factor authentication is required by your role.")
+	ErrChallengeInvalid        = newError(1205, "The login challenge is invalid or expired.")
+
+	// account errors
+	ErrLinkInvalid          = newError(1301, "The link is invalid or expired.")
+	ErrEmailAlreadyVerified = newError(1302, "The email is already verified.")
+
+	// login errors
+	ErrAccountLocked        = newError(1401, "The account is temporarily locked due to too many failed login attempts.")
+	ErrTooManyLoginAttempts = newError(1402, "Too many login attempts, please try again later.")
+
+	// password errors
+	ErrPasswordPolicy    = newError(1501, "The password does not meet the password policy.")
+	ErrPasswordIncorrect = newError(1502, "The current password is incorrect.")
+
+	// single sign-on errors
+	ErrOIDCDisabled        = newError(1601, "Single sign-on is not enabled.")
+	ErrOIDCStateInvalid    = newError(1602, "The single sign-on request is invalid or expired.")
+	ErrOIDCLoginFailed     = newError(1603, "Single sign-on failed.")
+	ErrOIDCAccountNotFound = newError(1604, "No account is linked to this identity.")
+
+	// api key errors
+	ErrApiKeyInvalid      = newError(1701, "The API key is invalid, expired or revoked.")
+	ErrApiKeyScopeInvalid = newError(1702, "The API key scopes are invalid.")
+	ErrApiKeyNotAllowed   = newError(1703, "This operation cannot be performed with an API key.")
+
+	// impersonation errors
+	ErrImpersonationNotAllowed = newError(1801, "This operation cannot be performed while impersonating.")
+	ErrImpersonationTarget     = newError(1802, "This user cannot be impersonated.")
+	ErrNotImpersonating        = newError(1803, "The current token is not an impersonation token.")
+
+	// role and permission errors
+	ErrRoleLabelExists    = newError(1901, "The role label is already in use.")
+	ErrRoleProtected      = newError(1902, "The default role cannot be deleted or relabeled.")
+	ErrPermissionNotFound = newError(1903, "One or more permissions do not exist.")
+	ErrPermissionInvalid  = newError(1904, "The permission path or method is invalid.")
+	ErrPermissionParent   = newError(1905, "The parent permission does not exist or cannot have children.")
+	ErrPermissionCycle    = newError(1906, "A permission cannot be moved under itself or its descendants.")
+	ErrPermissionChildren = newError(1907, "The permission still has child permissions.")
+	ErrRoleNotFound       = newError(1908, "One or more roles do not exist.")
+	ErrUserNotFound       = newError(1909, "One or more users do not exist.")
+	ErrRoleCycle          = newError(1910, "A role cannot inherit from itself or its descendants.")
+	ErrDeptNotFound       = newError(1911, "One or more departments do not exist.")
+	ErrDeptCycle          = newError(1912, "A department cannot be moved under itself or its descendants.")
+	ErrDeptNotEmpty       = newError(1913, "The department still has child departments or members.")
+	ErrDeptDisabled       = newError(1914, "The department is disabled.")
+	ErrRoleGrantPeriod    = newError(1915, "The grant must end after it starts and in the future.")
+	ErrRoleGrantEnded     = newError(1916, "The role grant has already ended.")
+	ErrRouteNotFound      = newError(1917, "No registered route matches the method and path.")
+	ErrRoleNotHeld        = newError(1918, "You cannot assign a role you do not hold.")
+
+	// tenant errors
+	ErrTenantCodeExists = newError(2001, "The tenant code is already in use.")
+	ErrTenantForbidden  = newError(2002, "Only platform administrators can perform this operation.")
+	ErrTenantNotEmpty   = newError(2003, "The tenant still has users.")
+
+	// access request errors
+	ErrRoleNotRequestable        = newError(2101, "The role cannot be requested.")
+	ErrRoleAlreadyAssigned       = newError(2102, "The user already has the role.")
+	ErrAccessRequestPending      = newError(2103, "A pending request for the role already exists.")
+	ErrAccessRequestDecided      = newError(2104, "The request is no longer pending.")
+	ErrAccessRequestForbidden    = newError(2105, "You are not an approver of the requested role.")
+	ErrAccessRequestSelfApproval = newError(2106, "You cannot approve or reject your own request.")
 )
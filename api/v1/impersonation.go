@@ -0,0 +1,35 @@
+package v1
+
+import "time"
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"排查工单#1024菜单显示异常"` // 记录到审计日志
+}
+type ImpersonationTokenData struct {
+	AccessToken string    `json:"accessToken"` // 以被模拟用户的身份访问，不能刷新
+	ExpiresIn   int64     `json:"expiresIn" example:"1800"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	UserId      string    `json:"userId"` // 被模拟的用户
+}
+type ImpersonationTokenResponse struct {
+	Response
+	Data ImpersonationTokenData
+}
+
+type ListImpersonationsRequest struct {
+	UserId string `form:"userId"` // 只返回该用户发起或被模拟的记录
+}
+type ImpersonationData struct {
+	ActorId   string     `json:"actorId"`
+	UserId    string     `json:"userId"`
+	Reason    string     `json:"reason"`
+	Ip        string     `json:"ip"`
+	UserAgent string     `json:"userAgent"`
+	StartedAt time.Time  `json:"startedAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	EndedAt   *time.Time `json:"endedAt"` // 为空表示未主动结束
+}
+type ListImpersonationsResponse struct {
+	Response
+	Data []ImpersonationData
+}
@@ -0,0 +1,16 @@
+package v1
+
+type OIDCAuthorizationData struct {
+	AuthorizationUrl string `json:"authorizationUrl"` // 前端跳转到该地址完成身份提供方登录
+	State            string `json:"state"`
+}
+type OIDCAuthorizationResponse struct {
+	Response
+	Data OIDCAuthorizationData
+}
+
+type OIDCCallbackRequest struct {
+	Code   string `json:"code" binding:"required"`  // 身份提供方回调地址中的code
+	State  string `json:"state" binding:"required"` // 身份提供方回调地址中的state
+	Device string `json:"device" example:"MacBook Pro"`
+}
@@ -0,0 +1,40 @@
+package v1
+
+type CreatePermissionRequest struct {
+	PermissionName string `json:"permissionName" binding:"required,max=64" example:"用户管理"`
+	PermissionType string `json:"permissionType" binding:"required,oneof=menu button api" example:"menu"`
+	ParentId       uint   `json:"parentId"` // 0表示根节点，父节点必须是菜单
+	Icon           string `json:"icon"`
+	Route          string `json:"route" example:"/system/user"`
+	RouteFile      string `json:"routeFile" example:"views/system/user/index.vue"`
+	Path           string `json:"path" example:"/v1/users/*"` // api类型必填
+	Method         string `json:"method" example:"GET,POST"`  // 多个用逗号分隔，all或为空表示全部方法
+	Sort           string `json:"sort"`                       // 为空时排在同级节点最后
+}
+type UpdatePermissionRequest struct {
+	PermissionName string `json:"permissionName" binding:"required,max=64" example:"用户管理"`
+	PermissionType string `json:"permissionType" binding:"required,oneof=menu button api" example:"menu"`
+	Icon           string `json:"icon"`
+	Route          string `json:"route" example:"/system/user"`
+	RouteFile      string `json:"routeFile" example:"views/system/user/index.vue"`
+	Path           string `json:"path" example:"/v1/users/*"`
+	Method         string `json:"method" example:"GET,POST"`
+	Sort           string `json:"sort"`
+}
+type MovePermissionRequest struct {
+	ParentId uint   `json:"parentId"` // 0表示移动到根节点
+	Sort     string `json:"sort"`     // 为空时排在新的同级节点最后
+}
+type SortPermissionsRequest struct {
+	ParentId uint   `json:"parentId"`
+	Ids      []uint `json:"ids" binding:"required,min=1"` // 按新的顺序排列的全部同级节点
+}
+
+type PermissionResponse struct {
+	Response
+	Data GetMenuTreeResponseData
+}
+type PermissionTreeResponse struct {
+	Response
+	Data []GetMenuTreeResponseData
+}
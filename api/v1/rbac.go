@@ -0,0 +1,62 @@
+package v1
+
+type PermissionCacheStatsData struct {
+	Enabled       bool    `json:"enabled"`
+	Redis         bool    `json:"redis"`    // 是否通过redis在多实例间共享
+	Size          int     `json:"size"`     // 进程内缓存的用户数
+	Capacity      int     `json:"capacity"` // 进程内缓存的容量上限
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRate       float64 `json:"hitRate" example:"0.98"`
+	Evictions     uint64  `json:"evictions"`     // 超出容量被淘汰的次数
+	Invalidations uint64  `json:"invalidations"` // 角色或权限变化导致的清除次数
+}
+type PermissionCacheStatsResponse struct {
+	Response
+	Data PermissionCacheStatsData
+}
+
+type ExplainPermissionRequest struct {
+	UserId string                `json:"userId" binding:"required" example:"1"`
+	Method string                `json:"method" binding:"required" example:"DELETE"`
+	Path   string                `json:"path" binding:"required" example:"/v1/roles/3"` // 实际请求路径或已注册的路由模板
+	DryRun *ExplainDryRunRequest `json:"dryRun"`                                        // 不为空时按调整后的角色评估，不会保存
+}
+type ExplainDryRunRequest struct {
+	AddRoleIds    []uint `json:"addRoleIds"`
+	RemoveRoleIds []uint `json:"removeRoleIds"` // 同时移除直接分配的角色和临时授权
+}
+type ExplainRoleData struct {
+	Id        uint   `json:"id"`
+	RoleLabel string `json:"roleLabel"`
+	RoleName  string `json:"roleName"`
+	Source    string `json:"source" example:"direct"` // direct直接分配，grant临时授权，proposed演练中新增，inherited继承自父角色
+}
+type ExplainRuleData struct {
+	PermissionId   uint   `json:"permissionId"`
+	PermissionName string `json:"permissionName"`
+	Path           string `json:"path"`
+	Method         string `json:"method"`
+	Effect         string `json:"effect" example:"allow"`
+	RoleIds        []uint `json:"roleIds"` // 关联该权限的角色
+}
+type ExplainDecisionData struct {
+	Allowed     bool              `json:"allowed"`
+	Reason      string            `json:"reason" example:"allow_rule"` // deny_rule、allow_rule、no_match或no_roles
+	Roles       []ExplainRoleData `json:"roles"`                       // 用户的全部角色，包括继承的角色
+	Permissions []ExplainRuleData `json:"permissions"`                 // 匹配的allow权限
+	DenyRules   []ExplainRuleData `json:"denyRules"`                   // 匹配的deny规则，存在时优先于allow
+}
+type ExplainPermissionData struct {
+	UserId   string               `json:"userId"`
+	Method   string               `json:"method"`
+	Path     string               `json:"path"`
+	Route    string               `json:"route" example:"/v1/roles/:roleId"` // 匹配到的路由模板
+	DryRun   bool                 `json:"dryRun"`
+	Decision ExplainDecisionData  `json:"decision"`          // 演练时为调整后角色的结果
+	Current  *ExplainDecisionData `json:"current,omitempty"` // 演练时当前角色的结果
+}
+type ExplainPermissionResponse struct {
+	Response
+	Data ExplainPermissionData
+}
@@ -0,0 +1,127 @@
+package v1
+
+import "time"
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// PageRequest 分页参数，page从1开始
+type PageRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100" example:"20"`
+}
+
+// Offset 返回查询偏移量和条数，未传参数时使用默认值
+func (p PageRequest) Offset() (int, int) {
+	page, size := p.Page, p.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	return (page - 1) * size, size
+}
+
+type CreateRoleRequest struct {
+	RoleLabel string `json:"roleLabel" binding:"required,max=64" example:"ops"`
+	RoleName  string `json:"roleName" binding:"required,max=64" example:"运维"`
+	ParentId  uint   `json:"parentId"`  // 继承该角色的全部权限，0表示没有父角色
+	TwoFactor bool   `json:"twoFactor"` // 拥有该角色的用户必须启用两步验证
+	// 数据范围：all全部、dept本部门、dept_and_children本部门及下级、self仅本人、custom自定义部门，为空时为all
+	DataScope        string `json:"dataScope" binding:"omitempty,oneof=all dept dept_and_children self custom" example:"all"`
+	DataScopeDeptIds []uint `json:"dataScopeDeptIds"` // dataScope为custom时可以查看的部门
+	ApproverRoleId   uint   `json:"approverRoleId"`   // 拥有该角色的用户可以审批申请本角色的请求，0表示不能申请
+}
+type UpdateRoleRequest struct {
+	RoleLabel string `json:"roleLabel" binding:"required,max=64" example:"ops"`
+	RoleName  string `json:"roleName" binding:"required,max=64" example:"运维"`
+	ParentId  uint   `json:"parentId"`
+	TwoFactor bool   `json:"twoFactor"`
+	// 与创建时相同，dataScope不是custom时清空自定义部门
+	DataScope        string `json:"dataScope" binding:"omitempty,oneof=all dept dept_and_children self custom" example:"all"`
+	DataScopeDeptIds []uint `json:"dataScopeDeptIds"`
+	ApproverRoleId   uint   `json:"approverRoleId"`
+}
+type ListRolesRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"` // 按标识或名称模糊查询
+	Deleted bool   `form:"deleted"` // 只查询已删除的角色，用于恢复
+}
+type RolePermissionsRequest struct {
+	PermissionIds []uint `json:"permissionIds" binding:"required,min=1"`
+	Effect        string `json:"effect" binding:"omitempty,oneof=allow deny" example:"allow"` // 取消关联时忽略；为空时按allow处理
+}
+
+type RolePermissionData struct {
+	Id             uint   `json:"id"`
+	PermissionName string `json:"permissionName"`
+	PermissionType string `json:"permissionType"`
+	Path           string `json:"path"`
+	Method         string `json:"method"`
+	Effect         string `json:"effect"` // deny优先于其他角色的allow
+}
+type RoleData struct {
+	Id        uint   `json:"id"`
+	RoleLabel string `json:"roleLabel"`
+	RoleName  string `json:"roleName"`
+	ParentId  uint   `json:"parentId"`
+	TwoFactor bool   `json:"twoFactor"`
+	DataScope string `json:"dataScope"`
+	// 审批申请本角色的请求的角色，0表示不能申请
+	ApproverRoleId uint `json:"approverRoleId"`
+	// 以下两项只在查询单个角色时返回
+	DataScopeDeptIds []uint               `json:"dataScopeDeptIds,omitempty"`
+	Permissions      []RolePermissionData `json:"permissions,omitempty"`
+	CreatedAt        time.Time            `json:"createdAt"`
+	UpdatedAt        time.Time            `json:"updatedAt"`
+	DeletedAt        *time.Time           `json:"deletedAt,omitempty"`
+}
+type RoleResponse struct {
+	Response
+	Data RoleData
+}
+type ListRolesResponseData struct {
+	List  []RoleData `json:"list"`
+	Total int64      `json:"total"`
+}
+type ListRolesResponse struct {
+	Response
+	Data ListRolesResponseData
+}
+
+type SetUserRolesRequest struct {
+	RoleIds []uint `json:"roleIds" binding:"required"` // 为空数组时移除用户的全部角色
+}
+type UserRolesRequest struct {
+	RoleIds []uint `json:"roleIds" binding:"required,min=1"`
+}
+type AssignRoleUsersRequest struct {
+	UserIds []string `json:"userIds" binding:"required,min=1,max=500"`
+}
+type UserRolesResponse struct {
+	Response
+	Data []RoleData
+}
+type ListRoleUsersRequest struct {
+	PageRequest
+}
+type RoleUserData struct {
+	UserId         string `json:"userId"`
+	Nickname       string `json:"nickname"`
+	Email          string `json:"email"`
+	ServiceAccount bool   `json:"serviceAccount"`
+}
+type ListRoleUsersResponseData struct {
+	List  []RoleUserData `json:"list"`
+	Total int64          `json:"total"`
+}
+type ListRoleUsersResponse struct {
+	Response
+	Data ListRoleUsersResponseData
+}
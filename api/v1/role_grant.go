@@ -0,0 +1,37 @@
+package v1
+
+import "time"
+
+type CreateRoleGrantRequest struct {
+	RoleId   uint       `json:"roleId" binding:"required"`
+	StartsAt *time.Time `json:"startsAt"` // 为空时立即生效
+	EndsAt   time.Time  `json:"endsAt" binding:"required"`
+	Reason   string     `json:"reason" binding:"required,max=255" example:"值班处理故障#2048"`
+}
+type ListRoleGrantsRequest struct {
+	Ended bool `form:"ended"` // 同时返回已结束的授权
+}
+
+type RoleGrantData struct {
+	Id        uint       `json:"id"`
+	UserId    string     `json:"userId"`
+	RoleId    uint       `json:"roleId"`
+	RoleLabel string     `json:"roleLabel"`
+	RoleName  string     `json:"roleName"`
+	StartsAt  time.Time  `json:"startsAt"`
+	EndsAt    time.Time  `json:"endsAt"`
+	Reason    string     `json:"reason"`
+	GrantedBy string     `json:"grantedBy"`
+	EndedAt   *time.Time `json:"endedAt"`   // 为空表示还未结束
+	EndReason string     `json:"endReason"` // expired到期或revoked撤销
+	RevokedBy string     `json:"revokedBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+type RoleGrantResponse struct {
+	Response
+	Data RoleGrantData
+}
+type ListRoleGrantsResponse struct {
+	Response
+	Data []RoleGrantData
+}
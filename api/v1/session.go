@@ -0,0 +1,19 @@
+package v1
+
+import "time"
+
+type SessionData struct {
+	SessionId  string    `json:"sessionId"`
+	UserId     string    `json:"userId"`
+	Device     string    `json:"device"`
+	Ip         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	IssuedAt   time.Time `json:"issuedAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"` // 是否为发起请求的会话
+}
+type ListSessionsResponse struct {
+	Response
+	Data []SessionData
+}
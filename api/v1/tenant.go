@@ -0,0 +1,38 @@
+package v1
+
+import "time"
+
+type CreateTenantRequest struct {
+	Code string `json:"code" binding:"required,max=64,alphanum" example:"acme"` // 创建后不能修改，删除后也不能复用
+	Name string `json:"name" binding:"required,max=64" example:"Acme"`
+}
+type UpdateTenantRequest struct {
+	Name string `json:"name" binding:"required,max=64" example:"Acme"`
+}
+type ListTenantsRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"` // 按标识或名称模糊查询
+}
+type MoveTenantUsersRequest struct {
+	UserIds []string `json:"userIds" binding:"required,min=1,max=500"`
+}
+
+type TenantData struct {
+	Id        uint      `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+type TenantResponse struct {
+	Response
+	Data TenantData
+}
+type ListTenantsResponseData struct {
+	List  []TenantData `json:"list"`
+	Total int64        `json:"total"`
+}
+type ListTenantsResponse struct {
+	Response
+	Data ListTenantsResponseData
+}
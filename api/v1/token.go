@@ -0,0 +1,9 @@
+package v1
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+type RefreshTokenResponse struct {
+	Response
+	Data LoginResponseData
+}
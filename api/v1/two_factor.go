@@ -0,0 +1,31 @@
+package v1
+
+type TwoFactorSetupData struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OtpauthUri string `json:"otpauthUri"` // 用于生成二维码
+}
+type TwoFactorSetupResponse struct {
+	Response
+	Data TwoFactorSetupData
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"` // 验证码或恢复码
+}
+
+type RecoveryCodesData struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // 仅展示一次，每个只能使用一次
+}
+type RecoveryCodesResponse struct {
+	Response
+	Data RecoveryCodesData
+}
+
+type TwoFactorLoginSetupRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+}
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required" example:"123456"` // 验证码或恢复码
+	Device         string `json:"device" example:"MacBook Pro"`
+}
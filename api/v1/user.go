@@ -10,9 +10,17 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"1234@gmail.com"`
 	Password string `json:"password" binding:"required" example:"123456"`
+	Device   string `json:"device" example:"MacBook Pro"` // 可选，用于会话列表展示
 }
 type LoginResponseData struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn" example:"900"` // 访问令牌有效期(秒)
+	// 需要两步验证时不返回令牌，客户端携带challengeToken调用/login/2fa完成登录
+	TwoFactorRequired      bool     `json:"twoFactorRequired,omitempty"`
+	TwoFactorSetupRequired bool     `json:"twoFactorSetupRequired,omitempty"` // 角色要求两步验证，需先调用/login/2fa/setup绑定
+	ChallengeToken         string   `json:"challengeToken,omitempty"`
+	RecoveryCodes          []string `json:"recoveryCodes,omitempty"` // 登录时完成绑定才会返回，仅展示一次
 }
 type LoginResponse struct {
 	Response
@@ -24,8 +32,9 @@ type UpdateProfileRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"1234@gmail.com"`
 }
 type GetProfileResponseData struct {
-	UserId   string `json:"userId"`
-	Nickname string `json:"nickname" example:"alan"`
+	UserId        string `json:"userId"`
+	Nickname      string `json:"nickname" example:"alan"`
+	EmailVerified bool   `json:"emailVerified"`
 }
 type GetProfileResponse struct {
 	Response
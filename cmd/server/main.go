@@ -24,6 +24,9 @@ import (
 // @securityDefinitions.apiKey Bearer
 // @in header
 // @name Authorization
+// @securityDefinitions.apiKey ApiKey
+// @in header
+// @name X-API-Key
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
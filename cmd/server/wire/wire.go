@@ -11,6 +11,8 @@ import (
 	"admin-webrtc-go/pkg/app"
 	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
+	"admin-webrtc-go/pkg/mailer"
+	"admin-webrtc-go/pkg/oidc"
 	"admin-webrtc-go/pkg/server/http"
 	"admin-webrtc-go/pkg/sid"
 	"github.com/google/wire"
@@ -23,27 +25,74 @@ var repositorySet = wire.NewSet(
 	repository.NewRepository,
 	repository.NewTransaction,
 	repository.NewUserRepository,
+	repository.NewTokenRepository,
+	repository.NewRevocationRepository,
+	repository.NewSessionRepository,
+	repository.NewTwoFactorRepository,
+	repository.NewLoginAttemptRepository,
+	repository.NewPasswordHistoryRepository,
+	repository.NewOIDCRepository,
+	repository.NewApiKeyRepository,
+	repository.NewImpersonationRepository,
+	repository.NewPermissionCacheRepository,
+	repository.NewRoleRepository,
+	repository.NewPermissionRepository,
+	repository.NewDeptRepository,
+	repository.NewTenantRepository,
+	repository.NewRoleGrantRepository,
+	repository.NewAccessRequestRepository,
 )
 
 var serviceSet = wire.NewSet(
 	service.NewService,
 	service.NewUserService,
+	service.NewTokenService,
+	service.NewSessionService,
+	service.NewTwoFactorService,
+	service.NewAccountService,
+	service.NewLoginAttemptService,
+	service.NewPasswordService,
+	service.NewOIDCService,
+	service.NewApiKeyService,
+	service.NewImpersonationService,
+	service.NewRBACService,
+	service.NewRoleService,
+	service.NewPermissionService,
+	service.NewDeptService,
+	service.NewTenantService,
+	service.NewRoleGrantService,
+	service.NewAccessRequestService,
 )
 
 var handlerSet = wire.NewSet(
 	handler.NewHandler,
 	handler.NewUserHandler,
+	handler.NewTokenHandler,
+	handler.NewSessionHandler,
+	handler.NewTwoFactorHandler,
+	handler.NewAccountHandler,
+	handler.NewOIDCHandler,
+	handler.NewApiKeyHandler,
+	handler.NewImpersonationHandler,
+	handler.NewRBACHandler,
+	handler.NewRoleHandler,
+	handler.NewPermissionHandler,
+	handler.NewDeptHandler,
+	handler.NewTenantHandler,
+	handler.NewRoleGrantHandler,
+	handler.NewAccessRequestHandler,
 )
 
 var serverSet = wire.NewSet(
 	server.NewHTTPServer,
 	server.NewJob,
+	server.NewRouteSync,
 )
 
 // build App
-func newApp(httpServer *http.Server, job *server.Job) *app.App {
+func newApp(httpServer *http.Server, job *server.Job, routeSync *server.RouteSync) *app.App {
 	return app.NewApp(
-		app.WithServer(httpServer, job),
+		app.WithServer(httpServer, job, routeSync),
 		app.WithName("demo-server"),
 	)
 }
@@ -57,6 +106,8 @@ func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
 		serverSet,
 		sid.NewSid,
 		jwt.NewJwt,
+		mailer.NewMailer,
+		oidc.NewProvider,
 		newApp,
 	))
 }
@@ -14,6 +14,8 @@ import (
 	"admin-webrtc-go/pkg/app"
 	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
+	"admin-webrtc-go/pkg/mailer"
+	"admin-webrtc-go/pkg/oidc"
 	"admin-webrtc-go/pkg/server/http"
 	"admin-webrtc-go/pkg/sid"
 	"github.com/google/wire"
@@ -31,26 +33,75 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 	sidSid := sid.NewSid()
 	serviceService := service.NewService(transaction, logger, sidSid, jwtJWT)
 	userRepository := repository.NewUserRepository(repositoryRepository)
-	userService := service.NewUserService(serviceService, userRepository)
+	tokenRepository := repository.NewTokenRepository(repositoryRepository)
+	revocationRepository := repository.NewRevocationRepository(repositoryRepository, viperViper)
+	sessionRepository := repository.NewSessionRepository(repositoryRepository)
+	tokenService := service.NewTokenService(serviceService, tokenRepository, revocationRepository, sessionRepository, userRepository)
+	twoFactorRepository := repository.NewTwoFactorRepository(repositoryRepository)
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository(repositoryRepository)
+	passwordService := service.NewPasswordService(serviceService, viperViper, passwordHistoryRepository)
+	mailerMailer := mailer.NewMailer(viperViper, logger)
+	accountService := service.NewAccountService(serviceService, viperViper, userRepository, tokenService, passwordService, mailerMailer)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(repositoryRepository)
+	loginAttemptService := service.NewLoginAttemptService(serviceService, viperViper, loginAttemptRepository)
+	twoFactorService := service.NewTwoFactorService(serviceService, viperViper, twoFactorRepository, userRepository, loginAttemptService)
+	permissionCacheRepository := repository.NewPermissionCacheRepository(repositoryRepository, viperViper)
+	userService := service.NewUserService(serviceService, userRepository, permissionCacheRepository, tokenService, twoFactorService, accountService, loginAttemptService, passwordService)
 	userHandler := handler.NewUserHandler(handlerHandler, userService)
-	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, userHandler, userService)
+	tokenHandler := handler.NewTokenHandler(handlerHandler, tokenService)
+	sessionService := service.NewSessionService(serviceService, sessionRepository, userRepository, tokenService)
+	sessionHandler := handler.NewSessionHandler(handlerHandler, sessionService)
+	twoFactorHandler := handler.NewTwoFactorHandler(handlerHandler, twoFactorService)
+	accountHandler := handler.NewAccountHandler(handlerHandler, accountService)
+	provider := oidc.NewProvider(viperViper)
+	oidcRepository := repository.NewOIDCRepository(repositoryRepository)
+	oidcService := service.NewOIDCService(serviceService, viperViper, provider, oidcRepository, userRepository, permissionCacheRepository, tokenService, twoFactorService, loginAttemptService)
+	oidcHandler := handler.NewOIDCHandler(handlerHandler, oidcService)
+	apiKeyRepository := repository.NewApiKeyRepository(repositoryRepository)
+	apiKeyService := service.NewApiKeyService(serviceService, viperViper, apiKeyRepository, userRepository)
+	apiKeyHandler := handler.NewApiKeyHandler(handlerHandler, apiKeyService)
+	impersonationRepository := repository.NewImpersonationRepository(repositoryRepository)
+	impersonationService := service.NewImpersonationService(serviceService, viperViper, impersonationRepository, userRepository, userService, tokenService)
+	impersonationHandler := handler.NewImpersonationHandler(handlerHandler, impersonationService)
+	roleRepository := repository.NewRoleRepository(repositoryRepository)
+	rbacService := service.NewRBACService(serviceService, permissionCacheRepository, userRepository, roleRepository)
+	rbacHandler := handler.NewRBACHandler(handlerHandler, rbacService)
+	roleService := service.NewRoleService(serviceService, roleRepository, userRepository, permissionCacheRepository)
+	roleHandler := handler.NewRoleHandler(handlerHandler, roleService)
+	permissionRepository := repository.NewPermissionRepository(repositoryRepository)
+	permissionService := service.NewPermissionService(serviceService, permissionRepository, permissionCacheRepository)
+	permissionHandler := handler.NewPermissionHandler(handlerHandler, permissionService)
+	deptRepository := repository.NewDeptRepository(repositoryRepository)
+	deptService := service.NewDeptService(serviceService, deptRepository, userRepository)
+	deptHandler := handler.NewDeptHandler(handlerHandler, deptService)
+	tenantRepository := repository.NewTenantRepository(repositoryRepository)
+	tenantService := service.NewTenantService(serviceService, tenantRepository, userRepository, permissionCacheRepository, tokenService)
+	tenantHandler := handler.NewTenantHandler(handlerHandler, tenantService)
+	roleGrantRepository := repository.NewRoleGrantRepository(repositoryRepository)
+	roleGrantService := service.NewRoleGrantService(serviceService, roleGrantRepository, roleRepository, userRepository, permissionCacheRepository)
+	roleGrantHandler := handler.NewRoleGrantHandler(handlerHandler, roleGrantService)
+	accessRequestRepository := repository.NewAccessRequestRepository(repositoryRepository)
+	accessRequestService := service.NewAccessRequestService(serviceService, accessRequestRepository, roleRepository, roleGrantRepository, permissionCacheRepository)
+	accessRequestHandler := handler.NewAccessRequestHandler(handlerHandler, accessRequestService)
+	httpServer := server.NewHTTPServer(logger, viperViper, jwtJWT, userHandler, tokenHandler, sessionHandler, twoFactorHandler, accountHandler, oidcHandler, apiKeyHandler, impersonationHandler, rbacHandler, roleHandler, permissionHandler, deptHandler, tenantHandler, roleGrantHandler, accessRequestHandler, userService, tokenService, apiKeyService, rbacService)
 	job := server.NewJob(logger)
-	appApp := newApp(httpServer, job)
+	routeSync := server.NewRouteSync(logger, viperViper, httpServer, permissionService)
+	appApp := newApp(httpServer, job, routeSync)
 	return appApp, func() {
 	}, nil
 }
 
 // wire.go:
 
-var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository)
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewTokenRepository, repository.NewRevocationRepository, repository.NewSessionRepository, repository.NewTwoFactorRepository, repository.NewLoginAttemptRepository, repository.NewPasswordHistoryRepository, repository.NewOIDCRepository, repository.NewApiKeyRepository, repository.NewImpersonationRepository, repository.NewPermissionCacheRepository, repository.NewRoleRepository, repository.NewPermissionRepository, repository.NewDeptRepository, repository.NewTenantRepository, repository.NewRoleGrantRepository, repository.NewAccessRequestRepository)
 
-var serviceSet = wire.NewSet(service.NewService, service.NewUserService)
+var serviceSet = wire.NewSet(service.NewService, service.NewUserService, service.NewTokenService, service.NewSessionService, service.NewTwoFactorService, service.NewAccountService, service.NewLoginAttemptService, service.NewPasswordService, service.NewOIDCService, service.NewApiKeyService, service.NewImpersonationService, service.NewRBACService, service.NewRoleService, service.NewPermissionService, service.NewDeptService, service.NewTenantService, service.NewRoleGrantService, service.NewAccessRequestService)
 
-var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler)
+var handlerSet = wire.NewSet(handler.NewHandler, handler.NewUserHandler, handler.NewTokenHandler, handler.NewSessionHandler, handler.NewTwoFactorHandler, handler.NewAccountHandler, handler.NewOIDCHandler, handler.NewApiKeyHandler, handler.NewImpersonationHandler, handler.NewRBACHandler, handler.NewRoleHandler, handler.NewPermissionHandler, handler.NewDeptHandler, handler.NewTenantHandler, handler.NewRoleGrantHandler, handler.NewAccessRequestHandler)
 
-var serverSet = wire.NewSet(server.NewHTTPServer, server.NewJob)
+var serverSet = wire.NewSet(server.NewHTTPServer, server.NewJob, server.NewRouteSync)
 
 // build App
-func newApp(httpServer *http.Server, job *server.Job) *app.App {
-	return app.NewApp(app.WithServer(httpServer, job), app.WithName("demo-server"))
+func newApp(httpServer *http.Server, job *server.Job, routeSync *server.RouteSync) *app.App {
+	return app.NewApp(app.WithServer(httpServer, job, routeSync), app.WithName("demo-server"))
 }
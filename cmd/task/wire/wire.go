@@ -4,14 +4,33 @@
 package wire
 
 import (
+	"admin-webrtc-go/internal/repository"
 	"admin-webrtc-go/internal/server"
+	"admin-webrtc-go/internal/service"
 	"admin-webrtc-go/pkg/app"
+	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
+	"admin-webrtc-go/pkg/sid"
 
 	"github.com/google/wire"
 	"github.com/spf13/viper"
 )
 
+var repositorySet = wire.NewSet(
+	repository.NewDB,
+	repository.NewRepository,
+	repository.NewTransaction,
+	repository.NewUserRepository,
+	repository.NewRoleRepository,
+	repository.NewPermissionCacheRepository,
+	repository.NewRoleGrantRepository,
+)
+
+var serviceSet = wire.NewSet(
+	service.NewService,
+	service.NewRoleGrantService,
+)
+
 var serverSet = wire.NewSet(
 	server.NewTask,
 )
@@ -26,7 +45,11 @@ func newApp(task *server.Task) *app.App {
 
 func NewWire(*viper.Viper, *log.Logger) (*app.App, func(), error) {
 	panic(wire.Build(
+		repositorySet,
+		serviceSet,
 		serverSet,
+		sid.NewSid,
+		jwt.NewJwt,
 		newApp,
 	))
 }
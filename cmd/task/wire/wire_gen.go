@@ -7,9 +7,13 @@
 package wire
 
 import (
+	"admin-webrtc-go/internal/repository"
 	"admin-webrtc-go/internal/server"
+	"admin-webrtc-go/internal/service"
 	"admin-webrtc-go/pkg/app"
+	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
+	"admin-webrtc-go/pkg/sid"
 	"github.com/google/wire"
 	"github.com/spf13/viper"
 )
@@ -17,7 +21,18 @@ import (
 // Injectors from wire.go:
 
 func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), error) {
-	task := server.NewTask(logger)
+	db := repository.NewDB(viperViper, logger)
+	repositoryRepository := repository.NewRepository(logger, db)
+	transaction := repository.NewTransaction(repositoryRepository)
+	sidSid := sid.NewSid()
+	jwtJWT := jwt.NewJwt(viperViper)
+	serviceService := service.NewService(transaction, logger, sidSid, jwtJWT)
+	roleGrantRepository := repository.NewRoleGrantRepository(repositoryRepository)
+	roleRepository := repository.NewRoleRepository(repositoryRepository)
+	userRepository := repository.NewUserRepository(repositoryRepository)
+	permissionCacheRepository := repository.NewPermissionCacheRepository(repositoryRepository, viperViper)
+	roleGrantService := service.NewRoleGrantService(serviceService, roleGrantRepository, roleRepository, userRepository, permissionCacheRepository)
+	task := server.NewTask(logger, roleGrantService)
 	appApp := newApp(task)
 	return appApp, func() {
 	}, nil
@@ -25,6 +40,10 @@ func NewWire(viperViper *viper.Viper, logger *log.Logger) (*app.App, func(), err
 
 // wire.go:
 
+var repositorySet = wire.NewSet(repository.NewDB, repository.NewRepository, repository.NewTransaction, repository.NewUserRepository, repository.NewRoleRepository, repository.NewPermissionCacheRepository, repository.NewRoleGrantRepository)
+
+var serviceSet = wire.NewSet(service.NewService, service.NewRoleGrantService)
+
 var serverSet = wire.NewSet(server.NewTask)
 
 // build App
@@ -24,13 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/getMenuTree": {
+        "/access-requests": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "需要拥有匹配该路由和方法的api权限；可按状态、申请人和角色过滤",
                 "consumes": [
                     "application/json"
                 ],
@@ -38,21 +39,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "角色申请模块"
+                ],
+                "summary": "分页查询角色申请",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 20,
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "roleId",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "userId",
+                        "in": "query"
+                    }
                 ],
-                "summary": "基于用户权限获取后台菜单",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponse"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListAccessRequestsResponse"
                         }
                     }
                 }
-            }
-        },
-        "/login": {
+            },
             "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "只能申请设置了审批人角色的角色；已拥有该角色或已有待审批的申请时不能再申请",
                 "consumes": [
                     "application/json"
                 ],
@@ -60,9 +103,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "角色申请模块"
                 ],
-                "summary": "账号登录",
+                "summary": "申请角色",
                 "parameters": [
                     {
                         "description": "params",
@@ -70,7 +113,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginRequest"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateAccessRequestRequest"
                         }
                     }
                 ],
@@ -78,15 +121,85 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponse"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestResponse"
                         }
                     }
                 }
             }
         },
-        "/register": {
+        "/access-requests/pending": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "申请的角色的审批人角色为当前用户拥有的角色（包括继承的角色和临时授权），不包括自己提交的申请；不能通过API密钥调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色申请模块"
+                ],
+                "summary": "获取待当前用户审批的角色申请",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/access-requests/{requestId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "包括每次状态变化的记录；只有申请人和审批人可以查看；不能通过API密钥调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色申请模块"
+                ],
+                "summary": "获取角色申请详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/access-requests/{requestId}/approve": {
             "post": {
-                "description": "目前只支持邮箱登录",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有申请的角色的审批人角色，不能审批自己的申请；批准后申请人立即获得角色，限时申请到期后自动失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -94,17 +207,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "角色申请模块"
                 ],
-                "summary": "用户注册",
+                "summary": "批准角色申请",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "params",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RegisterRequest"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DecideAccessRequestRequest"
                         }
                     }
                 ],
@@ -112,19 +231,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestResponse"
                         }
                     }
                 }
             }
         },
-        "/updateProfile": {
+        "/access-requests/{requestId}/cancel": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "只能撤回自己提交的待审批申请",
                 "consumes": [
                     "application/json"
                 ],
@@ -132,17 +252,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "角色申请模块"
                 ],
-                "summary": "更新用户信息",
+                "summary": "撤回角色申请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/access-requests/{requestId}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有申请的角色的审批人角色，不能审批自己的申请",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色申请模块"
+                ],
+                "summary": "拒绝角色申请",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "params",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UpdateProfileRequest"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DecideAccessRequestRequest"
                         }
                     }
                 ],
@@ -150,19 +313,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestResponse"
                         }
                     }
                 }
             }
         },
-        "/user": {
+        "/api-keys": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "包括已过期和已吊销的密钥；不能通过API密钥调用",
                 "consumes": [
                     "application/json"
                 ],
@@ -170,165 +334,4916 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "API密钥模块"
                 ],
-                "summary": "获取用户信息",
+                "summary": "获取当前用户的API密钥",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/admin-webrtc-go_api_v1.GetProfileResponse"
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListApiKeysResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "admin-webrtc-go_api_v1.GetMenuTreeResponse": {
-            "type": "object",
-            "properties": {
-                "children": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponse"
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "完整密钥只在创建时返回一次，调用接口时放在X-API-Key请求头中；不能通过API密钥调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API密钥模块"
+                ],
+                "summary": "创建个人API密钥",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateApiKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateApiKeyResponse"
+                        }
                     }
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "deletedAt": {
-                    "type": "string"
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "level": {
-                    "type": "integer"
-                },
-                "method": {
-                    "type": "string"
-                },
-                "parentId": {
-                    "type": "integer"
-                },
-                "path": {
-                    "type": "string"
-                },
-                "permissionName": {
-                    "type": "string"
-                },
-                "permissionType": {
-                    "type": "string"
-                },
-                "route": {
-                    "type": "string"
-                },
-                "routeFile": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                }
-            }
-        },
-        "admin-webrtc-go_api_v1.GetProfileResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "data": {
-                    "$ref": "#/definitions/admin-webrtc-go_api_v1.GetProfileResponseData"
-                },
-                "message": {
-                    "type": "string"
                 }
             }
         },
-        "admin-webrtc-go_api_v1.GetProfileResponseData": {
-            "type": "object",
-            "properties": {
-                "nickname": {
-                    "type": "string",
-                    "example": "alan"
-                },
-                "userId": {
-                    "type": "string"
+        "/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API密钥模块"
+                ],
+                "summary": "吊销当前用户的API密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "密钥ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
                 }
             }
         },
-        "admin-webrtc-go_api_v1.LoginRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
+        "/depts": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；负责人必须是已存在的用户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "创建部门",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateDeptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/depts/tree": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；包括停用的部门，同级部门按sort升序排列",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "获取部门树",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptTreeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/depts/{deptId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "获取部门详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "deptId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；修改上级部门时整个子树随之移动，不能移动到自身或下级部门下",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "修改部门",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "deptId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UpdateDeptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；部门还有下级部门或成员时不能删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "删除部门",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "deptId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/depts/{deptId}/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；只返回调用者角色数据范围内的用户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "分页获取部门成员",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "deptId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "包括下级部门的成员",
+                        "name": "includeChildren",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 20,
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListDeptUsersResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；用户只属于一个部门，加入后离开原部门；每次最多500个用户，任一用户不存在时全部不加入",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "批量将用户加入部门",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "deptId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；不属于该部门的用户会被忽略",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门模块"
+                ],
+                "summary": "将用户移出部门",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门ID",
+                        "name": "deptId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/verify": {
+            "post": {
+                "description": "提交验证邮件链接中的token，每个链接只能使用一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "账号模块"
+                ],
+                "summary": "验证邮箱",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/verify/send": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "账号模块"
+                ],
+                "summary": "重新发送邮箱验证邮件",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/getMenuTree": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "基于用户权限获取后台菜单",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonation/stop": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "使用模拟令牌调用，吊销该令牌；管理员自己的令牌不受影响",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "模拟登录模块"
+                ],
+                "summary": "结束模拟登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；返回最近的100条记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "模拟登录模块"
+                ],
+                "summary": "管理员查询模拟登录审计记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "只返回该用户发起或被模拟的记录",
+                        "name": "userId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListImpersonationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "账号登录",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/2fa": {
+            "post": {
+                "description": "提交登录返回的challengeToken和验证码(或恢复码)换取访问令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "两步验证登录",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/2fa/setup": {
+            "post": {
+                "description": "角色要求两步验证但尚未绑定时，凭登录返回的challengeToken生成密钥，再调用/login/2fa完成绑定和登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "登录时绑定两步验证",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorLoginSetupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorSetupResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "吊销当前访问令牌并结束所属会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "退出登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout/all": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "吊销当前用户已签发的全部访问令牌和刷新令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "退出所有会话",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/oidc/authorize": {
+            "get": {
+                "description": "返回身份提供方的授权地址，前端跳转后由身份提供方回调到配置的redirect_url，再调用/oidc/callback完成登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "发起单点登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.OIDCAuthorizationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/oidc/callback": {
+            "post": {
+                "description": "提交身份提供方回调地址中的code和state换取访问令牌，首次登录时关联或创建本地用户，并按用户组同步角色；与密码登录相同受登录锁定限制，需要两步验证时返回质询令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "单点登录回调",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.OIDCCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/change": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "新密码须符合密码策略且不能与最近使用过的密码相同，成功后该用户的全部会话失效，需要重新登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "账号模块"
+                ],
+                "summary": "修改密码",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "向邮箱发送重置密码链接，无论邮箱是否注册都返回成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "账号模块"
+                ],
+                "summary": "忘记密码",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "提交重置密码链接中的token和新密码，成功后该用户的全部会话失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "账号模块"
+                ],
+                "summary": "重置密码",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；父节点必须是菜单，层级根据父节点计算",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "创建权限节点",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreatePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.PermissionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/sort": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；ids必须正好是父节点下的全部子节点",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "调整同级权限节点的顺序",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.SortPermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/tree": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；包括菜单、按钮和api类型的全部节点，同级节点按sort升序排列",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "获取完整的权限树",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.PermissionTreeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/{permissionId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；有子节点的菜单不能修改为其他类型，修改父节点使用移动接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "修改权限节点",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "权限ID",
+                        "name": "permissionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UpdatePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.PermissionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；有子节点时不能删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "删除权限节点",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "权限ID",
+                        "name": "permissionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/{permissionId}/move": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；子孙节点随之移动，不能移动到自身或子孙节点下",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "移动权限节点",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "权限ID",
+                        "name": "permissionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.MovePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/rbac/cache": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；统计为当前实例启动以来的累计值",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "管理员查看权限缓存统计",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.PermissionCacheStatsResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；直接修改数据库中的角色或权限后调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "管理员清除权限缓存",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/rbac/explain": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；按RBACAuth相同的规则评估，返回结果及匹配的角色、权限和deny规则，不经过权限缓存。path为实际请求路径或已注册的路由模板，旧版/:api分组中的路由需要使用实际请求路径。dryRun不为空时按调整后的角色评估，不会保存，同时返回当前角色的结果",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限模块"
+                ],
+                "summary": "解释用户对接口的访问权限",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainPermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainPermissionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "目前只支持邮箱登录，密码须符合密码策略，不符合时data.violations列出未通过的规则",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "用户注册",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；deleted为true时只返回已删除的角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "分页获取角色列表",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "只查询已删除的角色，用于恢复",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "按标识或名称模糊查询",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 20,
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListRolesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；角色标识不能与已有角色重复，包括已删除的角色；设置父角色后继承其全部权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "创建角色",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{roleId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；包括角色关联的权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "获取角色详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；默认角色不能修改标识，父角色不能是自身或继承自该角色的角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "修改角色",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；软删除，可以恢复，默认角色不能删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "删除角色",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{roleId}/permissions": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；effect为deny时拒绝这些权限，优先于用户其他角色的allow，被拒绝的菜单及其子菜单不会出现在菜单树中；已关联的权限更新为新的effect",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "为角色关联权限",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RolePermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "取消角色关联的权限",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RolePermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{roleId}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；恢复后原有的用户和权限关联重新生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "恢复已删除的角色",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{roleId}/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；只返回调用者角色数据范围内的用户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "分页获取拥有该角色的用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 20,
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListRoleUsersResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；每次最多500个用户，任一用户不存在时全部不关联",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "批量为用户关联角色",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AssignRoleUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-accounts": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；服务账号不能登录，只能通过API密钥调用接口，只能授予自己拥有的角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API密钥模块"
+                ],
+                "summary": "管理员创建服务账号",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateServiceAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ServiceAccountResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-accounts/{userId}/api-keys": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；完整密钥只在创建时返回一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API密钥模块"
+                ],
+                "summary": "管理员为服务账号创建API密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账号ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateApiKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateApiKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "不能通过API密钥调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "会话模块"
+                ],
+                "summary": "获取当前用户的登录会话",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListSessionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "会话模块"
+                ],
+                "summary": "结束当前用户的指定会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户模块"
+                ],
+                "summary": "分页获取租户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "按标识或名称模糊查询",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 20,
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListTenantsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；同时创建租户的默认角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户模块"
+                ],
+                "summary": "创建租户",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TenantResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants/{tenantId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户模块"
+                ],
+                "summary": "获取租户详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "租户ID",
+                        "name": "tenantId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TenantResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；租户标识不能修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户模块"
+                ],
+                "summary": "修改租户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "租户ID",
+                        "name": "tenantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UpdateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TenantResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；租户还有用户时不能删除，其角色和部门一并删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户模块"
+                ],
+                "summary": "删除租户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "租户ID",
+                        "name": "tenantId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants/{tenantId}/users": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；用户离开原部门，原有角色替换为新租户的默认角色，并注销全部会话；每次最多500个用户，任一用户不存在时全部不移动",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户模块"
+                ],
+                "summary": "批量将用户移入租户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "租户ID",
+                        "name": "tenantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.MoveTenantUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "使用刷新令牌换取新的访问令牌，旧的刷新令牌随即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "刷新访问令牌",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RefreshTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/updateProfile": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "更新用户信息",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "获取用户信息",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.GetProfileResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "校验验证码后启用两步验证，返回的恢复码仅展示一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "两步验证模块"
+                ],
+                "summary": "确认绑定两步验证",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RecoveryCodesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "角色要求两步验证时不能关闭",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "两步验证模块"
+                ],
+                "summary": "关闭两步验证",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "旧的恢复码全部作废",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "两步验证模块"
+                ],
+                "summary": "重新生成恢复码",
+                "parameters": [
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RecoveryCodesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "返回密钥和otpauth链接，调用/user/2fa/confirm校验验证码后生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "两步验证模块"
+                ],
+                "summary": "生成两步验证密钥",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorSetupResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/access-requests": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "最多返回最近100条；不能通过API密钥调用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色申请模块"
+                ],
+                "summary": "获取当前用户的角色申请",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API密钥模块"
+                ],
+                "summary": "管理员获取指定用户或服务账号的API密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListApiKeysResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API密钥模块"
+                ],
+                "summary": "管理员吊销指定用户或服务账号的API密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "密钥ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；返回的令牌以被模拟用户的身份访问，不能刷新，也不能修改密码、两步验证等账号安全设置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "模拟登录模块"
+                ],
+                "summary": "模拟指定用户登录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "被模拟的用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ImpersonateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ImpersonationTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/lock": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "管理员解除账号登录锁定",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/role-grants": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；默认只返回还未结束的授权，最多返回最近100条",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "获取用户的临时授权",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "同时返回已结束的授权",
+                        "name": "ended",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListRoleGrantsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；授权只在开始和结束时间之间生效，到期后自动失效并保留记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "限时授予用户角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateRoleGrantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleGrantResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/role-grants/{grantId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；提前结束授权并记录撤销人",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "撤销用户的临时授权",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "授权ID",
+                        "name": "grantId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "获取用户的角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UserRolesResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；用roleIds替换用户现有的全部角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "设置用户的角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.SetUserRolesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限；已拥有的角色忽略",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "为用户添加角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UserRolesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色模块"
+                ],
+                "summary": "移除用户的角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.UserRolesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "会话模块"
+                ],
+                "summary": "管理员获取指定用户的登录会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ListSessionsResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "会话模块"
+                ],
+                "summary": "管理员强制结束用户的全部会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "需要拥有匹配该路由和方法的api权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "会话模块"
+                ],
+                "summary": "管理员强制结束会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin-webrtc-go_api_v1.AccessRequestData": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "decidedAt": {
+                    "type": "string"
+                },
+                "decidedBy": {
+                    "type": "string"
+                },
+                "durationHours": {
+                    "type": "integer"
+                },
+                "events": {
+                    "description": "只在查询单个申请时返回",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestEventData"
+                    }
+                },
+                "grantId": {
+                    "description": "限时申请批准后创建的临时授权",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "roleId": {
+                    "type": "integer"
+                },
+                "roleLabel": {
+                    "type": "string"
+                },
+                "roleName": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending、approved、rejected或cancelled",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.AccessRequestEventData": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "fromStatus": {
+                    "description": "创建申请时为空",
+                    "type": "string"
+                },
+                "toStatus": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.AccessRequestResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.AccessRequestsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ApiKeyData": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "keyId": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "密钥开头的字符，便于辨认",
+                    "type": "string",
+                    "example": "ak_3fK9"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ApiKeyScope"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ApiKeyScope": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "string"
+                },
+                "permissionId": {
+                    "type": "integer"
+                },
+                "permissionName": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.AssignRoleUsersRequest": {
+            "type": "object",
+            "required": [
+                "userIds"
+            ],
+            "properties": {
+                "userIds": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "oldPassword"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "example": "N3w-passw0rd"
+                },
+                "oldPassword": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateAccessRequestRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "roleId"
+            ],
+            "properties": {
+                "durationHours": {
+                    "description": "为空表示长期拥有",
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 1,
+                    "example": 8
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "负责本周的线上值班"
+                },
+                "roleId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateApiKeyData": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "完整密钥仅在创建时返回一次，调用接口时放在X-API-Key请求头中",
+                    "type": "string"
+                },
+                "keyId": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "密钥开头的字符，便于辨认",
+                    "type": "string",
+                    "example": "ak_3fK9"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ApiKeyScope"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateApiKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expiresInDays": {
+                    "description": "0表示使用默认有效期",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 90
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "ci-deploy"
+                },
+                "scopes": {
+                    "description": "可访问的api权限ID，不能超出所属用户拥有的权限",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.CreateApiKeyData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateDeptRequest": {
+            "type": "object",
+            "required": [
+                "deptName"
+            ],
+            "properties": {
+                "deptName": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "研发部"
+                },
+                "leaderId": {
+                    "description": "负责人的用户ID，为空表示未指定",
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "0表示顶级部门",
+                    "type": "integer"
+                },
+                "sort": {
+                    "description": "同级部门按升序排列",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "为空时为enabled",
+                    "type": "string",
+                    "enum": [
+                        "enabled",
+                        "disabled"
+                    ],
+                    "example": "enabled"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreatePermissionRequest": {
+            "type": "object",
+            "required": [
+                "permissionName",
+                "permissionType"
+            ],
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "method": {
+                    "description": "多个用逗号分隔，all或为空表示全部方法",
+                    "type": "string",
+                    "example": "GET,POST"
+                },
+                "parentId": {
+                    "description": "0表示根节点，父节点必须是菜单",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "api类型必填",
+                    "type": "string",
+                    "example": "/v1/users/*"
+                },
+                "permissionName": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "用户管理"
+                },
+                "permissionType": {
+                    "type": "string",
+                    "enum": [
+                        "menu",
+                        "button",
+                        "api"
+                    ],
+                    "example": "menu"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/system/user"
+                },
+                "routeFile": {
+                    "type": "string",
+                    "example": "views/system/user/index.vue"
+                },
+                "sort": {
+                    "description": "为空时排在同级节点最后",
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateRoleGrantRequest": {
+            "type": "object",
+            "required": [
+                "endsAt",
+                "reason",
+                "roleId"
+            ],
+            "properties": {
+                "endsAt": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "值班处理故障#2048"
+                },
+                "roleId": {
+                    "type": "integer"
+                },
+                "startsAt": {
+                    "description": "为空时立即生效",
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "roleLabel",
+                "roleName"
+            ],
+            "properties": {
+                "approverRoleId": {
+                    "description": "拥有该角色的用户可以审批申请本角色的请求，0表示不能申请",
+                    "type": "integer"
+                },
+                "dataScope": {
+                    "description": "数据范围：all全部、dept本部门、dept_and_children本部门及下级、self仅本人、custom自定义部门，为空时为all",
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "dept",
+                        "dept_and_children",
+                        "self",
+                        "custom"
+                    ],
+                    "example": "all"
+                },
+                "dataScopeDeptIds": {
+                    "description": "dataScope为custom时可以查看的部门",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "parentId": {
+                    "description": "继承该角色的全部权限，0表示没有父角色",
+                    "type": "integer"
+                },
+                "roleLabel": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "ops"
+                },
+                "roleName": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "运维"
+                },
+                "twoFactor": {
+                    "description": "拥有该角色的用户必须启用两步验证",
+                    "type": "boolean"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateServiceAccountRequest": {
+            "type": "object",
+            "required": [
+                "nickname"
+            ],
+            "properties": {
+                "nickname": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "deploy-bot"
+                },
+                "roles": {
+                    "description": "角色标识，服务账号的API密钥权限不能超出这些角色的权限",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.CreateTenantRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "description": "创建后不能修改，删除后也不能复用",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "acme"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Acme"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.DecideAccessRequestRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.DeptData": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "只在部门树中返回",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptData"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deptName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "leaderId": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.DeptResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.DeptTreeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.DeptUserData": {
+            "type": "object",
+            "properties": {
+                "deptId": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "serviceAccount": {
+                    "type": "boolean"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.DeptUsersRequest": {
+            "type": "object",
+            "required": [
+                "userIds"
+            ],
+            "properties": {
+                "userIds": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainDecisionData": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "denyRules": {
+                    "description": "匹配的deny规则，存在时优先于allow",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainRuleData"
+                    }
+                },
+                "permissions": {
+                    "description": "匹配的allow权限",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainRuleData"
+                    }
+                },
+                "reason": {
+                    "description": "deny_rule、allow_rule、no_match或no_roles",
+                    "type": "string",
+                    "example": "allow_rule"
+                },
+                "roles": {
+                    "description": "用户的全部角色，包括继承的角色",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainRoleData"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainDryRunRequest": {
+            "type": "object",
+            "properties": {
+                "addRoleIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "removeRoleIds": {
+                    "description": "同时移除直接分配的角色和临时授权",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainPermissionData": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "演练时当前角色的结果",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainDecisionData"
+                        }
+                    ]
+                },
+                "decision": {
+                    "description": "演练时为调整后角色的结果",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainDecisionData"
+                        }
+                    ]
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "route": {
+                    "description": "匹配到的路由模板",
+                    "type": "string",
+                    "example": "/v1/roles/:roleId"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainPermissionRequest": {
+            "type": "object",
+            "required": [
+                "method",
+                "path",
+                "userId"
+            ],
+            "properties": {
+                "dryRun": {
+                    "description": "不为空时按调整后的角色评估，不会保存",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainDryRunRequest"
+                        }
+                    ]
+                },
+                "method": {
+                    "type": "string",
+                    "example": "DELETE"
+                },
+                "path": {
+                    "description": "实际请求路径或已注册的路由模板",
+                    "type": "string",
+                    "example": "/v1/roles/3"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainPermissionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ExplainPermissionData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainRoleData": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "roleLabel": {
+                    "type": "string"
+                },
+                "roleName": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "direct直接分配，grant临时授权，proposed演练中新增，inherited继承自父角色",
+                    "type": "string",
+                    "example": "direct"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ExplainRuleData": {
+            "type": "object",
+            "properties": {
+                "effect": {
+                    "type": "string",
+                    "example": "allow"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "permissionId": {
+                    "type": "integer"
+                },
+                "permissionName": {
+                    "type": "string"
+                },
+                "roleIds": {
+                    "description": "关联该权限的角色",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "1234@gmail.com"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.GetMenuTreeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.GetMenuTreeResponseData": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponseData"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "permission_type": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "route_file": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.GetProfileResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.GetProfileResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.GetProfileResponseData": {
+            "type": "object",
+            "properties": {
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "nickname": {
+                    "type": "string",
+                    "example": "alan"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ImpersonateRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "记录到审计日志",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "排查工单#1024菜单显示异常"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ImpersonationData": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "endedAt": {
+                    "description": "为空表示未主动结束",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ImpersonationTokenData": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "description": "以被模拟用户的身份访问，不能刷新",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 1800
+                },
+                "userId": {
+                    "description": "被模拟的用户",
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ImpersonationTokenResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ImpersonationTokenData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListAccessRequestsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ListAccessRequestsResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListAccessRequestsResponseData": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.AccessRequestData"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListApiKeysResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ApiKeyData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListDeptUsersResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ListDeptUsersResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListDeptUsersResponseData": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.DeptUserData"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListImpersonationsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.ImpersonationData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListRoleGrantsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleGrantData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListRoleUsersResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ListRoleUsersResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListRoleUsersResponseData": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleUserData"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListRolesResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ListRolesResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListRolesResponseData": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleData"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.SessionData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListTenantsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ListTenantsResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ListTenantsResponseData": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.TenantData"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "device": {
+                    "description": "可选，用于会话列表展示",
+                    "type": "string",
+                    "example": "MacBook Pro"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "1234@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.LoginResponseData": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                },
+                "challengeToken": {
+                    "type": "string"
+                },
+                "expiresIn": {
+                    "description": "访问令牌有效期(秒)",
+                    "type": "integer",
+                    "example": 900
+                },
+                "recoveryCodes": {
+                    "description": "登录时完成绑定才会返回，仅展示一次",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "twoFactorRequired": {
+                    "description": "需要两步验证时不返回令牌，客户端携带challengeToken调用/login/2fa完成登录",
+                    "type": "boolean"
+                },
+                "twoFactorSetupRequired": {
+                    "description": "角色要求两步验证，需先调用/login/2fa/setup绑定",
+                    "type": "boolean"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.MovePermissionRequest": {
+            "type": "object",
+            "properties": {
+                "parentId": {
+                    "description": "0表示移动到根节点",
+                    "type": "integer"
+                },
+                "sort": {
+                    "description": "为空时排在新的同级节点最后",
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.MoveTenantUsersRequest": {
+            "type": "object",
+            "required": [
+                "userIds"
+            ],
+            "properties": {
+                "userIds": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.OIDCAuthorizationData": {
+            "type": "object",
+            "properties": {
+                "authorizationUrl": {
+                    "description": "前端跳转到该地址完成身份提供方登录",
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.OIDCAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.OIDCAuthorizationData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.OIDCCallbackRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "description": "身份提供方回调地址中的code",
+                    "type": "string"
+                },
+                "device": {
+                    "type": "string",
+                    "example": "MacBook Pro"
+                },
+                "state": {
+                    "description": "身份提供方回调地址中的state",
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.PermissionCacheStatsData": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "进程内缓存的容量上限",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "evictions": {
+                    "description": "超出容量被淘汰的次数",
+                    "type": "integer"
+                },
+                "hitRate": {
+                    "type": "number",
+                    "example": 0.98
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "invalidations": {
+                    "description": "角色或权限变化导致的清除次数",
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "redis": {
+                    "description": "是否通过redis在多实例间共享",
+                    "type": "boolean"
+                },
+                "size": {
+                    "description": "进程内缓存的用户数",
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.PermissionCacheStatsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.PermissionCacheStatsData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.PermissionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.PermissionTreeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.GetMenuTreeResponseData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RecoveryCodesData": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "description": "仅展示一次，每个只能使用一次",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.RecoveryCodesData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RefreshTokenResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponseData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "1234@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "token": {
+                    "description": "邮件链接中的token",
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RoleData": {
+            "type": "object",
+            "properties": {
+                "approverRoleId": {
+                    "description": "审批申请本角色的请求的角色，0表示不能申请",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "dataScope": {
+                    "type": "string"
+                },
+                "dataScopeDeptIds": {
+                    "description": "以下两项只在查询单个角色时返回",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "parentId": {
+                    "type": "integer"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.RolePermissionData"
+                    }
+                },
+                "roleLabel": {
+                    "type": "string"
+                },
+                "roleName": {
+                    "type": "string"
+                },
+                "twoFactor": {
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RoleGrantData": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "endReason": {
+                    "description": "expired到期或revoked撤销",
+                    "type": "string"
+                },
+                "endedAt": {
+                    "description": "为空表示还未结束",
+                    "type": "string"
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "grantedBy": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "revokedBy": {
+                    "type": "string"
+                },
+                "roleId": {
+                    "type": "integer"
+                },
+                "roleLabel": {
+                    "type": "string"
+                },
+                "roleName": {
+                    "type": "string"
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RoleGrantResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleGrantData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RolePermissionData": {
+            "type": "object",
+            "properties": {
+                "effect": {
+                    "description": "deny优先于其他角色的allow",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "permissionName": {
+                    "type": "string"
+                },
+                "permissionType": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RolePermissionsRequest": {
+            "type": "object",
+            "required": [
+                "permissionIds"
+            ],
+            "properties": {
+                "effect": {
+                    "description": "取消关联时忽略；为空时按allow处理",
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "allow"
+                },
+                "permissionIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RoleResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.RoleUserData": {
+            "type": "object",
+            "properties": {
                 "email": {
-                    "type": "string",
-                    "example": "1234@gmail.com"
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "123456"
+                "nickname": {
+                    "type": "string"
+                },
+                "serviceAccount": {
+                    "type": "boolean"
+                },
+                "userId": {
+                    "type": "string"
                 }
             }
         },
-        "admin-webrtc-go_api_v1.LoginResponse": {
+        "admin-webrtc-go_api_v1.ServiceAccountData": {
+            "type": "object",
+            "properties": {
+                "nickname": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.ServiceAccountResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
                 "data": {
-                    "$ref": "#/definitions/admin-webrtc-go_api_v1.LoginResponseData"
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.ServiceAccountData"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "admin-webrtc-go_api_v1.LoginResponseData": {
+        "admin-webrtc-go_api_v1.SessionData": {
             "type": "object",
             "properties": {
-                "accessToken": {
+                "current": {
+                    "description": "是否为发起请求的会话",
+                    "type": "boolean"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "issuedAt": {
+                    "type": "string"
+                },
+                "lastSeenAt": {
+                    "type": "string"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "admin-webrtc-go_api_v1.RegisterRequest": {
+        "admin-webrtc-go_api_v1.SetUserRolesRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "roleIds"
             ],
             "properties": {
-                "email": {
+                "roleIds": {
+                    "description": "为空数组时移除用户的全部角色",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.SortPermissionsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "按新的顺序排列的全部同级节点",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "parentId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.TenantData": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.TenantResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.TenantData"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.TwoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "验证码或恢复码",
                     "type": "string",
-                    "example": "1234@gmail.com"
+                    "example": "123456"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.TwoFactorLoginRequest": {
+            "type": "object",
+            "required": [
+                "challengeToken",
+                "code"
+            ],
+            "properties": {
+                "challengeToken": {
+                    "type": "string"
                 },
-                "password": {
+                "code": {
+                    "description": "验证码或恢复码",
                     "type": "string",
                     "example": "123456"
+                },
+                "device": {
+                    "type": "string",
+                    "example": "MacBook Pro"
                 }
             }
         },
-        "admin-webrtc-go_api_v1.Response": {
+        "admin-webrtc-go_api_v1.TwoFactorLoginSetupRequest": {
+            "type": "object",
+            "required": [
+                "challengeToken"
+            ],
+            "properties": {
+                "challengeToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.TwoFactorSetupData": {
+            "type": "object",
+            "properties": {
+                "otpauthUri": {
+                    "description": "用于生成二维码",
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.TwoFactorSetupResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
-                "data": {},
+                "data": {
+                    "$ref": "#/definitions/admin-webrtc-go_api_v1.TwoFactorSetupData"
+                },
                 "message": {
                     "type": "string"
                 }
             }
         },
+        "admin-webrtc-go_api_v1.UpdateDeptRequest": {
+            "type": "object",
+            "required": [
+                "deptName"
+            ],
+            "properties": {
+                "deptName": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "研发部"
+                },
+                "leaderId": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "修改后整个子树随之移动",
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "enabled",
+                        "disabled"
+                    ],
+                    "example": "enabled"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.UpdatePermissionRequest": {
+            "type": "object",
+            "required": [
+                "permissionName",
+                "permissionType"
+            ],
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET,POST"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/v1/users/*"
+                },
+                "permissionName": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "用户管理"
+                },
+                "permissionType": {
+                    "type": "string",
+                    "enum": [
+                        "menu",
+                        "button",
+                        "api"
+                    ],
+                    "example": "menu"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/system/user"
+                },
+                "routeFile": {
+                    "type": "string",
+                    "example": "views/system/user/index.vue"
+                },
+                "sort": {
+                    "type": "string"
+                }
+            }
+        },
         "admin-webrtc-go_api_v1.UpdateProfileRequest": {
             "type": "object",
             "required": [
@@ -344,9 +5259,129 @@ const docTemplate = `{
                     "example": "alan"
                 }
             }
+        },
+        "admin-webrtc-go_api_v1.UpdateRoleRequest": {
+            "type": "object",
+            "required": [
+                "roleLabel",
+                "roleName"
+            ],
+            "properties": {
+                "approverRoleId": {
+                    "type": "integer"
+                },
+                "dataScope": {
+                    "description": "与创建时相同，dataScope不是custom时清空自定义部门",
+                    "type": "string",
+                    "enum": [
+                        "all",
+                        "dept",
+                        "dept_and_children",
+                        "self",
+                        "custom"
+                    ],
+                    "example": "all"
+                },
+                "dataScopeDeptIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "parentId": {
+                    "type": "integer"
+                },
+                "roleLabel": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "ops"
+                },
+                "roleName": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "运维"
+                },
+                "twoFactor": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.UpdateTenantRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Acme"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.UserRolesRequest": {
+            "type": "object",
+            "required": [
+                "roleIds"
+            ],
+            "properties": {
+                "roleIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.UserRolesResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin-webrtc-go_api_v1.RoleData"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin-webrtc-go_api_v1.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "description": "邮件链接中的token",
+                    "type": "string"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "ApiKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "Bearer": {
             "type": "apiKey",
             "name": "Authorization",
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type AccessRequestHandler struct {
+	*Handler
+	accessRequestService service.AccessRequestService
+}
+
+func NewAccessRequestHandler(handler *Handler, accessRequestService service.AccessRequestService) *AccessRequestHandler {
+	return &AccessRequestHandler{
+		Handler:              handler,
+		accessRequestService: accessRequestService,
+	}
+}
+
+// CreateAccessRequest godoc
+// @Summary 申请角色
+// @Schemes
+// @Description 只能申请设置了审批人角色的角色；已拥有该角色或已有待审批的申请时不能再申请
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateAccessRequestRequest true "params"
+// @Success 200 {object} v1.AccessRequestResponse
+// @Router /access-requests [post]
+func (h *AccessRequestHandler) CreateAccessRequest(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	var req v1.CreateAccessRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.accessRequestService.Create(ctx, userId, &req)
+	if err != nil {
+		h.handleAccessRequestError(ctx, "accessRequestService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListMyAccessRequests godoc
+// @Summary 获取当前用户的角色申请
+// @Schemes
+// @Description 最多返回最近100条；不能通过API密钥调用
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.AccessRequestsResponse
+// @Router /user/access-requests [get]
+func (h *AccessRequestHandler) ListMyAccessRequests(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	data, err := h.accessRequestService.ListMine(ctx, userId)
+	if err != nil {
+		h.handleAccessRequestError(ctx, "accessRequestService.ListMine error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListPendingAccessRequests godoc
+// @Summary 获取待当前用户审批的角色申请
+// @Schemes
+// @Description 申请的角色的审批人角色为当前用户拥有的角色（包括继承的角色和临时授权），不包括自己提交的申请；不能通过API密钥调用
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.AccessRequestsResponse
+// @Router /access-requests/pending [get]
+func (h *AccessRequestHandler) ListPendingAccessRequests(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	data, err := h.accessRequestService.ListPending(ctx, userId)
+	if err != nil {
+		h.handleAccessRequestError(ctx, "accessRequestService.ListPending error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetAccessRequest godoc
+// @Summary 获取角色申请详情
+// @Schemes
+// @Description 包括每次状态变化的记录；只有申请人和审批人可以查看；不能通过API密钥调用
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param requestId path int true "申请ID"
+// @Success 200 {object} v1.AccessRequestResponse
+// @Router /access-requests/{requestId} [get]
+func (h *AccessRequestHandler) GetAccessRequest(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	requestId, ok := h.requestId(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := h.accessRequestService.Get(ctx, userId, requestId)
+	if err != nil {
+		h.handleAccessRequestError(ctx, "accessRequestService.Get error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ApproveAccessRequest godoc
+// @Summary 批准角色申请
+// @Schemes
+// @Description 需要拥有申请的角色的审批人角色，不能审批自己的申请；批准后申请人立即获得角色，限时申请到期后自动失效
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param requestId path int true "申请ID"
+// @Param request body v1.DecideAccessRequestRequest false "params"
+// @Success 200 {object} v1.AccessRequestResponse
+// @Router /access-requests/{requestId}/approve [post]
+func (h *AccessRequestHandler) ApproveAccessRequest(ctx *gin.Context) {
+	h.decide(ctx, h.accessRequestService.Approve, "accessRequestService.Approve error")
+}
+
+// RejectAccessRequest godoc
+// @Summary 拒绝角色申请
+// @Schemes
+// @Description 需要拥有申请的角色的审批人角色，不能审批自己的申请
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param requestId path int true "申请ID"
+// @Param request body v1.DecideAccessRequestRequest false "params"
+// @Success 200 {object} v1.AccessRequestResponse
+// @Router /access-requests/{requestId}/reject [post]
+func (h *AccessRequestHandler) RejectAccessRequest(ctx *gin.Context) {
+	h.decide(ctx, h.accessRequestService.Reject, "accessRequestService.Reject error")
+}
+
+// CancelAccessRequest godoc
+// @Summary 撤回角色申请
+// @Schemes
+// @Description 只能撤回自己提交的待审批申请
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param requestId path int true "申请ID"
+// @Success 200 {object} v1.AccessRequestResponse
+// @Router /access-requests/{requestId}/cancel [post]
+func (h *AccessRequestHandler) CancelAccessRequest(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	requestId, ok := h.requestId(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := h.accessRequestService.Cancel(ctx, userId, requestId)
+	if err != nil {
+		h.handleAccessRequestError(ctx, "accessRequestService.Cancel error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListAccessRequests godoc
+// @Summary 分页查询角色申请
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；可按状态、申请人和角色过滤
+// @Tags 角色申请模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request query v1.ListAccessRequestsRequest false "params"
+// @Success 200 {object} v1.ListAccessRequestsResponse
+// @Router /access-requests [get]
+func (h *AccessRequestHandler) ListAccessRequests(ctx *gin.Context) {
+	var req v1.ListAccessRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.accessRequestService.List(ctx, &req)
+	if err != nil {
+		h.handleAccessRequestError(ctx, "accessRequestService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// decide 批准和拒绝共用的处理，请求体可以为空
+func (h *AccessRequestHandler) decide(ctx *gin.Context, decide func(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error), msg string) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	requestId, ok := h.requestId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.DecideAccessRequestRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+			return
+		}
+	}
+
+	data, err := decide(ctx, userId, requestId, req.Comment)
+	if err != nil {
+		h.handleAccessRequestError(ctx, msg, err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+func (h *AccessRequestHandler) requestId(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("requestId"), 10, 64)
+	if err != nil || id == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *AccessRequestHandler) handleAccessRequestError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrAccessRequestForbidden), errors.Is(err, v1.ErrAccessRequestSelfApproval):
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+	case errors.Is(err, v1.ErrAccessRequestDecided), errors.Is(err, v1.ErrAccessRequestPending), errors.Is(err, v1.ErrRoleAlreadyAssigned):
+		v1.HandleError(ctx, http.StatusConflict, err, nil)
+	case errors.Is(err, v1.ErrRoleNotFound), errors.Is(err, v1.ErrRoleNotRequestable):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
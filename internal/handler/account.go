@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type AccountHandler struct {
+	*Handler
+	accountService service.AccountService
+}
+
+func NewAccountHandler(handler *Handler, accountService service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		Handler:        handler,
+		accountService: accountService,
+	}
+}
+
+// SendVerifyEmail godoc
+// @Summary 重新发送邮箱验证邮件
+// @Schemes
+// @Description
+// @Tags 账号模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.Response
+// @Router /email/verify/send [post]
+func (h *AccountHandler) SendVerifyEmail(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.accountService.SendVerifyEmail(ctx, userId); err != nil {
+		h.handleAccountError(ctx, "accountService.SendVerifyEmail error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// VerifyEmail godoc
+// @Summary 验证邮箱
+// @Schemes
+// @Description 提交验证邮件链接中的token，每个链接只能使用一次
+// @Tags 账号模块
+// @Accept json
+// @Produce json
+// @Param request body v1.VerifyEmailRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /email/verify [post]
+func (h *AccountHandler) VerifyEmail(ctx *gin.Context) {
+	var req v1.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(ctx, req.Token); err != nil {
+		h.handleAccountError(ctx, "accountService.VerifyEmail error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ForgotPassword godoc
+// @Summary 忘记密码
+// @Schemes
+// @Description 向邮箱发送重置密码链接，无论邮箱是否注册都返回成功
+// @Tags 账号模块
+// @Accept json
+// @Produce json
+// @Param request body v1.ForgotPasswordRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /password/forgot [post]
+func (h *AccountHandler) ForgotPassword(ctx *gin.Context) {
+	var req v1.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(ctx, req.Email); err != nil {
+		h.handleAccountError(ctx, "accountService.ForgotPassword error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ResetPassword godoc
+// @Summary 重置密码
+// @Schemes
+// @Description 提交重置密码链接中的token和新密码，成功后该用户的全部会话失效
+// @Tags 账号模块
+// @Accept json
+// @Produce json
+// @Param request body v1.ResetPasswordRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /password/reset [post]
+func (h *AccountHandler) ResetPassword(ctx *gin.Context) {
+	var req v1.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.accountService.ResetPassword(ctx, &req); err != nil {
+		h.handleAccountError(ctx, "accountService.ResetPassword error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ChangePassword godoc
+// @Summary 修改密码
+// @Schemes
+// @Description 新密码须符合密码策略且不能与最近使用过的密码相同，成功后该用户的全部会话失效，需要重新登录
+// @Tags 账号模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.ChangePasswordRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /password/change [post]
+func (h *AccountHandler) ChangePassword(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.accountService.ChangePassword(ctx, userId, &req); err != nil {
+		h.handleAccountError(ctx, "accountService.ChangePassword error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// handlePasswordPolicyError 密码不符合策略时返回400，data中列出未通过的规则
+func handlePasswordPolicyError(ctx *gin.Context, err error) bool {
+	var policyErr *service.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	v1.HandleError(ctx, http.StatusBadRequest, v1.ErrPasswordPolicy, map[string]interface{}{"violations": policyErr.Violations})
+	return true
+}
+
+func (h *AccountHandler) handleAccountError(ctx *gin.Context, msg string, err error) {
+	if handlePasswordPolicyError(ctx, err) {
+		return
+	}
+	if errors.Is(err, v1.ErrLinkInvalid) || errors.Is(err, v1.ErrEmailAlreadyVerified) || errors.Is(err, v1.ErrPasswordIncorrect) {
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		return
+	}
+	h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+	v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+}
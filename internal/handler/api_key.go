@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/jwt"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ApiKeyHandler struct {
+	*Handler
+	apiKeyService service.ApiKeyService
+}
+
+func NewApiKeyHandler(handler *Handler, apiKeyService service.ApiKeyService) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		Handler:       handler,
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateApiKey godoc
+// @Summary 创建个人API密钥
+// @Schemes
+// @Description 完整密钥只在创建时返回一次，调用接口时放在X-API-Key请求头中；不能通过API密钥调用
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateApiKeyRequest true "params"
+// @Success 200 {object} v1.CreateApiKeyResponse
+// @Router /api-keys [post]
+func (h *ApiKeyHandler) CreateApiKey(ctx *gin.Context) {
+	claims := h.humanClaims(ctx)
+	if claims == nil {
+		return
+	}
+
+	var req v1.CreateApiKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.apiKeyService.Create(ctx, claims.UserId, &req)
+	if err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListApiKeys godoc
+// @Summary 获取当前用户的API密钥
+// @Schemes
+// @Description 包括已过期和已吊销的密钥；不能通过API密钥调用
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.ListApiKeysResponse
+// @Router /api-keys [get]
+func (h *ApiKeyHandler) ListApiKeys(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	keys, err := h.apiKeyService.List(ctx, userId)
+	if err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, keys)
+}
+
+// RevokeApiKey godoc
+// @Summary 吊销当前用户的API密钥
+// @Schemes
+// @Description
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param keyId path string true "密钥ID"
+// @Success 200 {object} v1.Response
+// @Router /api-keys/{keyId} [delete]
+func (h *ApiKeyHandler) RevokeApiKey(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(ctx, userId, ctx.Param("keyId")); err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.Revoke error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// CreateServiceAccount godoc
+// @Summary 管理员创建服务账号
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；服务账号不能登录，只能通过API密钥调用接口，只能授予自己拥有的角色
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateServiceAccountRequest true "params"
+// @Success 200 {object} v1.ServiceAccountResponse
+// @Router /service-accounts [post]
+func (h *ApiKeyHandler) CreateServiceAccount(ctx *gin.Context) {
+	claims := h.humanClaims(ctx)
+	if claims == nil {
+		return
+	}
+
+	var req v1.CreateServiceAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.apiKeyService.CreateServiceAccount(ctx, claims.UserId, &req)
+	if err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.CreateServiceAccount error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// CreateServiceAccountKey godoc
+// @Summary 管理员为服务账号创建API密钥
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；完整密钥只在创建时返回一次
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "服务账号ID"
+// @Param request body v1.CreateApiKeyRequest true "params"
+// @Success 200 {object} v1.CreateApiKeyResponse
+// @Router /service-accounts/{userId}/api-keys [post]
+func (h *ApiKeyHandler) CreateServiceAccountKey(ctx *gin.Context) {
+	claims := h.humanClaims(ctx)
+	if claims == nil {
+		return
+	}
+
+	var req v1.CreateApiKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.apiKeyService.CreateForServiceAccount(ctx, claims.UserId, ctx.Param("userId"), &req)
+	if err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.CreateForServiceAccount error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListUserApiKeys godoc
+// @Summary 管理员获取指定用户或服务账号的API密钥
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Success 200 {object} v1.ListApiKeysResponse
+// @Router /users/{userId}/api-keys [get]
+func (h *ApiKeyHandler) ListUserApiKeys(ctx *gin.Context) {
+	keys, err := h.apiKeyService.List(ctx, ctx.Param("userId"))
+	if err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, keys)
+}
+
+// ForceRevokeApiKey godoc
+// @Summary 管理员吊销指定用户或服务账号的API密钥
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags API密钥模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param keyId path string true "密钥ID"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/api-keys/{keyId} [delete]
+func (h *ApiKeyHandler) ForceRevokeApiKey(ctx *gin.Context) {
+	if err := h.apiKeyService.Revoke(ctx, ctx.Param("userId"), ctx.Param("keyId")); err != nil {
+		h.handleApiKeyError(ctx, "apiKeyService.Revoke error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// humanClaims 创建密钥和服务账号必须使用登录令牌，防止泄露的密钥派生出新密钥
+func (h *ApiKeyHandler) humanClaims(ctx *gin.Context) *jwt.MyCustomClaims {
+	claims := GetClaimsFromCtx(ctx)
+	if claims == nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return nil
+	}
+	if claims.ApiKeyId != "" {
+		v1.HandleError(ctx, http.StatusForbidden, v1.ErrApiKeyNotAllowed, nil)
+		return nil
+	}
+	return claims
+}
+
+func (h *ApiKeyHandler) handleApiKeyError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrApiKeyScopeInvalid), errors.Is(err, v1.ErrBadRequest):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	case errors.Is(err, v1.ErrRoleNotHeld):
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
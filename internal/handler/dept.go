@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type DeptHandler struct {
+	*Handler
+	deptService service.DeptService
+}
+
+func NewDeptHandler(handler *Handler, deptService service.DeptService) *DeptHandler {
+	return &DeptHandler{
+		Handler:     handler,
+		deptService: deptService,
+	}
+}
+
+// GetDeptTree godoc
+// @Summary 获取部门树
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；包括停用的部门，同级部门按sort升序排列
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.DeptTreeResponse
+// @Router /depts/tree [get]
+func (h *DeptHandler) GetDeptTree(ctx *gin.Context) {
+	tree, err := h.deptService.Tree(ctx)
+	if err != nil {
+		h.handleDeptError(ctx, "deptService.Tree error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, tree)
+}
+
+// CreateDept godoc
+// @Summary 创建部门
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；负责人必须是已存在的用户
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateDeptRequest true "params"
+// @Success 200 {object} v1.DeptResponse
+// @Router /depts [post]
+func (h *DeptHandler) CreateDept(ctx *gin.Context) {
+	var req v1.CreateDeptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.deptService.Create(ctx, &req)
+	if err != nil {
+		h.handleDeptError(ctx, "deptService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetDept godoc
+// @Summary 获取部门详情
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param deptId path int true "部门ID"
+// @Success 200 {object} v1.DeptResponse
+// @Router /depts/{deptId} [get]
+func (h *DeptHandler) GetDept(ctx *gin.Context) {
+	deptId, ok := h.deptId(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := h.deptService.Get(ctx, deptId)
+	if err != nil {
+		h.handleDeptError(ctx, "deptService.Get error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UpdateDept godoc
+// @Summary 修改部门
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；修改上级部门时整个子树随之移动，不能移动到自身或下级部门下
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param deptId path int true "部门ID"
+// @Param request body v1.UpdateDeptRequest true "params"
+// @Success 200 {object} v1.DeptResponse
+// @Router /depts/{deptId} [put]
+func (h *DeptHandler) UpdateDept(ctx *gin.Context) {
+	deptId, ok := h.deptId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.UpdateDeptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.deptService.Update(ctx, deptId, &req)
+	if err != nil {
+		h.handleDeptError(ctx, "deptService.Update error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// DeleteDept godoc
+// @Summary 删除部门
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；部门还有下级部门或成员时不能删除
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param deptId path int true "部门ID"
+// @Success 200 {object} v1.Response
+// @Router /depts/{deptId} [delete]
+func (h *DeptHandler) DeleteDept(ctx *gin.Context) {
+	deptId, ok := h.deptId(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.deptService.Delete(ctx, deptId); err != nil {
+		h.handleDeptError(ctx, "deptService.Delete error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ListDeptUsers godoc
+// @Summary 分页获取部门成员
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；只返回调用者角色数据范围内的用户
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param deptId path int true "部门ID"
+// @Param request query v1.ListDeptUsersRequest false "params"
+// @Success 200 {object} v1.ListDeptUsersResponse
+// @Router /depts/{deptId}/users [get]
+func (h *DeptHandler) ListDeptUsers(ctx *gin.Context) {
+	deptId, ok := h.deptId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.ListDeptUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.deptService.ListUsers(ctx, deptId, &req)
+	if err != nil {
+		h.handleDeptError(ctx, "deptService.ListUsers error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// AddDeptUsers godoc
+// @Summary 批量将用户加入部门
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；用户只属于一个部门，加入后离开原部门；每次最多500个用户，任一用户不存在时全部不加入
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param deptId path int true "部门ID"
+// @Param request body v1.DeptUsersRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /depts/{deptId}/users [post]
+func (h *DeptHandler) AddDeptUsers(ctx *gin.Context) {
+	deptId, ok := h.deptId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.DeptUsersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.deptService.AddUsers(ctx, deptId, req.UserIds); err != nil {
+		h.handleDeptError(ctx, "deptService.AddUsers error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// RemoveDeptUsers godoc
+// @Summary 将用户移出部门
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；不属于该部门的用户会被忽略
+// @Tags 部门模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param deptId path int true "部门ID"
+// @Param request body v1.DeptUsersRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /depts/{deptId}/users [delete]
+func (h *DeptHandler) RemoveDeptUsers(ctx *gin.Context) {
+	deptId, ok := h.deptId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.DeptUsersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.deptService.RemoveUsers(ctx, deptId, req.UserIds); err != nil {
+		h.handleDeptError(ctx, "deptService.RemoveUsers error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+func (h *DeptHandler) deptId(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("deptId"), 10, 64)
+	if err != nil || id == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *DeptHandler) handleDeptError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrDeptNotEmpty):
+		v1.HandleError(ctx, http.StatusConflict, err, nil)
+	case errors.Is(err, v1.ErrDeptNotFound), errors.Is(err, v1.ErrDeptCycle), errors.Is(err, v1.ErrDeptDisabled),
+		errors.Is(err, v1.ErrUserNotFound):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
 	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
+	"net/http"
 )
 
 type Handler struct {
@@ -22,3 +25,24 @@ func GetUserIdFromCtx(ctx *gin.Context) string {
 	}
 	return v.(*jwt.MyCustomClaims).UserId
 }
+func GetClaimsFromCtx(ctx *gin.Context) *jwt.MyCustomClaims {
+	v, exists := ctx.Get("claims")
+	if !exists {
+		return nil
+	}
+	return v.(*jwt.MyCustomClaims)
+}
+
+// platformOnly 租户管理和全局的权限目录跨越租户边界，只有平台租户的用户可以操作
+func (h *Handler) platformOnly(ctx *gin.Context) bool {
+	claims := GetClaimsFromCtx(ctx)
+	if claims == nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return false
+	}
+	if claims.TenantId != model.PlatformTenantId {
+		v1.HandleError(ctx, http.StatusForbidden, v1.ErrTenantForbidden, nil)
+		return false
+	}
+	return true
+}
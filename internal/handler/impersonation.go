@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ImpersonationHandler struct {
+	*Handler
+	impersonationService service.ImpersonationService
+}
+
+func NewImpersonationHandler(handler *Handler, impersonationService service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		Handler:              handler,
+		impersonationService: impersonationService,
+	}
+}
+
+// Impersonate godoc
+// @Summary 模拟指定用户登录
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；返回的令牌以被模拟用户的身份访问，不能刷新，也不能修改密码、两步验证等账号安全设置
+// @Tags 模拟登录模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "被模拟的用户ID"
+// @Param request body v1.ImpersonateRequest true "params"
+// @Success 200 {object} v1.ImpersonationTokenResponse
+// @Router /users/{userId}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(ctx *gin.Context) {
+	claims := GetClaimsFromCtx(ctx)
+	if claims == nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	var req v1.ImpersonateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.impersonationService.Start(ctx, claims, ctx.Param("userId"), &req, service.ClientInfo{
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		h.handleImpersonationError(ctx, "impersonationService.Start error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// StopImpersonation godoc
+// @Summary 结束模拟登录
+// @Schemes
+// @Description 使用模拟令牌调用，吊销该令牌；管理员自己的令牌不受影响
+// @Tags 模拟登录模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.Response
+// @Router /impersonation/stop [post]
+func (h *ImpersonationHandler) StopImpersonation(ctx *gin.Context) {
+	claims := GetClaimsFromCtx(ctx)
+	if claims == nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.impersonationService.Stop(ctx, claims); err != nil {
+		h.handleImpersonationError(ctx, "impersonationService.Stop error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ListImpersonations godoc
+// @Summary 管理员查询模拟登录审计记录
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；返回最近的100条记录
+// @Tags 模拟登录模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request query v1.ListImpersonationsRequest false "params"
+// @Success 200 {object} v1.ListImpersonationsResponse
+// @Router /impersonations [get]
+func (h *ImpersonationHandler) ListImpersonations(ctx *gin.Context) {
+	var req v1.ListImpersonationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	list, err := h.impersonationService.List(ctx, &req)
+	if err != nil {
+		h.handleImpersonationError(ctx, "impersonationService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, list)
+}
+
+func (h *ImpersonationHandler) handleImpersonationError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrImpersonationNotAllowed), errors.Is(err, v1.ErrImpersonationTarget):
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+	case errors.Is(err, v1.ErrNotImpersonating):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type OIDCHandler struct {
+	*Handler
+	oidcService service.OIDCService
+}
+
+func NewOIDCHandler(handler *Handler, oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		Handler:     handler,
+		oidcService: oidcService,
+	}
+}
+
+// Authorize godoc
+// @Summary 发起单点登录
+// @Schemes
+// @Description 返回身份提供方的授权地址，前端跳转后由身份提供方回调到配置的redirect_url，再调用/oidc/callback完成登录
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Success 200 {object} v1.OIDCAuthorizationResponse
+// @Router /oidc/authorize [get]
+func (h *OIDCHandler) Authorize(ctx *gin.Context) {
+	data, err := h.oidcService.AuthorizationURL(ctx)
+	if err != nil {
+		h.handleOIDCError(ctx, "oidcService.AuthorizationURL error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// Callback godoc
+// @Summary 单点登录回调
+// @Schemes
+// @Description 提交身份提供方回调地址中的code和state换取访问令牌，首次登录时关联或创建本地用户，并按用户组同步角色；与密码登录相同受登录锁定限制，需要两步验证时返回质询令牌
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body v1.OIDCCallbackRequest true "params"
+// @Success 200 {object} v1.LoginResponse
+// @Router /oidc/callback [post]
+func (h *OIDCHandler) Callback(ctx *gin.Context) {
+	var req v1.OIDCCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.oidcService.Callback(ctx, &req, service.ClientInfo{
+		Device:    req.Device,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		h.handleOIDCError(ctx, "oidcService.Callback error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+func (h *OIDCHandler) handleOIDCError(ctx *gin.Context, msg string, err error) {
+	if handleRetryAfterError(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, v1.ErrOIDCDisabled):
+		v1.HandleError(ctx, http.StatusNotFound, err, nil)
+	case errors.Is(err, v1.ErrOIDCStateInvalid), errors.Is(err, v1.ErrOIDCLoginFailed):
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+	case errors.Is(err, v1.ErrOIDCAccountNotFound), errors.Is(err, v1.ErrEmailAlreadyUse):
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type PermissionHandler struct {
+	*Handler
+	permissionService service.PermissionService
+}
+
+func NewPermissionHandler(handler *Handler, permissionService service.PermissionService) *PermissionHandler {
+	return &PermissionHandler{
+		Handler:           handler,
+		permissionService: permissionService,
+	}
+}
+
+// GetPermissionTree godoc
+// @Summary 获取完整的权限树
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；包括菜单、按钮和api类型的全部节点，同级节点按sort升序排列
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.PermissionTreeResponse
+// @Router /permissions/tree [get]
+func (h *PermissionHandler) GetPermissionTree(ctx *gin.Context) {
+	tree, err := h.permissionService.Tree(ctx)
+	if err != nil {
+		h.handlePermissionError(ctx, "permissionService.Tree error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, tree)
+}
+
+// CreatePermission godoc
+// @Summary 创建权限节点
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；父节点必须是菜单，层级根据父节点计算
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreatePermissionRequest true "params"
+// @Success 200 {object} v1.PermissionResponse
+// @Router /permissions [post]
+func (h *PermissionHandler) CreatePermission(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	var req v1.CreatePermissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.permissionService.Create(ctx, &req)
+	if err != nil {
+		h.handlePermissionError(ctx, "permissionService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UpdatePermission godoc
+// @Summary 修改权限节点
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；有子节点的菜单不能修改为其他类型，修改父节点使用移动接口
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param permissionId path int true "权限ID"
+// @Param request body v1.UpdatePermissionRequest true "params"
+// @Success 200 {object} v1.PermissionResponse
+// @Router /permissions/{permissionId} [put]
+func (h *PermissionHandler) UpdatePermission(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	permissionId, ok := h.permissionId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.UpdatePermissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.permissionService.Update(ctx, permissionId, &req)
+	if err != nil {
+		h.handlePermissionError(ctx, "permissionService.Update error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// DeletePermission godoc
+// @Summary 删除权限节点
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；有子节点时不能删除
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param permissionId path int true "权限ID"
+// @Success 200 {object} v1.Response
+// @Router /permissions/{permissionId} [delete]
+func (h *PermissionHandler) DeletePermission(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	permissionId, ok := h.permissionId(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.permissionService.Delete(ctx, permissionId); err != nil {
+		h.handlePermissionError(ctx, "permissionService.Delete error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// MovePermission godoc
+// @Summary 移动权限节点
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；子孙节点随之移动，不能移动到自身或子孙节点下
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param permissionId path int true "权限ID"
+// @Param request body v1.MovePermissionRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /permissions/{permissionId}/move [post]
+func (h *PermissionHandler) MovePermission(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	permissionId, ok := h.permissionId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.MovePermissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.permissionService.Move(ctx, permissionId, &req); err != nil {
+		h.handlePermissionError(ctx, "permissionService.Move error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// SortPermissions godoc
+// @Summary 调整同级权限节点的顺序
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；ids必须正好是父节点下的全部子节点
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.SortPermissionsRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /permissions/sort [put]
+func (h *PermissionHandler) SortPermissions(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	var req v1.SortPermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.permissionService.Sort(ctx, &req); err != nil {
+		h.handlePermissionError(ctx, "permissionService.Sort error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+func (h *PermissionHandler) permissionId(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("permissionId"), 10, 64)
+	if err != nil || id == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *PermissionHandler) handlePermissionError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrBadRequest), errors.Is(err, v1.ErrPermissionInvalid),
+		errors.Is(err, v1.ErrPermissionParent), errors.Is(err, v1.ErrPermissionCycle):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	case errors.Is(err, v1.ErrPermissionChildren):
+		v1.HandleError(ctx, http.StatusConflict, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
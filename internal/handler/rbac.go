@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type RBACHandler struct {
+	*Handler
+	rbacService service.RBACService
+}
+
+func NewRBACHandler(handler *Handler, rbacService service.RBACService) *RBACHandler {
+	return &RBACHandler{
+		Handler:     handler,
+		rbacService: rbacService,
+	}
+}
+
+// GetCacheStats godoc
+// @Summary 管理员查看权限缓存统计
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；统计为当前实例启动以来的累计值
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.PermissionCacheStatsResponse
+// @Router /rbac/cache [get]
+func (h *RBACHandler) GetCacheStats(ctx *gin.Context) {
+	v1.HandleSuccess(ctx, h.rbacService.CacheStats(ctx))
+}
+
+// FlushCache godoc
+// @Summary 管理员清除权限缓存
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；直接修改数据库中的角色或权限后调用
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.Response
+// @Router /rbac/cache [delete]
+func (h *RBACHandler) FlushCache(ctx *gin.Context) {
+	h.rbacService.FlushCache(ctx)
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ExplainPermission godoc
+// @Summary 解释用户对接口的访问权限
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；按RBACAuth相同的规则评估，返回结果及匹配的角色、权限和deny规则，不经过权限缓存。path为实际请求路径或已注册的路由模板，旧版/:api分组中的路由需要使用实际请求路径。dryRun不为空时按调整后的角色评估，不会保存，同时返回当前角色的结果
+// @Tags 权限模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.ExplainPermissionRequest true "params"
+// @Success 200 {object} v1.ExplainPermissionResponse
+// @Router /rbac/explain [post]
+func (h *RBACHandler) ExplainPermission(ctx *gin.Context) {
+	var req v1.ExplainPermissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.rbacService.Explain(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, v1.ErrNotFound):
+			v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+		case errors.Is(err, v1.ErrRouteNotFound), errors.Is(err, v1.ErrRoleNotFound):
+			v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+		default:
+			h.logger.WithContext(ctx).Error("rbacService.Explain error", zap.Error(err))
+			v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		}
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
@@ -0,0 +1,424 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type RoleHandler struct {
+	*Handler
+	roleService service.RoleService
+}
+
+func NewRoleHandler(handler *Handler, roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		Handler:     handler,
+		roleService: roleService,
+	}
+}
+
+// CreateRole godoc
+// @Summary 创建角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；角色标识不能与已有角色重复，包括已删除的角色；设置父角色后继承其全部权限
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateRoleRequest true "params"
+// @Success 200 {object} v1.RoleResponse
+// @Router /roles [post]
+func (h *RoleHandler) CreateRole(ctx *gin.Context) {
+	var req v1.CreateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.roleService.Create(ctx, &req)
+	if err != nil {
+		h.handleRoleError(ctx, "roleService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListRoles godoc
+// @Summary 分页获取角色列表
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；deleted为true时只返回已删除的角色
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request query v1.ListRolesRequest false "params"
+// @Success 200 {object} v1.ListRolesResponse
+// @Router /roles [get]
+func (h *RoleHandler) ListRoles(ctx *gin.Context) {
+	var req v1.ListRolesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.roleService.List(ctx, &req)
+	if err != nil {
+		h.handleRoleError(ctx, "roleService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetRole godoc
+// @Summary 获取角色详情
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；包括角色关联的权限
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Success 200 {object} v1.RoleResponse
+// @Router /roles/{roleId} [get]
+func (h *RoleHandler) GetRole(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := h.roleService.Get(ctx, roleId)
+	if err != nil {
+		h.handleRoleError(ctx, "roleService.Get error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UpdateRole godoc
+// @Summary 修改角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；默认角色不能修改标识，父角色不能是自身或继承自该角色的角色
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Param request body v1.UpdateRoleRequest true "params"
+// @Success 200 {object} v1.RoleResponse
+// @Router /roles/{roleId} [put]
+func (h *RoleHandler) UpdateRole(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.UpdateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.roleService.Update(ctx, roleId, &req)
+	if err != nil {
+		h.handleRoleError(ctx, "roleService.Update error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// DeleteRole godoc
+// @Summary 删除角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；软删除，可以恢复，默认角色不能删除
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Success 200 {object} v1.Response
+// @Router /roles/{roleId} [delete]
+func (h *RoleHandler) DeleteRole(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.Delete(ctx, roleId); err != nil {
+		h.handleRoleError(ctx, "roleService.Delete error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// RestoreRole godoc
+// @Summary 恢复已删除的角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；恢复后原有的用户和权限关联重新生效
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Success 200 {object} v1.Response
+// @Router /roles/{roleId}/restore [post]
+func (h *RoleHandler) RestoreRole(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.Restore(ctx, roleId); err != nil {
+		h.handleRoleError(ctx, "roleService.Restore error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// AttachPermissions godoc
+// @Summary 为角色关联权限
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；effect为deny时拒绝这些权限，优先于用户其他角色的allow，被拒绝的菜单及其子菜单不会出现在菜单树中；已关联的权限更新为新的effect
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Param request body v1.RolePermissionsRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /roles/{roleId}/permissions [post]
+func (h *RoleHandler) AttachPermissions(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.RolePermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.roleService.AttachPermissions(ctx, roleId, req.PermissionIds, req.Effect); err != nil {
+		h.handleRoleError(ctx, "roleService.AttachPermissions error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// DetachPermissions godoc
+// @Summary 取消角色关联的权限
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Param request body v1.RolePermissionsRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /roles/{roleId}/permissions [delete]
+func (h *RoleHandler) DetachPermissions(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.RolePermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.roleService.DetachPermissions(ctx, roleId, req.PermissionIds); err != nil {
+		h.handleRoleError(ctx, "roleService.DetachPermissions error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// GetUserRoles godoc
+// @Summary 获取用户的角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Success 200 {object} v1.UserRolesResponse
+// @Router /users/{userId}/roles [get]
+func (h *RoleHandler) GetUserRoles(ctx *gin.Context) {
+	data, err := h.roleService.GetUserRoles(ctx, ctx.Param("userId"))
+	if err != nil {
+		h.handleRoleError(ctx, "roleService.GetUserRoles error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// SetUserRoles godoc
+// @Summary 设置用户的角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；用roleIds替换用户现有的全部角色
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param request body v1.SetUserRolesRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/roles [put]
+func (h *RoleHandler) SetUserRoles(ctx *gin.Context) {
+	var req v1.SetUserRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.roleService.SetUserRoles(ctx, ctx.Param("userId"), req.RoleIds); err != nil {
+		h.handleRoleError(ctx, "roleService.SetUserRoles error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// AddUserRoles godoc
+// @Summary 为用户添加角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；已拥有的角色忽略
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param request body v1.UserRolesRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/roles [post]
+func (h *RoleHandler) AddUserRoles(ctx *gin.Context) {
+	var req v1.UserRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.roleService.AddUserRoles(ctx, ctx.Param("userId"), req.RoleIds); err != nil {
+		h.handleRoleError(ctx, "roleService.AddUserRoles error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// RemoveUserRoles godoc
+// @Summary 移除用户的角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param request body v1.UserRolesRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/roles [delete]
+func (h *RoleHandler) RemoveUserRoles(ctx *gin.Context) {
+	var req v1.UserRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.roleService.RemoveUserRoles(ctx, ctx.Param("userId"), req.RoleIds); err != nil {
+		h.handleRoleError(ctx, "roleService.RemoveUserRoles error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// AssignRoleUsers godoc
+// @Summary 批量为用户关联角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；每次最多500个用户，任一用户不存在时全部不关联
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Param request body v1.AssignRoleUsersRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /roles/{roleId}/users [post]
+func (h *RoleHandler) AssignRoleUsers(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.AssignRoleUsersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.roleService.AssignUsers(ctx, roleId, req.UserIds); err != nil {
+		h.handleRoleError(ctx, "roleService.AssignUsers error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ListRoleUsers godoc
+// @Summary 分页获取拥有该角色的用户
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；只返回调用者角色数据范围内的用户
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param roleId path int true "角色ID"
+// @Param request query v1.ListRoleUsersRequest false "params"
+// @Success 200 {object} v1.ListRoleUsersResponse
+// @Router /roles/{roleId}/users [get]
+func (h *RoleHandler) ListRoleUsers(ctx *gin.Context) {
+	roleId, ok := h.roleId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.ListRoleUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.roleService.ListUsers(ctx, roleId, &req)
+	if err != nil {
+		h.handleRoleError(ctx, "roleService.ListUsers error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+func (h *RoleHandler) roleId(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("roleId"), 10, 64)
+	if err != nil || id == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *RoleHandler) handleRoleError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrRoleLabelExists):
+		v1.HandleError(ctx, http.StatusConflict, err, nil)
+	case errors.Is(err, v1.ErrRoleProtected):
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+	case errors.Is(err, v1.ErrPermissionNotFound), errors.Is(err, v1.ErrRoleNotFound), errors.Is(err, v1.ErrUserNotFound),
+		errors.Is(err, v1.ErrRoleCycle), errors.Is(err, v1.ErrDeptNotFound):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type RoleGrantHandler struct {
+	*Handler
+	roleGrantService service.RoleGrantService
+}
+
+func NewRoleGrantHandler(handler *Handler, roleGrantService service.RoleGrantService) *RoleGrantHandler {
+	return &RoleGrantHandler{
+		Handler:          handler,
+		roleGrantService: roleGrantService,
+	}
+}
+
+// CreateRoleGrant godoc
+// @Summary 限时授予用户角色
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；授权只在开始和结束时间之间生效，到期后自动失效并保留记录
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param request body v1.CreateRoleGrantRequest true "params"
+// @Success 200 {object} v1.RoleGrantResponse
+// @Router /users/{userId}/role-grants [post]
+func (h *RoleGrantHandler) CreateRoleGrant(ctx *gin.Context) {
+	var req v1.CreateRoleGrantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.roleGrantService.Create(ctx, GetUserIdFromCtx(ctx), ctx.Param("userId"), &req)
+	if err != nil {
+		h.handleRoleGrantError(ctx, "roleGrantService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListRoleGrants godoc
+// @Summary 获取用户的临时授权
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；默认只返回还未结束的授权，最多返回最近100条
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param request query v1.ListRoleGrantsRequest false "params"
+// @Success 200 {object} v1.ListRoleGrantsResponse
+// @Router /users/{userId}/role-grants [get]
+func (h *RoleGrantHandler) ListRoleGrants(ctx *gin.Context) {
+	var req v1.ListRoleGrantsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.roleGrantService.List(ctx, ctx.Param("userId"), &req)
+	if err != nil {
+		h.handleRoleGrantError(ctx, "roleGrantService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// RevokeRoleGrant godoc
+// @Summary 撤销用户的临时授权
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限；提前结束授权并记录撤销人
+// @Tags 角色模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param grantId path int true "授权ID"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/role-grants/{grantId} [delete]
+func (h *RoleGrantHandler) RevokeRoleGrant(ctx *gin.Context) {
+	grantId, err := strconv.ParseUint(ctx.Param("grantId"), 10, 64)
+	if err != nil || grantId == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err = h.roleGrantService.Revoke(ctx, GetUserIdFromCtx(ctx), ctx.Param("userId"), uint(grantId)); err != nil {
+		h.handleRoleGrantError(ctx, "roleGrantService.Revoke error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+func (h *RoleGrantHandler) handleRoleGrantError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrRoleGrantEnded):
+		v1.HandleError(ctx, http.StatusConflict, err, nil)
+	case errors.Is(err, v1.ErrRoleNotFound), errors.Is(err, v1.ErrRoleGrantPeriod):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
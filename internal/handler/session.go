@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type SessionHandler struct {
+	*Handler
+	sessionService service.SessionService
+}
+
+func NewSessionHandler(handler *Handler, sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		Handler:        handler,
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions godoc
+// @Summary 获取当前用户的登录会话
+// @Schemes
+// @Description 不能通过API密钥调用
+// @Tags 会话模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.ListSessionsResponse
+// @Router /sessions [get]
+func (h *SessionHandler) ListSessions(ctx *gin.Context) {
+	claims := GetClaimsFromCtx(ctx)
+	if claims == nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(ctx, claims.UserId, claims.SessionId)
+	if err != nil {
+		h.logger.WithContext(ctx).Error("sessionService.ListSessions error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, sessions)
+}
+
+// RevokeSession godoc
+// @Summary 结束当前用户的指定会话
+// @Schemes
+// @Description
+// @Tags 会话模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param sessionId path string true "会话ID"
+// @Success 200 {object} v1.Response
+// @Router /sessions/{sessionId} [delete]
+func (h *SessionHandler) RevokeSession(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.sessionService.RevokeOwnSession(ctx, userId, ctx.Param("sessionId")); err != nil {
+		h.handleSessionError(ctx, "sessionService.RevokeOwnSession error", err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ListUserSessions godoc
+// @Summary 管理员获取指定用户的登录会话
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 会话模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Success 200 {object} v1.ListSessionsResponse
+// @Router /users/{userId}/sessions [get]
+func (h *SessionHandler) ListUserSessions(ctx *gin.Context) {
+	sessions, err := h.sessionService.ListSessions(ctx, ctx.Param("userId"), "")
+	if err != nil {
+		h.logger.WithContext(ctx).Error("sessionService.ListSessions error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, sessions)
+}
+
+// ForceRevokeSession godoc
+// @Summary 管理员强制结束会话
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 会话模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Param sessionId path string true "会话ID"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/sessions/{sessionId} [delete]
+func (h *SessionHandler) ForceRevokeSession(ctx *gin.Context) {
+	if err := h.sessionService.ForceRevokeSession(ctx, ctx.Param("userId"), ctx.Param("sessionId")); err != nil {
+		h.handleSessionError(ctx, "sessionService.ForceRevokeSession error", err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// ForceRevokeUserSessions godoc
+// @Summary 管理员强制结束用户的全部会话
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 会话模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/sessions [delete]
+func (h *SessionHandler) ForceRevokeUserSessions(ctx *gin.Context) {
+	if err := h.sessionService.ForceRevokeUserSessions(ctx, ctx.Param("userId")); err != nil {
+		h.handleSessionError(ctx, "sessionService.ForceRevokeUserSessions error", err)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+func (h *SessionHandler) handleSessionError(ctx *gin.Context, msg string, err error) {
+	if errors.Is(err, v1.ErrNotFound) {
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+		return
+	}
+	h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+	v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type TenantHandler struct {
+	*Handler
+	tenantService service.TenantService
+}
+
+func NewTenantHandler(handler *Handler, tenantService service.TenantService) *TenantHandler {
+	return &TenantHandler{
+		Handler:       handler,
+		tenantService: tenantService,
+	}
+}
+
+// CreateTenant godoc
+// @Summary 创建租户
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；同时创建租户的默认角色
+// @Tags 租户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.CreateTenantRequest true "params"
+// @Success 200 {object} v1.TenantResponse
+// @Router /tenants [post]
+func (h *TenantHandler) CreateTenant(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	var req v1.CreateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.tenantService.Create(ctx, &req)
+	if err != nil {
+		h.handleTenantError(ctx, "tenantService.Create error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// ListTenants godoc
+// @Summary 分页获取租户
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户
+// @Tags 租户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request query v1.ListTenantsRequest false "params"
+// @Success 200 {object} v1.ListTenantsResponse
+// @Router /tenants [get]
+func (h *TenantHandler) ListTenants(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	var req v1.ListTenantsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.tenantService.List(ctx, &req)
+	if err != nil {
+		h.handleTenantError(ctx, "tenantService.List error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// GetTenant godoc
+// @Summary 获取租户详情
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户
+// @Tags 租户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param tenantId path int true "租户ID"
+// @Success 200 {object} v1.TenantResponse
+// @Router /tenants/{tenantId} [get]
+func (h *TenantHandler) GetTenant(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	tenantId, ok := h.tenantId(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := h.tenantService.Get(ctx, tenantId)
+	if err != nil {
+		h.handleTenantError(ctx, "tenantService.Get error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UpdateTenant godoc
+// @Summary 修改租户
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；租户标识不能修改
+// @Tags 租户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param tenantId path int true "租户ID"
+// @Param request body v1.UpdateTenantRequest true "params"
+// @Success 200 {object} v1.TenantResponse
+// @Router /tenants/{tenantId} [put]
+func (h *TenantHandler) UpdateTenant(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	tenantId, ok := h.tenantId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.UpdateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.tenantService.Update(ctx, tenantId, &req)
+	if err != nil {
+		h.handleTenantError(ctx, "tenantService.Update error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// DeleteTenant godoc
+// @Summary 删除租户
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；租户还有用户时不能删除，其角色和部门一并删除
+// @Tags 租户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param tenantId path int true "租户ID"
+// @Success 200 {object} v1.Response
+// @Router /tenants/{tenantId} [delete]
+func (h *TenantHandler) DeleteTenant(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	tenantId, ok := h.tenantId(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.tenantService.Delete(ctx, tenantId); err != nil {
+		h.handleTenantError(ctx, "tenantService.Delete error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// MoveTenantUsers godoc
+// @Summary 批量将用户移入租户
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限，且调用者属于平台租户；用户离开原部门，原有角色替换为新租户的默认角色，并注销全部会话；每次最多500个用户，任一用户不存在时全部不移动
+// @Tags 租户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param tenantId path int true "租户ID"
+// @Param request body v1.MoveTenantUsersRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /tenants/{tenantId}/users [post]
+func (h *TenantHandler) MoveTenantUsers(ctx *gin.Context) {
+	if !h.platformOnly(ctx) {
+		return
+	}
+	tenantId, ok := h.tenantId(ctx)
+	if !ok {
+		return
+	}
+	var req v1.MoveTenantUsersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.tenantService.MoveUsers(ctx, tenantId, req.UserIds); err != nil {
+		h.handleTenantError(ctx, "tenantService.MoveUsers error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+func (h *TenantHandler) tenantId(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("tenantId"), 10, 64)
+	if err != nil || id == 0 {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *TenantHandler) handleTenantError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, v1.ErrNotFound):
+		v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+	case errors.Is(err, v1.ErrTenantCodeExists), errors.Is(err, v1.ErrTenantNotEmpty):
+		v1.HandleError(ctx, http.StatusConflict, err, nil)
+	case errors.Is(err, v1.ErrUserNotFound):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	default:
+		h.logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TokenHandler struct {
+	*Handler
+	tokenService service.TokenService
+}
+
+func NewTokenHandler(handler *Handler, tokenService service.TokenService) *TokenHandler {
+	return &TokenHandler{
+		Handler:      handler,
+		tokenService: tokenService,
+	}
+}
+
+// Refresh godoc
+// @Summary 刷新访问令牌
+// @Schemes
+// @Description 使用刷新令牌换取新的访问令牌，旧的刷新令牌随即失效
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body v1.RefreshTokenRequest true "params"
+// @Success 200 {object} v1.RefreshTokenResponse
+// @Router /token/refresh [post]
+func (h *TokenHandler) Refresh(ctx *gin.Context) {
+	var req v1.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.tokenService.Refresh(ctx, req.RefreshToken, service.ClientInfo{
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		if errors.Is(err, v1.ErrRefreshTokenInvalid) || errors.Is(err, v1.ErrRefreshTokenReused) {
+			v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+			return
+		}
+		h.logger.WithContext(ctx).Error("tokenService.Refresh error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, data)
+}
+
+// Logout godoc
+// @Summary 退出登录
+// @Schemes
+// @Description 吊销当前访问令牌并结束所属会话
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.Response
+// @Router /logout [post]
+func (h *TokenHandler) Logout(ctx *gin.Context) {
+	claims := GetClaimsFromCtx(ctx)
+	if claims == nil {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.tokenService.Logout(ctx, claims); err != nil {
+		h.logger.WithContext(ctx).Error("tokenService.Logout error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// LogoutAll godoc
+// @Summary 退出所有会话
+// @Schemes
+// @Description 吊销当前用户已签发的全部访问令牌和刷新令牌
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.Response
+// @Router /logout/all [post]
+func (h *TokenHandler) LogoutAll(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	if err := h.tokenService.LogoutAll(ctx, userId); err != nil {
+		h.logger.WithContext(ctx).Error("tokenService.LogoutAll error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+
+	v1.HandleSuccess(ctx, nil)
+}
+
+// JWKS 以RFC 7517格式公开验签公钥，不使用统一的响应包装，方便其他服务直接使用标准库验签
+func (h *TokenHandler) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.tokenService.JWKS())
+}
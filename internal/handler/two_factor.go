@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/log"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TwoFactorHandler struct {
+	*Handler
+	twoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandler(handler *Handler, twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		Handler:          handler,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Setup godoc
+// @Summary 生成两步验证密钥
+// @Schemes
+// @Description 返回密钥和otpauth链接，调用/user/2fa/confirm校验验证码后生效
+// @Tags 两步验证模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} v1.TwoFactorSetupResponse
+// @Router /user/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+
+	data, err := h.twoFactorService.Setup(ctx, userId)
+	if err != nil {
+		handleTwoFactorError(ctx, h.logger, "twoFactorService.Setup error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// Confirm godoc
+// @Summary 确认绑定两步验证
+// @Schemes
+// @Description 校验验证码后启用两步验证，返回的恢复码仅展示一次
+// @Tags 两步验证模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.TwoFactorCodeRequest true "params"
+// @Success 200 {object} v1.RecoveryCodesResponse
+// @Router /user/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	var req v1.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(ctx, userId, req.Code)
+	if err != nil {
+		handleTwoFactorError(ctx, h.logger, "twoFactorService.Confirm error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, v1.RecoveryCodesData{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary 关闭两步验证
+// @Schemes
+// @Description 角色要求两步验证时不能关闭
+// @Tags 两步验证模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.TwoFactorCodeRequest true "params"
+// @Success 200 {object} v1.Response
+// @Router /user/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	var req v1.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(ctx, userId, req.Code); err != nil {
+		handleTwoFactorError(ctx, h.logger, "twoFactorService.Disable error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary 重新生成恢复码
+// @Schemes
+// @Description 旧的恢复码全部作废
+// @Tags 两步验证模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body v1.TwoFactorCodeRequest true "params"
+// @Success 200 {object} v1.RecoveryCodesResponse
+// @Router /user/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userId := GetUserIdFromCtx(ctx)
+	if userId == "" {
+		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+		return
+	}
+	var req v1.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(ctx, userId, req.Code)
+	if err != nil {
+		handleTwoFactorError(ctx, h.logger, "twoFactorService.RegenerateRecoveryCodes error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, v1.RecoveryCodesData{RecoveryCodes: codes})
+}
+
+// handleTwoFactorError 业务错误原样返回给客户端，其余按服务端错误处理
+func handleTwoFactorError(ctx *gin.Context, logger *log.Logger, msg string, err error) {
+	if handleRetryAfterError(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, v1.ErrTwoFactorCodeInvalid), errors.Is(err, v1.ErrChallengeInvalid):
+		v1.HandleError(ctx, http.StatusUnauthorized, err, nil)
+	case errors.Is(err, v1.ErrTwoFactorAlreadyEnabled), errors.Is(err, v1.ErrTwoFactorNotEnabled):
+		v1.HandleError(ctx, http.StatusBadRequest, err, nil)
+	case errors.Is(err, v1.ErrTwoFactorRequired):
+		v1.HandleError(ctx, http.StatusForbidden, err, nil)
+	default:
+		logger.WithContext(ctx).Error(msg, zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+	}
+}
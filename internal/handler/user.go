@@ -3,9 +3,12 @@ package handler
 import (
 	"admin-webrtc-go/api/v1"
 	"admin-webrtc-go/internal/service"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type UserHandler struct {
@@ -23,7 +26,7 @@ func NewUserHandler(handler *Handler, userService service.UserService) *UserHand
 // Register godoc
 // @Summary 用户注册
 // @Schemes
-// @Description 目前只支持邮箱登录
+// @Description 目前只支持邮箱登录，密码须符合密码策略，不符合时data.violations列出未通过的规则
 // @Tags 用户模块
 // @Accept json
 // @Produce json
@@ -38,6 +41,9 @@ func (h *UserHandler) Register(ctx *gin.Context) {
 	}
 
 	if err := h.userService.Register(ctx, req); err != nil {
+		if handlePasswordPolicyError(ctx, err) {
+			return
+		}
 		h.logger.WithContext(ctx).Error("userService.Register error", zap.Error(err))
 		v1.HandleError(ctx, http.StatusInternalServerError, err, nil)
 		return
@@ -63,14 +69,112 @@ func (h *UserHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.userService.Login(ctx, &req)
+	data, err := h.userService.Login(ctx, &req, service.ClientInfo{
+		Device:    req.Device,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
 	if err != nil {
+		if handleRetryAfterError(ctx, err) {
+			return
+		}
 		v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
 		return
 	}
-	v1.HandleSuccess(ctx, v1.LoginResponseData{
-		AccessToken: token,
+	v1.HandleSuccess(ctx, data)
+}
+
+// LoginTwoFactorSetup godoc
+// @Summary 登录时绑定两步验证
+// @Schemes
+// @Description 角色要求两步验证但尚未绑定时，凭登录返回的challengeToken生成密钥，再调用/login/2fa完成绑定和登录
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body v1.TwoFactorLoginSetupRequest true "params"
+// @Success 200 {object} v1.TwoFactorSetupResponse
+// @Router /login/2fa/setup [post]
+func (h *UserHandler) LoginTwoFactorSetup(ctx *gin.Context) {
+	var req v1.TwoFactorLoginSetupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.userService.LoginTwoFactorSetup(ctx, &req)
+	if err != nil {
+		handleTwoFactorError(ctx, h.logger, "userService.LoginTwoFactorSetup error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// LoginTwoFactor godoc
+// @Summary 两步验证登录
+// @Schemes
+// @Description 提交登录返回的challengeToken和验证码(或恢复码)换取访问令牌
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body v1.TwoFactorLoginRequest true "params"
+// @Success 200 {object} v1.LoginResponse
+// @Router /login/2fa [post]
+func (h *UserHandler) LoginTwoFactor(ctx *gin.Context) {
+	var req v1.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		v1.HandleError(ctx, http.StatusBadRequest, v1.ErrBadRequest, nil)
+		return
+	}
+
+	data, err := h.userService.LoginTwoFactor(ctx, &req, service.ClientInfo{
+		Device:    req.Device,
+		Ip:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
 	})
+	if err != nil {
+		if handleRetryAfterError(ctx, err) {
+			return
+		}
+		handleTwoFactorError(ctx, h.logger, "userService.LoginTwoFactor error", err)
+		return
+	}
+	v1.HandleSuccess(ctx, data)
+}
+
+// UnlockLogin godoc
+// @Summary 管理员解除账号登录锁定
+// @Schemes
+// @Description 需要拥有匹配该路由和方法的api权限
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId path string true "用户ID"
+// @Success 200 {object} v1.Response
+// @Router /users/{userId}/lock [delete]
+func (h *UserHandler) UnlockLogin(ctx *gin.Context) {
+	if err := h.userService.UnlockLogin(ctx, ctx.Param("userId")); err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			v1.HandleError(ctx, http.StatusNotFound, v1.ErrNotFound, nil)
+			return
+		}
+		h.logger.WithContext(ctx).Error("userService.UnlockLogin error", zap.Error(err))
+		v1.HandleError(ctx, http.StatusInternalServerError, v1.ErrInternalServerError, nil)
+		return
+	}
+	v1.HandleSuccess(ctx, nil)
+}
+
+// handleRetryAfterError 登录受限时返回429，并通过Retry-After告知需要等待的秒数
+func handleRetryAfterError(ctx *gin.Context, err error) bool {
+	var retryErr *service.RetryAfterError
+	if !errors.As(err, &retryErr) {
+		return false
+	}
+	seconds := int64(math.Ceil(retryErr.RetryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.FormatInt(seconds, 10))
+	v1.HandleError(ctx, http.StatusTooManyRequests, retryErr.Err, map[string]int64{"retryAfter": seconds})
+	return true
 }
 
 // GetProfile godoc
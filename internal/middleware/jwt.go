@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/service"
 	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
 	"github.com/gin-gonic/gin"
@@ -9,10 +10,12 @@ import (
 	"net/http"
 )
 
-func StrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
+const apiKeyHeader = "X-API-Key"
+
+func StrictAuth(j *jwt.JWT, ts service.TokenService, aks service.ApiKeyService, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString := ctx.Request.Header.Get("Authorization")
-		if tokenString == "" {
+		if tokenString == "" && ctx.Request.Header.Get(apiKeyHeader) == "" {
 			logger.WithContext(ctx).Warn("No token", zap.Any("data", map[string]interface{}{
 				"url":    ctx.Request.URL,
 				"params": ctx.Params,
@@ -22,7 +25,7 @@ func StrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := j.ParseToken(tokenString)
+		claims, err := authenticate(ctx, j, ts, aks, tokenString)
 		if err != nil {
 			logger.WithContext(ctx).Error("token error", zap.Any("data", map[string]interface{}{
 				"url":    ctx.Request.URL,
@@ -39,7 +42,7 @@ func StrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
-func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
+func NoStrictAuth(j *jwt.JWT, ts service.TokenService, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenString := ctx.Request.Header.Get("Authorization")
 		if tokenString == "" {
@@ -53,7 +56,7 @@ func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := j.ParseToken(tokenString)
+		claims, err := parseToken(ctx, j, ts, tokenString)
 		if err != nil {
 			ctx.Next()
 			return
@@ -65,8 +68,69 @@ func NoStrictAuth(j *jwt.JWT, logger *log.Logger) gin.HandlerFunc {
 	}
 }
 
+// authenticate 请求携带X-API-Key时按API密钥认证，否则解析Authorization中的访问令牌
+func authenticate(ctx *gin.Context, j *jwt.JWT, ts service.TokenService, aks service.ApiKeyService, tokenString string) (*jwt.MyCustomClaims, error) {
+	rawKey := ctx.Request.Header.Get(apiKeyHeader)
+	if rawKey == "" {
+		return parseToken(ctx, j, ts, tokenString)
+	}
+	key, err := aks.Authenticate(ctx, rawKey, ctx.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+	ctx.Set("apiKey", key)
+	return &jwt.MyCustomClaims{UserId: key.UserId, ApiKeyId: key.KeyId, TenantId: key.TenantId}, nil
+}
+
+// parseToken 校验签名和有效期，并确认令牌没有被服务端吊销
+func parseToken(ctx *gin.Context, j *jwt.JWT, ts service.TokenService, tokenString string) (*jwt.MyCustomClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := ts.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, v1.ErrTokenRevoked
+	}
+	return claims, nil
+}
+
 func recoveryLoggerFunc(ctx *gin.Context, logger *log.Logger) {
 	if userInfo, ok := ctx.MustGet("claims").(*jwt.MyCustomClaims); ok {
 		logger.WithValue(ctx, zap.String("UserId", userInfo.UserId))
+		// 模拟登录的请求在后续所有日志中带上发起人
+		if userInfo.ActorId != "" {
+			logger.WithValue(ctx, zap.String("ImpersonatorId", userInfo.ActorId))
+			logger.WithContext(ctx).Info("impersonated request", zap.String("url", ctx.Request.URL.String()))
+		}
+	}
+}
+
+// DenyImpersonation 修改密码、两步验证等账号安全操作只能由用户本人完成，模拟令牌不能调用
+func DenyImpersonation(logger *log.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if claims, ok := ctx.Value("claims").(*jwt.MyCustomClaims); ok && claims.ActorId != "" {
+			logger.WithContext(ctx).Warn("operation denied while impersonating", zap.String("url", ctx.Request.URL.String()))
+			v1.HandleError(ctx, http.StatusForbidden, v1.ErrImpersonationNotAllowed, nil)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// DenyApiKey 账号安全和审批操作只能使用用户登录后的访问令牌，API密钥即使在权限范围内也不能调用
+func DenyApiKey(logger *log.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if claims, ok := ctx.Value("claims").(*jwt.MyCustomClaims); ok && claims.ApiKeyId != "" {
+			logger.WithContext(ctx).Warn("operation denied for api key", zap.String("url", ctx.Request.URL.String()))
+			v1.HandleError(ctx, http.StatusForbidden, v1.ErrApiKeyNotAllowed, nil)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
 	}
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
 	"admin-webrtc-go/internal/service"
 	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/pkg/log"
@@ -10,10 +11,10 @@ import (
 	"net/http"
 )
 
-func RBACAuth(j *jwt.JWT, us service.UserService, logger *log.Logger) gin.HandlerFunc {
+func RBACAuth(j *jwt.JWT, us service.UserService, ts service.TokenService, aks service.ApiKeyService, logger *log.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		tokenStr := ctx.Request.Header.Get("Authorization")
-		if tokenStr == "" {
+		if tokenStr == "" && ctx.Request.Header.Get(apiKeyHeader) == "" {
 			logger.WithContext(ctx).Error("token empty", zap.Any("data", map[string]interface{}{
 				"url":    ctx.Request.URL,
 				"params": ctx.Params,
@@ -23,21 +24,30 @@ func RBACAuth(j *jwt.JWT, us service.UserService, logger *log.Logger) gin.Handle
 			return
 		}
 
-		claims, err := j.ParseToken(tokenStr)
-		if err != nil {
-			logger.WithContext(ctx).Error("token parse error", zap.Any("data", map[string]interface{}{
-				"url":    ctx.Request.URL,
-				"params": ctx.Params,
-			}), zap.Error(err))
-			v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
-			ctx.Abort()
-			return
+		// 前置的认证中间件已经完成认证时直接使用其结果，避免重复校验
+		claims, ok := ctx.Value("claims").(*jwt.MyCustomClaims)
+		if !ok {
+			var err error
+			claims, err = authenticate(ctx, j, ts, aks, tokenStr)
+			if err != nil {
+				logger.WithContext(ctx).Error("token parse error", zap.Any("data", map[string]interface{}{
+					"url":    ctx.Request.URL,
+					"params": ctx.Params,
+				}), zap.Error(err))
+				v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+				ctx.Abort()
+				return
+			}
 		}
 
-		// 需要取出路径中的/:api参数用于permission表查询是否拥有请求权限
-		apiPath := ctx.Param("api")
+		// 按注册的路由模板和请求方法匹配api权限，旧版/:api分组同时带上api参数
+		req := service.ApiRequest{
+			Method: ctx.Request.Method,
+			Route:  ctx.FullPath(),
+			Api:    ctx.Param("api"),
+		}
 
-		flag, err := us.CheckAPIAuthPermission(ctx, claims.UserId, apiPath)
+		flag, err := us.CheckAPIAuthPermission(ctx, claims.UserId, req)
 		// 查询报错
 		if err != nil {
 			logger.WithContext(ctx).Error("CheckAPIAuthPermission method error", zap.Any("data", map[string]interface{}{
@@ -48,13 +58,17 @@ func RBACAuth(j *jwt.JWT, us service.UserService, logger *log.Logger) gin.Handle
 			ctx.Abort()
 			return
 		}
-		// 无权限
+		// API密钥还要受其权限范围限制
+		if key, ok := ctx.Value("apiKey").(*model.ApiKey); ok && flag {
+			flag = service.ApiKeyAllows(key, req)
+		}
+		// 已认证但无权限
 		if !flag {
-			logger.WithContext(ctx).Error(v1.ErrUnauthorized.Error(), zap.Any("data", map[string]interface{}{
+			logger.WithContext(ctx).Error(v1.ErrForbidden.Error(), zap.Any("data", map[string]interface{}{
 				"url":    ctx.Request.URL,
 				"params": ctx.Params,
 			}))
-			v1.HandleError(ctx, http.StatusUnauthorized, v1.ErrUnauthorized, nil)
+			v1.HandleError(ctx, http.StatusForbidden, v1.ErrForbidden, nil)
 			ctx.Abort()
 			return
 		}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// 角色申请的状态，只能从pending转为其他状态
+const (
+	AccessRequestPending   = "pending"
+	AccessRequestApproved  = "approved"
+	AccessRequestRejected  = "rejected"
+	AccessRequestCancelled = "cancelled"
+)
+
+// AccessRequest 用户申请角色的请求，由拥有该角色审批人角色的用户审批
+type AccessRequest struct {
+	Id            uint   `gorm:"primarykey"`
+	TenantId      uint   `gorm:"index;not null;default:0"` // 所属租户
+	UserId        string `gorm:"size:64;index;not null"`   // 申请人
+	RoleId        uint   `gorm:"index;not null"`
+	Reason        string `gorm:"not null"`
+	DurationHours int    `gorm:"not null;default:0"` // 批准后授权的小时数，0表示长期拥有
+	Status        string `gorm:"size:16;index;not null;default:pending"`
+	DecidedBy     string `gorm:"size:64"` // 审批人或取消申请的用户
+	DecidedAt     *time.Time
+	GrantId       uint // 限时申请批准后创建的临时授权
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (m *AccessRequest) TableName() string {
+	return "access_request"
+}
+
+// AccessRequestEvent 角色申请的每次状态变化，创建申请时FromStatus为空
+type AccessRequestEvent struct {
+	Id         uint   `gorm:"primarykey"`
+	TenantId   uint   `gorm:"index;not null;default:0"`
+	RequestId  uint   `gorm:"index;not null"`
+	FromStatus string `gorm:"size:16"`
+	ToStatus   string `gorm:"size:16;not null"`
+	ActorId    string `gorm:"size:64;not null"`
+	Comment    string
+	CreatedAt  time.Time
+}
+
+func (m *AccessRequestEvent) TableName() string {
+	return "access_request_event"
+}
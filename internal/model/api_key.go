@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// ApiKey 机器间调用使用的API密钥，只保存哈希值，Scopes限定可访问的api权限
+type ApiKey struct {
+	Id         uint         `gorm:"primarykey"`
+	KeyId      string       `gorm:"size:32;uniqueIndex;not null"` // 公开标识，用于展示和吊销
+	Prefix     string       `gorm:"not null"`                     // 密钥开头的若干字符，便于用户辨认
+	KeyHash    string       `gorm:"size:64;uniqueIndex;not null"` // 密钥sha256摘要
+	Name       string       `gorm:"not null"`
+	UserId     string       `gorm:"index;not null"`           // 所属用户或服务账号
+	TenantId   uint         `gorm:"index;not null;default:0"` // 与所属用户相同，认证后写入令牌信息
+	CreatedBy  string       `gorm:"not null"`
+	Scopes     []Permission `gorm:"many2many:api_key_permissions;"`
+	ExpiresAt  *time.Time   // 为空表示永不过期
+	LastUsedAt *time.Time
+	LastUsedIp string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (m *ApiKey) TableName() string {
+	return "api_key"
+}
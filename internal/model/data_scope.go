@@ -0,0 +1,20 @@
+package model
+
+// 角色的数据范围，用户拥有多个角色时取并集
+const (
+	DataScopeAll             = "all"               // 全部数据
+	DataScopeDept            = "dept"              // 本部门
+	DataScopeDeptAndChildren = "dept_and_children" // 本部门及下级部门
+	DataScopeSelf            = "self"              // 仅本人
+	DataScopeCustom          = "custom"            // 自定义部门
+)
+
+// RoleDept 数据范围为custom的角色可以查看的部门
+type RoleDept struct {
+	RoleId uint `gorm:"primaryKey"`
+	DeptId uint `gorm:"primaryKey"`
+}
+
+func (m *RoleDept) TableName() string {
+	return "role_dept"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// 部门状态，停用的部门不能再添加成员，已有成员和数据范围不受影响
+const (
+	DeptStatusEnabled  = "enabled"
+	DeptStatusDisabled = "disabled"
+)
+
+type Dept struct {
+	Id        uint   `gorm:"primarykey"`
+	TenantId  uint   `gorm:"index;not null;default:0"` // 所属租户
+	ParentId  uint   `gorm:"index"`                    // 上级部门，0表示顶级部门
+	DeptName  string `gorm:"size:64;not null"`
+	LeaderId  string `gorm:"size:64"`                          // 负责人的用户ID
+	Sort      int    `gorm:"not null;default:0"`               // 同级部门按升序排列
+	Status    string `gorm:"size:16;not null;default:enabled"` // 见DeptStatusEnabled等
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (m *Dept) TableName() string {
+	return "dept"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Impersonation 管理员模拟登录的审计记录，每次签发模拟令牌对应一条
+type Impersonation struct {
+	Id           uint   `gorm:"primarykey"`
+	ActorId      string `gorm:"index;not null"` // 发起模拟的管理员
+	TargetUserId string `gorm:"index;not null"` // 被模拟的用户
+	TenantId     uint   `gorm:"index;not null;default:0"` // 与被模拟的用户相同
+	Reason       string `gorm:"not null"`
+	Jti          string `gorm:"size:64;uniqueIndex;not null"` // 模拟令牌的jti
+	Ip           string
+	UserAgent    string
+	ExpiresAt    time.Time
+	EndedAt      *time.Time // 主动结束模拟的时间，为空表示直到令牌过期
+	CreatedAt    time.Time  // 开始模拟的时间
+	UpdatedAt    time.Time
+}
+
+func (m *Impersonation) TableName() string {
+	return "impersonation"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// LoginAttempt 登录失败计数，Subject为account:用户ID或ip:客户端IP
+type LoginAttempt struct {
+	Id           uint   `gorm:"primarykey"`
+	Subject      string `gorm:"unique;not null"`
+	Failures     int    `gorm:"not null;default:0"`
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (m *LoginAttempt) TableName() string {
+	return "login_attempt"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// PasswordHistory 用户使用过的密码哈希，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	Id           uint   `gorm:"primarykey"`
+	UserId       string `gorm:"index;not null"`
+	PasswordHash string `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+func (m *PasswordHistory) TableName() string {
+	return "password_history"
+}
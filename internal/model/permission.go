@@ -13,8 +13,8 @@ type Permission struct {
 	Icon           string        // 菜单图标
 	Route          string        // 路由地址
 	RouteFile      string        // 路由地址对应前端文件
-	Path           string        // 有权访问的路径
-	Method         string        // 有权访问的方法
+	Path           string        // 有权访问的路径，以/开头时匹配完整路由模板并支持*通配，否则匹配/:api分组中的api参数
+	Method         string        // 有权访问的方法，多个用逗号分隔，all或为空表示全部方法
 	Sort           string        `gorm:"index"` // 菜单排序
 	Children       []*Permission `gorm:"foreignKey:ParentId;references:ParentId"`
 	CreatedAt      string
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// RefreshToken 刷新令牌，同一次登录轮换出的令牌共享FamilyId
+type RefreshToken struct {
+	Id        uint       `gorm:"primarykey"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null"` // 令牌sha256摘要
+	FamilyId  string     `gorm:"index;not null"`               // 令牌族
+	UserId    string     `gorm:"index;not null"`
+	ExpiresAt time.Time  // 过期时间
+	UsedAt    *time.Time // 已被轮换的时间，再次使用视为重放
+	RevokedAt *time.Time // 吊销时间
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m *RefreshToken) TableName() string {
+	return "refresh_token"
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// RevokedToken 已吊销的访问令牌
+type RevokedToken struct {
+	Id        uint      `gorm:"primarykey"`
+	Jti       string    `gorm:"size:64;uniqueIndex;not null"`
+	UserId    string    `gorm:"index;not null"`
+	ExpiresAt time.Time `gorm:"index"` // 令牌原本的过期时间，之后记录可清理
+	CreatedAt time.Time
+}
+
+func (m *RevokedToken) TableName() string {
+	return "revoked_token"
+}
+
+// UserTokenRevocation 用户级吊销，签发时间不晚于RevokedBefore的令牌全部失效
+type UserTokenRevocation struct {
+	Id            uint   `gorm:"primarykey"`
+	UserId        string `gorm:"uniqueIndex;not null"`
+	RevokedBefore time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (m *UserTokenRevocation) TableName() string {
+	return "user_token_revocation"
+}
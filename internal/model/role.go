@@ -5,15 +5,24 @@ import (
 	"time"
 )
 
+// DefaultRoleLabel 注册用户默认获得的角色，不能删除或修改标识
+const DefaultRoleLabel = "normal"
+
 type Role struct {
 	Id          uint   `gorm:"primarykey;auto_increment"`
-	RoleLabel   string `gorm:"unique"`
+	TenantId    uint   `gorm:"uniqueIndex:idx_role_tenant_label;not null;default:0"` // 所属租户，标识在租户内唯一
+	RoleLabel   string `gorm:"uniqueIndex:idx_role_tenant_label;size:64"`
 	RoleName    string
+	ParentId    uint         `gorm:"index"` // 父角色，继承其全部权限，0表示没有父角色
 	Permissions []Permission `gorm:"many2many:role_permissions;"`
 	DeleteFlag  int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	TwoFactor   bool   `gorm:"not null;default:false"`       // 拥有该角色的用户必须启用两步验证
+	DataScope   string `gorm:"size:32;not null;default:all"` // 数据范围，见DataScopeAll等
+	// 拥有该角色的用户可以审批申请本角色的请求，0表示不能申请
+	ApproverRoleId uint `gorm:"index;not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 func (m *Role) TableName() string {
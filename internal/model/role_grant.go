@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// 临时授权的结束原因
+const (
+	RoleGrantEndExpired = "expired"
+	RoleGrantEndRevoked = "revoked"
+)
+
+// RoleGrant 限时授予用户的角色，只在[StartsAt, EndsAt)内生效；结束后保留记录用于审计
+type RoleGrant struct {
+	Id        uint      `gorm:"primarykey"`
+	TenantId  uint      `gorm:"index;not null;default:0"` // 所属租户
+	UserId    string    `gorm:"size:64;index:idx_role_grant_user;not null"`
+	RoleId    uint      `gorm:"index;not null"`
+	StartsAt  time.Time `gorm:"not null"`
+	EndsAt    time.Time `gorm:"index;not null"`
+	Reason    string    `gorm:"not null"`
+	GrantedBy string    `gorm:"size:64"` // 授权的管理员
+	// 授权结束的时间：到期时为EndsAt，提前撤销时为撤销的时间；为空表示还未结束
+	EndedAt   *time.Time `gorm:"index:idx_role_grant_user"`
+	EndReason string     `gorm:"size:16"` // 见RoleGrantEndExpired等
+	RevokedBy string     `gorm:"size:64"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m *RoleGrant) TableName() string {
+	return "role_grant"
+}
@@ -0,0 +1,17 @@
+package model
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// RolePermission 角色与权限的关联，Effect为deny时拒绝该权限，优先于任何角色的allow
+type RolePermission struct {
+	RoleId       uint   `gorm:"primaryKey"`
+	PermissionId uint   `gorm:"primaryKey"`
+	Effect       string `gorm:"size:8;not null;default:allow"`
+}
+
+func (m *RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// Session 登录会话，SessionId即刷新令牌的FamilyId
+type Session struct {
+	Id         uint       `gorm:"primarykey"`
+	SessionId  string     `gorm:"uniqueIndex;not null"`
+	UserId     string     `gorm:"index;not null"`
+	TenantId   uint       `gorm:"index;not null;default:0"` // 与所属用户相同，签发时写入
+	Device     string     // 客户端上报的设备名称
+	Ip         string     // 最近一次使用的IP
+	UserAgent  string     // 登录时的User-Agent
+	LastSeenAt time.Time  // 最近一次登录或刷新令牌的时间
+	ExpiresAt  time.Time  // 最新刷新令牌的过期时间
+	RevokedAt  *time.Time `gorm:"index"`
+	CreatedAt  time.Time  // 签发时间
+	UpdatedAt  time.Time
+}
+
+func (m *Session) TableName() string {
+	return "session"
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// PlatformTenantId 平台租户，注册和第三方登录创建的用户属于该租户，其中拥有权限的用户可以管理全部租户
+const PlatformTenantId uint = 0
+
+type Tenant struct {
+	Id        uint   `gorm:"primarykey"`
+	Code      string `gorm:"size:64;uniqueIndex;not null"` // 租户标识，删除后也不能复用
+	Name      string `gorm:"size:64;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (m *Tenant) TableName() string {
+	return "tenant"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// UserTwoFactor 用户的TOTP密钥，EnabledAt为空表示已生成密钥但尚未确认绑定
+type UserTwoFactor struct {
+	Id           uint   `gorm:"primarykey"`
+	UserId       string `gorm:"unique;not null"`
+	Secret       string `gorm:"not null"`
+	EnabledAt    *time.Time
+	LastUsedStep int64 // 最近一次通过校验的时间步，防止验证码重放
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (m *UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// RecoveryCode 一次性恢复码，只保存哈希值
+type RecoveryCode struct {
+	Id        uint   `gorm:"primarykey"`
+	UserId    string `gorm:"index;not null"`
+	CodeHash  string `gorm:"index;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (m *RecoveryCode) TableName() string {
+	return "recovery_code"
+}
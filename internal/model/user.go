@@ -6,15 +6,19 @@ import (
 )
 
 type User struct {
-	Id        uint   `gorm:"primarykey"`
-	UserId    string `gorm:"index;unique;not null"`
-	Nickname  string `gorm:"not null"`
-	Password  string `gorm:"not null"`
-	Email     string `gorm:"not null"`
-	Roles     []Role `gorm:"many2many:user_role;ForeignKey:UserId;AssociationForeignKey:Id;references:Id"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Id              uint   `gorm:"primarykey"`
+	UserId          string `gorm:"index;unique;not null"`
+	Nickname        string `gorm:"not null"`
+	Password        string `gorm:"not null"`
+	Email           string `gorm:"not null"`
+	EmailVerifiedAt *time.Time
+	ServiceAccount  bool   `gorm:"not null;default:false"`   // 服务账号不能登录，只能通过API密钥调用接口
+	DeptId          uint   `gorm:"index"`                    // 所属部门，0表示未分配部门
+	TenantId        uint   `gorm:"index;not null;default:0"` // 所属租户，见PlatformTenantId
+	Roles           []Role `gorm:"many2many:user_role;ForeignKey:UserId;AssociationForeignKey:Id;references:Id"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
 }
 
 func (u *User) TableName() string {
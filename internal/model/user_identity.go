@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// UserIdentity 用户在外部身份提供方的身份，Provider和Subject唯一确定一个外部用户
+type UserIdentity struct {
+	Id        uint   `gorm:"primarykey"`
+	Provider  string `gorm:"uniqueIndex:idx_user_identity_subject;size:64;not null"`
+	Subject   string `gorm:"uniqueIndex:idx_user_identity_subject;size:255;not null"`
+	UserId    string `gorm:"index;not null"`
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m *UserIdentity) TableName() string {
+	return "user_identity"
+}
+
+// OIDCState 发起单点登录时保存的state，回调时取出nonce和PKCE校验码，只能使用一次
+type OIDCState struct {
+	Id           uint   `gorm:"primarykey"`
+	State        string `gorm:"unique;size:64;not null"`
+	Nonce        string `gorm:"not null"`
+	CodeVerifier string `gorm:"not null"`
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+func (m *OIDCState) TableName() string {
+	return "oidc_state"
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const accessRequestListLimit = 100
+
+type AccessRequestRepository interface {
+	Create(ctx context.Context, request *model.AccessRequest) error
+	GetById(ctx context.Context, id uint) (*model.AccessRequest, error)
+	HasPending(ctx context.Context, userId string, roleId uint) (bool, error)
+	Transition(ctx context.Context, request *model.AccessRequest, status string, actorId string, decidedAt time.Time) (bool, error)
+	AddEvent(ctx context.Context, event *model.AccessRequestEvent) error
+	GetEvents(ctx context.Context, requestId uint) ([]model.AccessRequestEvent, error)
+	ListByUser(ctx context.Context, userId string) ([]model.AccessRequest, error)
+	ListPendingByApprover(ctx context.Context, approverRoleIds []uint, excludeUserId string) ([]model.AccessRequest, error)
+	List(ctx context.Context, status string, userId string, roleId uint, offset int, limit int) ([]model.AccessRequest, int64, error)
+	HasRole(ctx context.Context, userId string, roleId uint) (bool, error)
+	GetUserRoleIds(ctx context.Context, userId string) ([]uint, error)
+}
+
+func NewAccessRequestRepository(r *Repository) AccessRequestRepository {
+	return &accessRequestRepository{
+		Repository: r,
+	}
+}
+
+type accessRequestRepository struct {
+	*Repository
+}
+
+func (r *accessRequestRepository) Create(ctx context.Context, request *model.AccessRequest) error {
+	return r.DB(ctx).Create(request).Error
+}
+
+func (r *accessRequestRepository) GetById(ctx context.Context, id uint) (*model.AccessRequest, error) {
+	var request model.AccessRequest
+	if err := r.DB(ctx).Where("id = ?", id).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *accessRequestRepository) HasPending(ctx context.Context, userId string, roleId uint) (bool, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.AccessRequest{}).
+		Where("user_id = ? AND role_id = ? AND status = ?", userId, roleId, model.AccessRequestPending).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Transition 将待审批的申请改为status，申请已不是待审批状态时返回false；
+// 成功时同步更新request，GrantId需要在调用前设置
+func (r *accessRequestRepository) Transition(ctx context.Context, request *model.AccessRequest, status string, actorId string, decidedAt time.Time) (bool, error) {
+	result := r.DB(ctx).Model(&model.AccessRequest{}).
+		Where("id = ? AND status = ?", request.Id, model.AccessRequestPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"decided_by": actorId,
+			"decided_at": decidedAt,
+			"grant_id":   request.GrantId,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	request.Status = status
+	request.DecidedBy = actorId
+	request.DecidedAt = &decidedAt
+	return true, nil
+}
+
+func (r *accessRequestRepository) AddEvent(ctx context.Context, event *model.AccessRequestEvent) error {
+	return r.DB(ctx).Create(event).Error
+}
+
+func (r *accessRequestRepository) GetEvents(ctx context.Context, requestId uint) ([]model.AccessRequestEvent, error) {
+	var events []model.AccessRequestEvent
+	if err := r.DB(ctx).Where("request_id = ?", requestId).Order("id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListByUser 用户最近提交的申请
+func (r *accessRequestRepository) ListByUser(ctx context.Context, userId string) ([]model.AccessRequest, error) {
+	var requests []model.AccessRequest
+	if err := r.DB(ctx).Where("user_id = ?", userId).
+		Order("id DESC").Limit(accessRequestListLimit).Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ListPendingByApprover 申请的角色由approverRoleIds中任一角色审批的待审批申请，不包括excludeUserId提交的
+func (r *accessRequestRepository) ListPendingByApprover(ctx context.Context, approverRoleIds []uint, excludeUserId string) ([]model.AccessRequest, error) {
+	var requests []model.AccessRequest
+	if err := r.DB(ctx).
+		Joins("JOIN role ON role.id = access_request.role_id AND role.deleted_at IS NULL").
+		Where("role.approver_role_id IN ? AND access_request.status = ? AND access_request.user_id <> ?",
+			approverRoleIds, model.AccessRequestPending, excludeUserId).
+		Order("access_request.id ASC").Limit(accessRequestListLimit).
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// List 按条件分页查询申请，条件为空时不过滤
+func (r *accessRequestRepository) List(ctx context.Context, status string, userId string, roleId uint, offset int, limit int) ([]model.AccessRequest, int64, error) {
+	db := r.DB(ctx).Model(&model.AccessRequest{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if userId != "" {
+		db = db.Where("user_id = ?", userId)
+	}
+	if roleId != 0 {
+		db = db.Where("role_id = ?", roleId)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var requests []model.AccessRequest
+	if err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&requests).Error; err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
+
+// HasRole 用户是否直接拥有角色，不包括继承的角色和临时授权
+func (r *accessRequestRepository) HasRole(ctx context.Context, userId string, roleId uint) (bool, error) {
+	var count int64
+	if err := r.DB(ctx).Table("user_role").
+		Where("user_user_id = ? AND role_id = ?", userId, roleId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetUserRoleIds 用户当前拥有的全部角色，包括继承的角色和生效的临时授权
+func (r *accessRequestRepository) GetUserRoleIds(ctx context.Context, userId string) ([]uint, error) {
+	return r.userRoleIds(ctx, userId)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, key *model.ApiKey) error
+	GetByHash(ctx context.Context, keyHash string) (*model.ApiKey, error)
+	GetByKeyId(ctx context.Context, keyId string) (*model.ApiKey, error)
+	ListByUser(ctx context.Context, userId string) ([]*model.ApiKey, error)
+	Revoke(ctx context.Context, keyId string, revokedAt time.Time) error
+	Touch(ctx context.Context, keyId string, usedAt time.Time, ip string) error
+	GetApiPermissions(ctx context.Context, ids []uint) ([]model.Permission, error)
+}
+
+func NewApiKeyRepository(r *Repository) ApiKeyRepository {
+	return &apiKeyRepository{
+		Repository: r,
+	}
+}
+
+type apiKeyRepository struct {
+	*Repository
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.ApiKey) error {
+	return r.DB(ctx).Omit("Scopes.*").Create(key).Error
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*model.ApiKey, error) {
+	var key model.ApiKey
+	if err := r.DB(ctx).Preload("Scopes").Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByKeyId(ctx context.Context, keyId string) (*model.ApiKey, error) {
+	var key model.ApiKey
+	if err := r.DB(ctx).Where("key_id = ?", keyId).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUser 返回用户的全部密钥，包括已过期和已吊销的
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userId string) ([]*model.ApiKey, error) {
+	var keys []*model.ApiKey
+	if err := r.DB(ctx).Preload("Scopes").Where("user_id = ?", userId).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, keyId string, revokedAt time.Time) error {
+	return r.DB(ctx).Model(&model.ApiKey{}).
+		Where("key_id = ? AND revoked_at IS NULL", keyId).
+		Update("revoked_at", revokedAt).Error
+}
+
+// Touch 记录最近一次使用的时间和IP
+func (r *apiKeyRepository) Touch(ctx context.Context, keyId string, usedAt time.Time, ip string) error {
+	return r.DB(ctx).Model(&model.ApiKey{}).
+		Where("key_id = ?", keyId).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
+
+// GetApiPermissions 查询ids中类型为api的权限
+func (r *apiKeyRepository) GetApiPermissions(ctx context.Context, ids []uint) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if err := r.DB(ctx).Where("id IN ? AND permission_type = ?", ids, "api").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/pkg/jwt"
+	"context"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 与中间件保存登录信息时使用的key一致
+const ctxClaimsKey = "claims"
+
+// dataScope 调用者全部角色数据范围的并集
+type dataScope struct {
+	all     bool
+	deptIds []uint
+	userId  string // 包含本人数据时为调用者的用户ID
+}
+
+// DataScope 按调用者角色的数据范围过滤列表查询，deptColumn和userColumn为记录所属部门和所属用户的列，
+// 为空表示该表没有对应的列。ctx中没有调用者或调用者没有任何数据范围时不返回记录
+func (r *Repository) DataScope(ctx context.Context, deptColumn string, userColumn string) func(db *gorm.DB) *gorm.DB {
+	var scope *dataScope
+	return func(db *gorm.DB) *gorm.DB {
+		// 同一查询分页时会先后执行count和find，数据范围只解析一次
+		if scope == nil {
+			resolved, err := r.resolveDataScope(ctx)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			scope = resolved
+		}
+		if scope.all {
+			return db
+		}
+
+		var conditions []string
+		var args []interface{}
+		if deptColumn != "" && len(scope.deptIds) > 0 {
+			conditions = append(conditions, deptColumn+" IN ?")
+			args = append(args, scope.deptIds)
+		}
+		if userColumn != "" && scope.userId != "" {
+			conditions = append(conditions, userColumn+" = ?")
+			args = append(args, scope.userId)
+		}
+		if len(conditions) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where(strings.Join(conditions, " OR "), args...)
+	}
+}
+
+func (r *Repository) resolveDataScope(ctx context.Context) (*dataScope, error) {
+	scope := &dataScope{}
+	claims, ok := ctx.Value(ctxClaimsKey).(*jwt.MyCustomClaims)
+	if !ok || claims.UserId == "" {
+		return scope, nil
+	}
+	roleIds, err := r.userRoleIds(ctx, claims.UserId)
+	if err != nil || len(roleIds) == 0 {
+		return scope, err
+	}
+	var roles []model.Role
+	if err = r.DB(ctx).Select("id", "data_scope").Where("id IN ?", roleIds).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	var ownDept, children bool
+	var customRoleIds []uint
+	for _, role := range roles {
+		switch role.DataScope {
+		case model.DataScopeAll:
+			scope.all = true
+			return scope, nil
+		case model.DataScopeDept:
+			ownDept = true
+		case model.DataScopeDeptAndChildren:
+			ownDept, children = true, true
+		case model.DataScopeSelf:
+			scope.userId = claims.UserId
+		case model.DataScopeCustom:
+			customRoleIds = append(customRoleIds, role.Id)
+		}
+	}
+
+	seen := make(map[uint]bool)
+	addDept := func(id uint) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			scope.deptIds = append(scope.deptIds, id)
+		}
+	}
+	if ownDept {
+		var deptIds []uint
+		if err = r.DB(ctx).Model(&model.User{}).Where("user_id = ?", claims.UserId).Pluck("dept_id", &deptIds).Error; err != nil {
+			return nil, err
+		}
+		for _, deptId := range deptIds {
+			addDept(deptId)
+		}
+		if children && len(scope.deptIds) > 0 {
+			parents, err := r.deptParents(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, deptId := range DescendantDeptIds(parents, scope.deptIds) {
+				addDept(deptId)
+			}
+		}
+	}
+	if len(customRoleIds) > 0 {
+		var deptIds []uint
+		if err = r.DB(ctx).Model(&model.RoleDept{}).Where("role_id IN ?", customRoleIds).Pluck("dept_id", &deptIds).Error; err != nil {
+			return nil, err
+		}
+		for _, deptId := range deptIds {
+			addDept(deptId)
+		}
+	}
+	return scope, nil
+}
+
+func (r *Repository) deptParents(ctx context.Context) (map[uint]uint, error) {
+	var depts []model.Dept
+	if err := r.DB(ctx).Select("id", "parent_id").Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	parents := make(map[uint]uint, len(depts))
+	for _, dept := range depts {
+		parents[dept.Id] = dept.ParentId
+	}
+	return parents, nil
+}
+
+// DescendantDeptIds 返回ids的全部下级部门，不包括ids本身
+func DescendantDeptIds(parents map[uint]uint, ids []uint) []uint {
+	children := make(map[uint][]uint, len(parents))
+	for id, parentId := range parents {
+		children[parentId] = append(children[parentId], id)
+	}
+	for _, ids := range children {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	var result []uint
+	queue := append([]uint(nil), ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if !seen[child] {
+				seen[child] = true
+				result = append(result, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type DeptRepository interface {
+	Create(ctx context.Context, dept *model.Dept) error
+	Update(ctx context.Context, dept *model.Dept) error
+	GetById(ctx context.Context, id uint) (*model.Dept, error)
+	List(ctx context.Context) ([]model.Dept, error)
+	GetParents(ctx context.Context) (map[uint]uint, error)
+	CountChildren(ctx context.Context, id uint) (int64, error)
+	CountUsers(ctx context.Context, id uint) (int64, error)
+	Delete(ctx context.Context, id uint) error
+	SetUsersDept(ctx context.Context, userIds []string, deptId uint) error
+	RemoveUsers(ctx context.Context, deptId uint, userIds []string) error
+	ListUsers(ctx context.Context, deptIds []uint, offset int, limit int) ([]model.User, int64, error)
+}
+
+func NewDeptRepository(r *Repository) DeptRepository {
+	return &deptRepository{
+		Repository: r,
+	}
+}
+
+type deptRepository struct {
+	*Repository
+}
+
+func (r *deptRepository) Create(ctx context.Context, dept *model.Dept) error {
+	return r.DB(ctx).Create(dept).Error
+}
+
+func (r *deptRepository) Update(ctx context.Context, dept *model.Dept) error {
+	return r.DB(ctx).Model(dept).Select("parent_id", "dept_name", "leader_id", "sort", "status").Updates(dept).Error
+}
+
+func (r *deptRepository) GetById(ctx context.Context, id uint) (*model.Dept, error) {
+	var dept model.Dept
+	if err := r.DB(ctx).Where("id = ?", id).First(&dept).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &dept, nil
+}
+
+// List 返回全部部门，按sort和id升序排列
+func (r *deptRepository) List(ctx context.Context) ([]model.Dept, error) {
+	var depts []model.Dept
+	if err := r.DB(ctx).Order("sort ASC, id ASC").Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	return depts, nil
+}
+
+// GetParents 返回全部未删除部门的上级部门id
+func (r *deptRepository) GetParents(ctx context.Context) (map[uint]uint, error) {
+	return r.deptParents(ctx)
+}
+
+func (r *deptRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.Dept{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *deptRepository) CountUsers(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.User{}).Where("dept_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Delete 删除部门并从角色的自定义数据范围中移除，需要在事务中调用
+// Delete role_dept没有租户字段，只在部门属于当前租户并已删除时清理关联
+func (r *deptRepository) Delete(ctx context.Context, id uint) error {
+	result := r.DB(ctx).Delete(&model.Dept{}, id)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return r.DB(ctx).Where("dept_id = ?", id).Delete(&model.RoleDept{}).Error
+}
+
+// SetUsersDept 用户只属于一个部门，加入新部门时离开原部门
+func (r *deptRepository) SetUsersDept(ctx context.Context, userIds []string, deptId uint) error {
+	return r.DB(ctx).Model(&model.User{}).Where("user_id IN ?", userIds).Update("dept_id", deptId).Error
+}
+
+// RemoveUsers 只移除属于该部门的用户，其他用户不受影响
+func (r *deptRepository) RemoveUsers(ctx context.Context, deptId uint, userIds []string) error {
+	return r.DB(ctx).Model(&model.User{}).Where("dept_id = ? AND user_id IN ?", deptId, userIds).Update("dept_id", 0).Error
+}
+
+// ListUsers 分页查询部门成员，只返回调用者数据范围内的用户
+func (r *deptRepository) ListUsers(ctx context.Context, deptIds []uint, offset int, limit int) ([]model.User, int64, error) {
+	db := r.DB(ctx).Model(&model.User{}).
+		Where("users.dept_id IN ?", deptIds).
+		Scopes(r.DataScope(ctx, "users.dept_id", "users.user_id"))
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []model.User
+	if err := db.Order("users.id ASC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
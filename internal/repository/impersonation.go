@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"admin-webrtc-go/internal/model"
+	"context"
+	"time"
+)
+
+const impersonationListLimit = 100
+
+type ImpersonationRepository interface {
+	Create(ctx context.Context, impersonation *model.Impersonation) error
+	End(ctx context.Context, jti string, endedAt time.Time) error
+	List(ctx context.Context, userId string) ([]model.Impersonation, error)
+}
+
+func NewImpersonationRepository(r *Repository) ImpersonationRepository {
+	return &impersonationRepository{
+		Repository: r,
+	}
+}
+
+type impersonationRepository struct {
+	*Repository
+}
+
+func (r *impersonationRepository) Create(ctx context.Context, impersonation *model.Impersonation) error {
+	if err := r.DB(ctx).Create(impersonation).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *impersonationRepository) End(ctx context.Context, jti string, endedAt time.Time) error {
+	return r.DB(ctx).Model(&model.Impersonation{}).
+		Where("jti = ? AND ended_at IS NULL", jti).
+		Update("ended_at", endedAt).Error
+}
+
+// List 最近的模拟记录，userId不为空时只返回该用户发起或被模拟的记录
+func (r *impersonationRepository) List(ctx context.Context, userId string) ([]model.Impersonation, error) {
+	var list []model.Impersonation
+	db := r.DB(ctx)
+	if userId != "" {
+		db = db.Where("actor_id = ? OR target_user_id = ?", userId, userId)
+	}
+	if err := db.Order("id desc").Limit(impersonationListLimit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
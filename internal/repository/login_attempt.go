@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, subject string) (*model.LoginAttempt, error)
+	IncrFailures(ctx context.Context, subject string, failedAt time.Time) (int, error)
+	Lock(ctx context.Context, subject string, until time.Time) error
+	Delete(ctx context.Context, subject string) error
+}
+
+func NewLoginAttemptRepository(r *Repository) LoginAttemptRepository {
+	return &loginAttemptRepository{
+		Repository: r,
+	}
+}
+
+type loginAttemptRepository struct {
+	*Repository
+}
+
+// Get 没有失败记录时返回nil
+func (r *loginAttemptRepository) Get(ctx context.Context, subject string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	if err := r.DB(ctx).Where("subject = ?", subject).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// IncrFailures 原子地累加失败次数，返回累加后的次数
+func (r *loginAttemptRepository) IncrFailures(ctx context.Context, subject string, failedAt time.Time) (int, error) {
+	attempt := model.LoginAttempt{
+		Subject:      subject,
+		Failures:     1,
+		LastFailedAt: failedAt,
+	}
+	if err := r.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":       gorm.Expr("login_attempt.failures + 1"),
+			"last_failed_at": failedAt,
+			"updated_at":     failedAt,
+		}),
+	}).Create(&attempt).Error; err != nil {
+		return 0, err
+	}
+
+	var failures int
+	if err := r.DB(ctx).Model(&model.LoginAttempt{}).Where("subject = ?", subject).
+		Pluck("failures", &failures).Error; err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, subject string, until time.Time) error {
+	return r.DB(ctx).Model(&model.LoginAttempt{}).
+		Where("subject = ?", subject).
+		Update("locked_until", until).Error
+}
+
+func (r *loginAttemptRepository) Delete(ctx context.Context, subject string) error {
+	return r.DB(ctx).Where("subject = ?", subject).Delete(&model.LoginAttempt{}).Error
+}
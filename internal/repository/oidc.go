@@ -0,0 +1,107 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type OIDCRepository interface {
+	CreateState(ctx context.Context, state *model.OIDCState) error
+	TakeState(ctx context.Context, state string) (*model.OIDCState, error)
+	GetIdentity(ctx context.Context, provider string, subject string) (*model.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *model.UserIdentity) error
+	SyncRoles(ctx context.Context, userId string, managed []string, granted []string) error
+}
+
+func NewOIDCRepository(r *Repository) OIDCRepository {
+	return &oidcRepository{
+		Repository: r,
+	}
+}
+
+type oidcRepository struct {
+	*Repository
+}
+
+// CreateState 顺带清理已过期的state
+func (r *oidcRepository) CreateState(ctx context.Context, state *model.OIDCState) error {
+	if err := r.DB(ctx).Where("expires_at < ?", time.Now()).Delete(&model.OIDCState{}).Error; err != nil {
+		return err
+	}
+	return r.DB(ctx).Create(state).Error
+}
+
+// TakeState 取出并删除state，并发回调时只有一个请求能取到
+func (r *oidcRepository) TakeState(ctx context.Context, state string) (*model.OIDCState, error) {
+	var s model.OIDCState
+	if err := r.DB(ctx).Where("state = ?", state).First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	result := r.DB(ctx).Where("id = ?", s.Id).Delete(&model.OIDCState{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, v1.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, provider string, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	if err := r.DB(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *oidcRepository) CreateIdentity(ctx context.Context, identity *model.UserIdentity) error {
+	return r.DB(ctx).Create(identity).Error
+}
+
+// SyncRoles 授予granted中的角色，撤销managed中的其余角色，managed之外的角色不受影响
+func (r *oidcRepository) SyncRoles(ctx context.Context, userId string, managed []string, granted []string) error {
+	if len(managed) == 0 {
+		return nil
+	}
+	var roles []model.Role
+	if err := r.DB(ctx).Where("role_label IN ?", managed).Find(&roles).Error; err != nil {
+		return err
+	}
+	grantedSet := make(map[string]bool, len(granted))
+	for _, label := range granted {
+		grantedSet[label] = true
+	}
+
+	for _, role := range roles {
+		if !grantedSet[role.RoleLabel] {
+			if err := r.DB(ctx).Exec("DELETE FROM user_role WHERE user_user_id = ? AND role_id = ?", userId, role.Id).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		var count int64
+		if err := r.DB(ctx).Table("user_role").
+			Where("user_user_id = ? AND role_id = ?", userId, role.Id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := r.DB(ctx).Exec("INSERT INTO user_role (user_user_id, role_id) VALUES (?, ?)", userId, role.Id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
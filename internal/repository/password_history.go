@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"admin-webrtc-go/internal/model"
+	"context"
+)
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, history *model.PasswordHistory) error
+	ListRecent(ctx context.Context, userId string, limit int) ([]*model.PasswordHistory, error)
+	Prune(ctx context.Context, userId string, keep int) error
+}
+
+func NewPasswordHistoryRepository(r *Repository) PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		Repository: r,
+	}
+}
+
+type passwordHistoryRepository struct {
+	*Repository
+}
+
+func (r *passwordHistoryRepository) Create(ctx context.Context, history *model.PasswordHistory) error {
+	return r.DB(ctx).Create(history).Error
+}
+
+// ListRecent 按时间倒序返回最近limit条记录
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userId string, limit int) ([]*model.PasswordHistory, error) {
+	var histories []*model.PasswordHistory
+	if err := r.DB(ctx).Where("user_id = ?", userId).Order("id DESC").Limit(limit).Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// Prune 只保留最近keep条记录
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userId string, keep int) error {
+	// mysql不支持在IN子查询中使用LIMIT，先查出保留范围内最早一条的ID
+	var ids []uint
+	if err := r.DB(ctx).Model(&model.PasswordHistory{}).Where("user_id = ?", userId).
+		Order("id DESC").Offset(keep-1).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Where("user_id = ? AND id < ?", userId, ids[0]).Delete(&model.PasswordHistory{}).Error
+}
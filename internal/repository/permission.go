@@ -0,0 +1,94 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionRepository interface {
+	Create(ctx context.Context, permission *model.Permission) error
+	Update(ctx context.Context, permission *model.Permission) error
+	GetById(ctx context.Context, id uint) (*model.Permission, error)
+	List(ctx context.Context) ([]model.Permission, error)
+	CountChildren(ctx context.Context, id uint) (int64, error)
+	Move(ctx context.Context, id uint, parentId uint, sort string) error
+	ShiftLevel(ctx context.Context, ids []uint, delta int) error
+	UpdateSort(ctx context.Context, id uint, sort string) error
+	Delete(ctx context.Context, id uint) error
+}
+
+func NewPermissionRepository(r *Repository) PermissionRepository {
+	return &permissionRepository{
+		Repository: r,
+	}
+}
+
+type permissionRepository struct {
+	*Repository
+}
+
+func (r *permissionRepository) Create(ctx context.Context, permission *model.Permission) error {
+	return r.DB(ctx).Omit(clause.Associations).Create(permission).Error
+}
+
+// Update 只修改节点自身的属性，父节点和层级通过Move修改
+func (r *permissionRepository) Update(ctx context.Context, permission *model.Permission) error {
+	return r.DB(ctx).Model(permission).
+		Select("permission_name", "permission_type", "icon", "route", "route_file", "path", "method", "sort", "updated_at").
+		Updates(permission).Error
+}
+
+func (r *permissionRepository) GetById(ctx context.Context, id uint) (*model.Permission, error) {
+	var permission model.Permission
+	if err := r.DB(ctx).Where("id = ?", id).First(&permission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &permission, nil
+}
+
+// List 返回全部未删除的权限节点
+func (r *permissionRepository) List(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if err := r.DB(ctx).Order("sort ASC, id ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *permissionRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.Permission{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *permissionRepository) Move(ctx context.Context, id uint, parentId uint, sort string) error {
+	return r.DB(ctx).Model(&model.Permission{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"parent_id": parentId, "sort": sort}).Error
+}
+
+// ShiftLevel 移动节点后调整其及子孙节点的层级
+func (r *permissionRepository) ShiftLevel(ctx context.Context, ids []uint, delta int) error {
+	if len(ids) == 0 || delta == 0 {
+		return nil
+	}
+	return r.DB(ctx).Model(&model.Permission{}).Where("id IN ?", ids).
+		Update("level", gorm.Expr("level + ?", delta)).Error
+}
+
+func (r *permissionRepository) UpdateSort(ctx context.Context, id uint, sort string) error {
+	return r.DB(ctx).Model(&model.Permission{}).Where("id = ?", id).Update("sort", sort).Error
+}
+
+// Delete 软删除，已关联的角色和API密钥随即失去该权限
+func (r *permissionRepository) Delete(ctx context.Context, id uint) error {
+	return r.DB(ctx).Delete(&model.Permission{}, id).Error
+}
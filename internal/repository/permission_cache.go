@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	permissionCacheKeyPrefix  = "rbac:perm:"
+	permissionCacheGeneration = "rbac:perm:generation"
+
+	defaultPermissionCacheSize = 10000
+	defaultPermissionCacheTTL  = time.Minute
+)
+
+// ApiPermission 编译后的api权限，只保留匹配请求需要的字段
+type ApiPermission struct {
+	Path   string `json:"p"`
+	Method string `json:"m"`
+	Deny   bool   `json:"d,omitempty"`
+}
+
+// PermissionCacheStats 权限缓存的累计统计
+type PermissionCacheStats struct {
+	Enabled       bool
+	Redis         bool
+	Size          int
+	Capacity      int
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+}
+
+// PermissionLoader 查询用户的api权限，validUntil不为零时权限只在该时间之前有效，如临时授权的开始或结束时间
+type PermissionLoader func(ctx context.Context, userId string) (permissions []ApiPermission, validUntil time.Time, err error)
+
+type PermissionCacheRepository interface {
+	Load(ctx context.Context, userId string, load PermissionLoader) ([]ApiPermission, error)
+	Invalidate(ctx context.Context, userIds ...string)
+	InvalidateAll(ctx context.Context)
+	Stats() PermissionCacheStats
+}
+
+// NewPermissionCacheRepository 按用户缓存api权限，进程内使用带过期时间的LRU；
+// 开启security.rbac.cache.redis后多实例通过redis共享缓存，其他实例的进程内缓存最多滞后一个ttl
+func NewPermissionCacheRepository(r *Repository, conf *viper.Viper) PermissionCacheRepository {
+	size := defaultPermissionCacheSize
+	if conf.IsSet("security.rbac.cache.size") {
+		size = conf.GetInt("security.rbac.cache.size")
+	}
+	ttl := conf.GetDuration("security.rbac.cache.ttl")
+	if ttl <= 0 {
+		ttl = defaultPermissionCacheTTL
+	}
+	repo := &permissionCacheRepository{
+		Repository: r,
+		enabled:    !conf.IsSet("security.rbac.cache.enabled") || conf.GetBool("security.rbac.cache.enabled"),
+		ttl:        ttl,
+		lru:        newPermissionLRU(size),
+	}
+	if repo.enabled && conf.GetBool("security.rbac.cache.redis") {
+		repo.rdb = NewRedis(conf)
+	}
+	return repo
+}
+
+type permissionCacheRepository struct {
+	*Repository
+	enabled bool
+	ttl     time.Duration
+	rdb     *redis.Client
+	lru     *permissionLRU
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+// Load 依次查询进程内缓存和redis，都未命中时调用load并写入缓存，redis出错时按未命中处理；
+// 缓存的有效期不超过load返回的validUntil，临时授权开始或结束后各实例都会重新查库
+func (r *permissionCacheRepository) Load(ctx context.Context, userId string, load PermissionLoader) ([]ApiPermission, error) {
+	if !r.enabled {
+		permissions, _, err := load(ctx, userId)
+		return permissions, err
+	}
+	if permissions, ok := r.lru.get(userId); ok {
+		r.hits.Add(1)
+		return permissions, nil
+	}
+	// 加载期间发生过清除时不写入缓存，避免把清除前查到的旧权限缓存下来
+	version := r.lru.currentVersion()
+	if r.rdb != nil {
+		permissions, ttl, err := r.fromRedis(ctx, userId)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			r.logger.WithContext(ctx).Warn("permission cache redis get error", zap.Error(err))
+		}
+		if err == nil {
+			r.lru.set(userId, permissions, ttl, version)
+			r.hits.Add(1)
+			return permissions, nil
+		}
+	}
+	r.misses.Add(1)
+
+	permissions, validUntil, err := load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	ttl := r.ttl
+	if !validUntil.IsZero() {
+		if d := time.Until(validUntil); d < ttl {
+			ttl = d
+		}
+	}
+	if ttl <= 0 {
+		return permissions, nil
+	}
+	if r.lru.set(userId, permissions, ttl, version) && r.rdb != nil {
+		if err = r.toRedis(ctx, userId, permissions, ttl); err != nil {
+			r.logger.WithContext(ctx).Warn("permission cache redis set error", zap.Error(err))
+		}
+	}
+	return permissions, nil
+}
+
+// Invalidate 用户的角色发生变化时清除其缓存
+func (r *permissionCacheRepository) Invalidate(ctx context.Context, userIds ...string) {
+	if !r.enabled || len(userIds) == 0 {
+		return
+	}
+	r.invalidations.Add(1)
+	for _, userId := range userIds {
+		r.lru.delete(userId)
+	}
+	if r.rdb != nil {
+		keys := make([]string, 0, len(userIds))
+		generation := r.generation(ctx)
+		for _, userId := range userIds {
+			keys = append(keys, permissionCacheKey(generation, userId))
+		}
+		if err := r.rdb.Del(ctx, keys...).Err(); err != nil {
+			r.logger.WithContext(ctx).Warn("permission cache redis del error", zap.Error(err))
+		}
+	}
+}
+
+// InvalidateAll 角色或权限本身发生变化时影响的用户无法逐个确定，清除全部缓存；
+// redis中通过递增代数使旧的缓存键全部失效
+func (r *permissionCacheRepository) InvalidateAll(ctx context.Context) {
+	if !r.enabled {
+		return
+	}
+	r.invalidations.Add(1)
+	r.lru.clear()
+	if r.rdb != nil {
+		if err := r.rdb.Incr(ctx, permissionCacheGeneration).Err(); err != nil {
+			r.logger.WithContext(ctx).Warn("permission cache redis incr error", zap.Error(err))
+		}
+	}
+}
+
+func (r *permissionCacheRepository) Stats() PermissionCacheStats {
+	size, evictions := r.lru.stats()
+	return PermissionCacheStats{
+		Enabled:       r.enabled,
+		Redis:         r.rdb != nil,
+		Size:          size,
+		Capacity:      r.lru.capacity,
+		Hits:          r.hits.Load(),
+		Misses:        r.misses.Load(),
+		Evictions:     evictions,
+		Invalidations: r.invalidations.Load(),
+	}
+}
+
+func (r *permissionCacheRepository) generation(ctx context.Context) int64 {
+	generation, err := r.rdb.Get(ctx, permissionCacheGeneration).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		r.logger.WithContext(ctx).Warn("permission cache redis generation error", zap.Error(err))
+	}
+	return generation
+}
+
+// fromRedis 同时返回键的剩余有效期，写入进程内缓存时不超过redis中的有效期
+func (r *permissionCacheRepository) fromRedis(ctx context.Context, userId string) ([]ApiPermission, time.Duration, error) {
+	key := permissionCacheKey(r.generation(ctx), userId)
+	pipe := r.rdb.Pipeline()
+	get := pipe.Get(ctx, key)
+	pttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+	var permissions []ApiPermission
+	if err := json.Unmarshal([]byte(get.Val()), &permissions); err != nil {
+		return nil, 0, err
+	}
+	ttl := r.ttl
+	if d := pttl.Val(); d > 0 && d < ttl {
+		ttl = d
+	}
+	return permissions, ttl, nil
+}
+
+func (r *permissionCacheRepository) toRedis(ctx context.Context, userId string, permissions []ApiPermission, ttl time.Duration) error {
+	val, err := json.Marshal(permissions)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, permissionCacheKey(r.generation(ctx), userId), val, ttl).Err()
+}
+
+func permissionCacheKey(generation int64, userId string) string {
+	return permissionCacheKeyPrefix + strconv.FormatInt(generation, 10) + ":" + userId
+}
+
+// permissionLRU 容量有限的进程内缓存，超出容量时淘汰最久未使用的条目，capacity<=0表示不缓存
+type permissionLRU struct {
+	mu        sync.Mutex
+	capacity  int
+	items     map[string]*list.Element
+	order     *list.List
+	evictions uint64
+	version   uint64 // 每次清除时递增
+}
+
+type permissionLRUItem struct {
+	userId      string
+	permissions []ApiPermission
+	expireAt    time.Time
+}
+
+func newPermissionLRU(capacity int) *permissionLRU {
+	return &permissionLRU{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *permissionLRU) get(userId string) ([]ApiPermission, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[userId]
+	if !ok {
+		return nil, false
+	}
+	item := elem.Value.(*permissionLRUItem)
+	if time.Now().After(item.expireAt) {
+		c.order.Remove(elem)
+		delete(c.items, userId)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return item.permissions, true
+}
+
+func (c *permissionLRU) currentVersion() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+// set 读取版本后发生过清除时放弃写入，返回是否仍可写入共享缓存
+func (c *permissionLRU) set(userId string, permissions []ApiPermission, ttl time.Duration, version uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version {
+		return false
+	}
+	if c.capacity <= 0 {
+		return true
+	}
+	item := &permissionLRUItem{userId: userId, permissions: permissions, expireAt: time.Now().Add(ttl)}
+	if elem, ok := c.items[userId]; ok {
+		elem.Value = item
+		c.order.MoveToFront(elem)
+		return true
+	}
+	c.items[userId] = c.order.PushFront(item)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*permissionLRUItem).userId)
+		c.evictions++
+	}
+	return true
+}
+
+func (c *permissionLRU) delete(userId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	if elem, ok := c.items[userId]; ok {
+		c.order.Remove(elem)
+		delete(c.items, userId)
+	}
+}
+
+func (c *permissionLRU) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *permissionLRU) stats() (int, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len(), c.evictions
+}
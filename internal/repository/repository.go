@@ -16,6 +16,8 @@ import (
 
 const ctxTxKey = "TxKey"
 
+type afterCommitKey struct{}
+
 type Repository struct {
 	db *gorm.DB
 	//rdb    *redis.Client
@@ -27,6 +29,9 @@ func NewRepository(
 	db *gorm.DB,
 	// rdb *redis.Client,
 ) *Repository {
+	if db != nil && db.Callback().Create().Get("tenant:stamp") == nil {
+		_ = db.Callback().Create().Before("gorm:create").Register("tenant:stamp", stampTenant)
+	}
 	return &Repository{
 		db: db,
 		//rdb:    rdb,
@@ -44,21 +49,49 @@ func NewTransaction(r *Repository) Transaction {
 
 // DB return tx
 // If you need to create a Transaction, you must call DB(ctx) and Transaction(ctx,fn)
+// ctx中有登录信息时自动按调用者所属的租户过滤
 func (r *Repository) DB(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
 	v := ctx.Value(ctxTxKey)
 	if v != nil {
 		if tx, ok := v.(*gorm.DB); ok {
-			return tx
+			db = tx
 		}
 	}
-	return r.db.WithContext(ctx)
+	if tenantId, ok := TenantIdFromCtx(ctx); ok {
+		return db.Scopes(tenantScope(tenantId))
+	}
+	return db
 }
 
 func (r *Repository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	// 已处于事务中时直接复用外层事务
+	if _, ok := ctx.Value(ctxTxKey).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	var hooks []func()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		ctx = context.WithValue(ctx, ctxTxKey, tx)
+		ctx = context.WithValue(ctx, afterCommitKey{}, &hooks)
 		return fn(ctx)
 	})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// afterCommit 在最外层事务提交后执行fn，事务回滚时不执行，不在事务中时立即执行；
+// 用于写入缓存等无法随事务回滚的操作
+func (r *Repository) afterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
 }
 
 func NewDB(conf *viper.Viper, l *log.Logger) *gorm.DB {
@@ -75,15 +108,16 @@ func NewDB(conf *viper.Viper, l *log.Logger) *gorm.DB {
 	switch driver {
 	case "mysql":
 		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			Logger: logger,
+			Logger:         logger,
+			TranslateError: true,
 		})
 	case "postgres":
 		db, err = gorm.Open(postgres.New(postgres.Config{
 			DSN:                  dsn,
 			PreferSimpleProtocol: true, // disables implicit prepared statement usage
-		}), &gorm.Config{})
+		}), &gorm.Config{TranslateError: true})
 	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
 	default:
 		panic("unknown db driver")
 	}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	revokedJtiKeyPrefix     = "jwt:revoked:jti:"
+	revokedSessionKeyPrefix = "jwt:revoked:sid:"
+	revokedUserKeyPrefix    = "jwt:revoked:user:"
+
+	defaultRevocationCacheTTL = 30 * time.Second
+	// 与刷新令牌的默认有效期相同
+	defaultRevocationRedisTTL = 7 * 24 * time.Hour
+	// redis中表示未吊销的值
+	revocationRedisNone = "0"
+)
+
+type RevocationRepository interface {
+	RevokeToken(ctx context.Context, token *model.RevokedToken) error
+	RevokeSession(ctx context.Context, sessionId string, revokedAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userId string, before time.Time) error
+	IsRevoked(ctx context.Context, jti string, sessionId string, userId string, issuedAt time.Time) (bool, error)
+}
+
+// NewRevocationRepository 吊销记录以数据库为准，查询结果在进程内短暂缓存，多实例时其他实例最多延迟cache_ttl感知吊销；
+// 开启security.jwt.revocation.redis后多实例之间通过redis共享吊销状态，进程内只缓存不会再变化的令牌和会话吊销，吊销立即生效。
+// 缓存和redis都在事务提交后写入，redis中没有的key回源数据库后回填
+func NewRevocationRepository(r *Repository, conf *viper.Viper) RevocationRepository {
+	ttl := conf.GetDuration("security.jwt.revocation.cache_ttl")
+	if ttl <= 0 {
+		ttl = defaultRevocationCacheTTL
+	}
+	// 会话和用户级吊销在刷新令牌有效期内都可能被用到
+	redisTTL := conf.GetDuration("security.jwt.refresh_expire")
+	if redisTTL <= 0 {
+		redisTTL = defaultRevocationRedisTTL
+	}
+	repo := &revocationRepository{
+		Repository: r,
+		cache:      newRevocationCache(ttl),
+		redisTTL:   redisTTL,
+	}
+	if conf.GetBool("security.jwt.revocation.redis") {
+		repo.rdb = NewRedis(conf)
+	}
+	return repo
+}
+
+type revocationRepository struct {
+	*Repository
+	rdb      *redis.Client
+	cache    *revocationCache
+	redisTTL time.Duration // 会话和用户级吊销在redis中的保留时间
+}
+
+func (r *revocationRepository) RevokeToken(ctx context.Context, token *model.RevokedToken) error {
+	if err := r.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error; err != nil {
+		return err
+	}
+	key := revokedJtiKeyPrefix + token.Jti
+	r.afterCommit(ctx, func() {
+		if r.rdb != nil {
+			if ttl := time.Until(token.ExpiresAt); ttl > 0 {
+				r.setRedis(ctx, key, time.Now(), ttl)
+			}
+		}
+		r.cache.set(key, time.Now())
+	})
+	return nil
+}
+
+// RevokeSession 吊销会话，该会话下签发的访问令牌随之失效
+func (r *revocationRepository) RevokeSession(ctx context.Context, sessionId string, revokedAt time.Time) error {
+	if err := r.DB(ctx).Model(&model.Session{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionId).
+		Update("revoked_at", revokedAt).Error; err != nil {
+		return err
+	}
+	key := revokedSessionKeyPrefix + sessionId
+	r.afterCommit(ctx, func() {
+		if r.rdb != nil {
+			r.setRedis(ctx, key, revokedAt, r.redisTTL)
+		}
+		r.cache.set(key, revokedAt)
+	})
+	return nil
+}
+
+// RevokeUserTokens 用户级吊销，同时结束该用户的全部会话
+func (r *revocationRepository) RevokeUserTokens(ctx context.Context, userId string, before time.Time) error {
+	revocation := model.UserTokenRevocation{
+		UserId:        userId,
+		RevokedBefore: before,
+	}
+	if err := r.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "updated_at"}),
+	}).Create(&revocation).Error; err != nil {
+		return err
+	}
+	if err := r.DB(ctx).Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", before).Error; err != nil {
+		return err
+	}
+	key := revokedUserKeyPrefix + userId
+	r.afterCommit(ctx, func() {
+		if r.rdb != nil {
+			r.setRedis(ctx, key, before, r.redisTTL)
+			return
+		}
+		r.cache.set(key, before)
+	})
+	return nil
+}
+
+// IsRevoked 令牌本身或所属会话被吊销，或者签发时间不晚于用户级吊销时间
+func (r *revocationRepository) IsRevoked(ctx context.Context, jti string, sessionId string, userId string, issuedAt time.Time) (bool, error) {
+	if jti != "" {
+		revokedAt, err := r.lookup(ctx, revokedJtiKeyPrefix+jti, r.revokedAtByJti)
+		if err != nil {
+			return false, err
+		}
+		if !revokedAt.IsZero() {
+			return true, nil
+		}
+	}
+	if sessionId != "" {
+		revokedAt, err := r.lookup(ctx, revokedSessionKeyPrefix+sessionId, r.revokedAtBySession)
+		if err != nil {
+			return false, err
+		}
+		if !revokedAt.IsZero() {
+			return true, nil
+		}
+	}
+
+	before, err := r.lookup(ctx, revokedUserKeyPrefix+userId, r.revokedBeforeByUser)
+	if err != nil {
+		return false, err
+	}
+	// NumericDate精度为秒，同一秒内签发的令牌一并视为吊销
+	return !before.IsZero() && !issuedAt.After(before.Truncate(time.Second)), nil
+}
+
+// lookup 依次查询进程内缓存、redis、数据库，返回零值表示未吊销。
+// 使用redis时未吊销和用户级吊销时间都可能被其他实例改变，不缓存在进程内
+func (r *revocationRepository) lookup(ctx context.Context, key string, fromDB func(ctx context.Context, key string) (time.Time, error)) (time.Time, error) {
+	if v, ok := r.cache.get(key); ok {
+		return v, nil
+	}
+
+	var (
+		v     time.Time
+		found bool
+		err   error
+	)
+	if r.rdb != nil {
+		v, found, err = r.fromRedis(ctx, key)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	// redis中的key过期、写入失败或从未写入时以数据库为准
+	if !found {
+		if v, err = fromDB(ctx, key); err != nil {
+			return time.Time{}, err
+		}
+		if r.rdb != nil {
+			r.fillRedis(ctx, key, v)
+		}
+	}
+	if r.rdb == nil || (!v.IsZero() && !strings.HasPrefix(key, revokedUserKeyPrefix)) {
+		r.cache.set(key, v)
+	}
+	return v, nil
+}
+
+// fromRedis 返回key中的吊销时间，key不存在时found为false
+func (r *revocationRepository) fromRedis(ctx context.Context, key string) (time.Time, bool, error) {
+	val, err := r.rdb.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	if val == revocationRedisNone {
+		return time.Time{}, true, nil
+	}
+	if strings.HasPrefix(key, revokedJtiKeyPrefix) {
+		return time.Now(), true, nil
+	}
+	unix, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.Unix(unix, 0), true, nil
+}
+
+// setRedis 吊销已在数据库提交，写入失败时其他实例回源数据库，只记录日志
+func (r *revocationRepository) setRedis(ctx context.Context, key string, revokedAt time.Time, ttl time.Duration) {
+	if err := r.rdb.Set(ctx, key, revokedAt.Unix(), ttl).Err(); err != nil {
+		r.logger.WithContext(ctx).Warn("revocation redis set error", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// fillRedis 回填数据库的查询结果，未吊销的只保留cache_ttl；
+// 使用SetNX，避免覆盖回源期间其他请求刚写入的吊销
+func (r *revocationRepository) fillRedis(ctx context.Context, key string, revokedAt time.Time) {
+	var (
+		val interface{} = revocationRedisNone
+		ttl             = r.cache.ttl
+	)
+	if !revokedAt.IsZero() {
+		val, ttl = revokedAt.Unix(), r.redisTTL
+	}
+	if err := r.rdb.SetNX(ctx, key, val, ttl).Err(); err != nil {
+		r.logger.WithContext(ctx).Warn("revocation redis fill error", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func (r *revocationRepository) revokedAtByJti(ctx context.Context, key string) (time.Time, error) {
+	var token model.RevokedToken
+	if err := r.DB(ctx).Where("jti = ?", key[len(revokedJtiKeyPrefix):]).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return token.CreatedAt, nil
+}
+
+// revokedAtBySession SessionId全局唯一，不按租户过滤，避免会话吊销状态被租户条件遮住
+func (r *revocationRepository) revokedAtBySession(ctx context.Context, key string) (time.Time, error) {
+	var session model.Session
+	if err := r.DB(WithoutTenant(ctx)).Where("session_id = ?", key[len(revokedSessionKeyPrefix):]).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if session.RevokedAt == nil {
+		return time.Time{}, nil
+	}
+	return *session.RevokedAt, nil
+}
+
+func (r *revocationRepository) revokedBeforeByUser(ctx context.Context, key string) (time.Time, error) {
+	var revocation model.UserTokenRevocation
+	if err := r.DB(ctx).Where("user_id = ?", key[len(revokedUserKeyPrefix):]).First(&revocation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return revocation.RevokedBefore, nil
+}
+
+// revocationCache 进程内缓存，未吊销的结果同样缓存，避免每个请求都查库
+type revocationCache struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	items     map[string]revocationCacheItem
+	lastSweep time.Time
+}
+
+type revocationCacheItem struct {
+	value    time.Time
+	expireAt time.Time
+}
+
+func newRevocationCache(ttl time.Duration) *revocationCache {
+	return &revocationCache{
+		ttl:   ttl,
+		items: make(map[string]revocationCacheItem),
+	}
+}
+
+func (c *revocationCache) get(key string) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[key]
+	if !ok || time.Now().After(item.expireAt) {
+		return time.Time{}, false
+	}
+	return item.value, true
+}
+
+func (c *revocationCache) set(key string, value time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	// 每个ttl周期顺带清理一次过期条目，防止map无限增长
+	if now.Sub(c.lastSweep) > c.ttl {
+		for k, item := range c.items {
+			if now.After(item.expireAt) {
+				delete(c.items, k)
+			}
+		}
+		c.lastSweep = now
+	}
+	c.items[key] = revocationCacheItem{value: value, expireAt: now.Add(c.ttl)}
+}
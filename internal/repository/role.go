@@ -0,0 +1,346 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"github.com/duke-git/lancet/v2/slice"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// RolePermissionDetail 角色关联的权限及关联的effect
+type RolePermissionDetail struct {
+	model.Permission
+	Effect string
+}
+
+// RoleApiPermission 提供api权限的角色及关联的effect
+type RoleApiPermission struct {
+	RoleId uint
+	model.Permission
+	Effect string
+}
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *model.Role) error
+	Update(ctx context.Context, role *model.Role) error
+	GetById(ctx context.Context, id uint, withDeleted bool) (*model.Role, error)
+	List(ctx context.Context, keyword string, deleted bool, offset int, limit int) ([]model.Role, int64, error)
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	GetPermissions(ctx context.Context, roleId uint) ([]RolePermissionDetail, error)
+	AttachPermissions(ctx context.Context, roleId uint, permissionIds []uint, effect string) error
+	DetachPermissions(ctx context.Context, roleId uint, permissionIds []uint) error
+	GetByIds(ctx context.Context, ids []uint) ([]model.Role, error)
+	GetUserRoles(ctx context.Context, userId string) ([]model.Role, error)
+	AddUserRoles(ctx context.Context, userIds []string, roleIds []uint) error
+	RemoveUserRoles(ctx context.Context, userId string, roleIds []uint) error
+	ClearUserRoles(ctx context.Context, userId string) error
+	ListUsers(ctx context.Context, roleId uint, offset int, limit int) ([]model.User, int64, error)
+	GetParents(ctx context.Context) (map[uint]uint, error)
+	GetAssignedRoleIds(ctx context.Context, userId string) ([]uint, []uint, error)
+	GetApiPermissions(ctx context.Context, roleIds []uint) ([]RoleApiPermission, error)
+	GetDataScopeDepts(ctx context.Context, roleId uint) ([]uint, error)
+	SetDataScopeDepts(ctx context.Context, roleId uint, deptIds []uint) error
+}
+
+func NewRoleRepository(r *Repository) RoleRepository {
+	return &roleRepository{
+		Repository: r,
+	}
+}
+
+type roleRepository struct {
+	*Repository
+}
+
+// Create 标识与已删除的角色重复时同样返回ErrRoleLabelExists，需要先恢复该角色
+func (r *roleRepository) Create(ctx context.Context, role *model.Role) error {
+	if err := r.DB(ctx).Omit("Permissions").Create(role).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return v1.ErrRoleLabelExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *roleRepository) Update(ctx context.Context, role *model.Role) error {
+	err := r.DB(ctx).Model(role).Select("role_label", "role_name", "parent_id", "two_factor", "data_scope", "approver_role_id").Updates(role).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return v1.ErrRoleLabelExists
+	}
+	return err
+}
+
+// GetById withDeleted为true时也查询已删除的角色
+func (r *roleRepository) GetById(ctx context.Context, id uint, withDeleted bool) (*model.Role, error) {
+	db := r.DB(ctx)
+	if withDeleted {
+		db = db.Unscoped()
+	}
+	var role model.Role
+	if err := db.Where("id = ?", id).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// List deleted为true时只查询已删除的角色
+func (r *roleRepository) List(ctx context.Context, keyword string, deleted bool, offset int, limit int) ([]model.Role, int64, error) {
+	db := r.DB(ctx).Model(&model.Role{})
+	if deleted {
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		db = db.Where("role_label LIKE ? OR role_name LIKE ?", like, like)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var roles []model.Role
+	if err := db.Order("id ASC").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+		return nil, 0, err
+	}
+	return roles, total, nil
+}
+
+// Delete 软删除，保留用户和权限的关联以便恢复
+func (r *roleRepository) Delete(ctx context.Context, id uint) error {
+	return r.DB(ctx).Delete(&model.Role{}, id).Error
+}
+
+func (r *roleRepository) Restore(ctx context.Context, id uint) error {
+	return r.DB(ctx).Unscoped().Model(&model.Role{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *roleRepository) GetPermissions(ctx context.Context, roleId uint) ([]RolePermissionDetail, error) {
+	var permissions []RolePermissionDetail
+	if err := r.DB(ctx).Table("permission").
+		Select("permission.*, role_permissions.effect").
+		Joins("join role_permissions on role_permissions.permission_id = permission.id").
+		Where("role_permissions.role_id = ? AND permission.deleted_at IS NULL", roleId).
+		Order("permission.id ASC").
+		Scan(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// AttachPermissions 权限不存在时返回ErrPermissionNotFound，已关联的权限更新为新的effect
+func (r *roleRepository) AttachPermissions(ctx context.Context, roleId uint, permissionIds []uint, effect string) error {
+	var count int64
+	if err := r.DB(ctx).Model(&model.Permission{}).Where("id IN ?", permissionIds).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(permissionIds)) {
+		return v1.ErrPermissionNotFound
+	}
+	rows := make([]map[string]interface{}, 0, len(permissionIds))
+	for _, permissionId := range permissionIds {
+		rows = append(rows, map[string]interface{}{"role_id": roleId, "permission_id": permissionId, "effect": effect})
+	}
+	return r.DB(ctx).Table("role_permissions").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role_id"}, {Name: "permission_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"effect"}),
+	}).Create(rows).Error
+}
+
+func (r *roleRepository) DetachPermissions(ctx context.Context, roleId uint, permissionIds []uint) error {
+	return r.DB(ctx).Exec("DELETE FROM role_permissions WHERE role_id = ? AND permission_id IN ?", roleId, permissionIds).Error
+}
+
+// GetByIds 查询ids中未删除的角色
+func (r *roleRepository) GetByIds(ctx context.Context, ids []uint) ([]model.Role, error) {
+	var roles []model.Role
+	if err := r.DB(ctx).Where("id IN ?", ids).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetUserRoles(ctx context.Context, userId string) ([]model.Role, error) {
+	var roles []model.Role
+	if err := r.DB(ctx).
+		Joins("JOIN user_role ON user_role.role_id = role.id").
+		Where("user_role.user_user_id = ?", userId).
+		Order("role.id ASC").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// AddUserRoles 为每个用户关联全部角色，已关联的忽略
+func (r *roleRepository) AddUserRoles(ctx context.Context, userIds []string, roleIds []uint) error {
+	rows := make([]map[string]interface{}, 0, len(userIds)*len(roleIds))
+	for _, userId := range userIds {
+		for _, roleId := range roleIds {
+			rows = append(rows, map[string]interface{}{"user_user_id": userId, "role_id": roleId})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.DB(ctx).Table("user_role").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 500).Error
+}
+
+func (r *roleRepository) RemoveUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	return r.DB(ctx).Exec("DELETE FROM user_role WHERE user_user_id = ? AND role_id IN ?", userId, roleIds).Error
+}
+
+func (r *roleRepository) ClearUserRoles(ctx context.Context, userId string) error {
+	return r.DB(ctx).Exec("DELETE FROM user_role WHERE user_user_id = ?", userId).Error
+}
+
+// ListUsers 分页查询拥有该角色的用户，只返回调用者数据范围内的用户
+func (r *roleRepository) ListUsers(ctx context.Context, roleId uint, offset int, limit int) ([]model.User, int64, error) {
+	db := r.DB(ctx).Model(&model.User{}).
+		Joins("JOIN user_role ON user_role.user_user_id = users.user_id").
+		Where("user_role.role_id = ?", roleId).
+		Scopes(r.DataScope(ctx, "users.dept_id", "users.user_id"))
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []model.User
+	if err := db.Order("users.id ASC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// GetParents 返回全部未删除角色的父角色id
+func (r *roleRepository) GetParents(ctx context.Context) (map[uint]uint, error) {
+	return r.roleParents(ctx)
+}
+
+func (r *Repository) roleParents(ctx context.Context) (map[uint]uint, error) {
+	var roles []model.Role
+	if err := r.DB(ctx).Select("id", "parent_id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	parents := make(map[uint]uint, len(roles))
+	for _, role := range roles {
+		parents[role.Id] = role.ParentId
+	}
+	return parents, nil
+}
+
+// GetAssignedRoleIds 返回用户直接拥有的角色和当前生效的临时授权角色，不包括继承的角色
+func (r *roleRepository) GetAssignedRoleIds(ctx context.Context, userId string) ([]uint, []uint, error) {
+	return r.assignedRoleIds(ctx, userId)
+}
+
+// GetApiPermissions 返回角色自身关联的api权限，每个角色与权限的关联一行
+func (r *roleRepository) GetApiPermissions(ctx context.Context, roleIds []uint) ([]RoleApiPermission, error) {
+	var permissions []RoleApiPermission
+	if len(roleIds) == 0 {
+		return permissions, nil
+	}
+	if err := r.DB(ctx).Table("role_permissions").
+		Select("role_permissions.role_id, role_permissions.effect, permission.*").
+		Joins("join permission on role_permissions.permission_id = permission.id AND permission.deleted_at IS NULL").
+		Where("role_permissions.role_id IN ? AND permission.permission_type = ?", roleIds, "api").
+		Order("permission.id ASC, role_permissions.role_id ASC").
+		Scan(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// assignedRoleIds 返回用户直接拥有的角色和当前生效的临时授权角色，已删除的角色除外；
+// 临时授权按当前时间判断是否生效，不依赖定时任务结束授权
+func (r *Repository) assignedRoleIds(ctx context.Context, userId string) ([]uint, []uint, error) {
+	var direct []uint
+	if err := r.DB(ctx).Table("user_role").
+		Joins("join role on user_role.role_id = role.id AND role.deleted_at IS NULL").
+		Where("user_role.user_user_id = ?", userId).
+		Pluck("role.id", &direct).Error; err != nil {
+		return nil, nil, err
+	}
+	var granted []uint
+	now := time.Now()
+	if err := r.DB(ctx).Model(&model.RoleGrant{}).
+		Joins("join role on role_grant.role_id = role.id AND role.deleted_at IS NULL").
+		Where("role_grant.user_id = ? AND role_grant.ended_at IS NULL AND role_grant.starts_at <= ? AND role_grant.ends_at > ?", userId, now, now).
+		Pluck("role.id", &granted).Error; err != nil {
+		return nil, nil, err
+	}
+	return direct, granted, nil
+}
+
+// userRoleIds 返回用户直接拥有的角色、当前生效的临时授权角色及沿父角色继承的全部角色，角色已删除时不再向上继承
+func (r *Repository) userRoleIds(ctx context.Context, userId string) ([]uint, error) {
+	direct, granted, err := r.assignedRoleIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	direct = slice.Union(direct, granted)
+	if len(direct) == 0 {
+		return nil, nil
+	}
+	parents, err := r.roleParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return InheritedRoleIds(parents, direct), nil
+}
+
+// InheritedRoleIds 沿父角色展开ids，parents中不存在的角色视为已删除，遇到环时停止
+func InheritedRoleIds(parents map[uint]uint, ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		for id != 0 && !seen[id] {
+			parentId, ok := parents[id]
+			if !ok {
+				break
+			}
+			seen[id] = true
+			result = append(result, id)
+			id = parentId
+		}
+	}
+	return result
+}
+
+func (r *roleRepository) GetDataScopeDepts(ctx context.Context, roleId uint) ([]uint, error) {
+	var deptIds []uint
+	if err := r.DB(ctx).Model(&model.RoleDept{}).Where("role_id = ?", roleId).Order("dept_id ASC").Pluck("dept_id", &deptIds).Error; err != nil {
+		return nil, err
+	}
+	return deptIds, nil
+}
+
+// SetDataScopeDepts 替换角色的自定义部门，部门不存在时返回ErrDeptNotFound
+func (r *roleRepository) SetDataScopeDepts(ctx context.Context, roleId uint, deptIds []uint) error {
+	if len(deptIds) > 0 {
+		var count int64
+		if err := r.DB(ctx).Model(&model.Dept{}).Where("id IN ?", deptIds).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(deptIds) {
+			return v1.ErrDeptNotFound
+		}
+	}
+	if err := r.DB(ctx).Where("role_id = ?", roleId).Delete(&model.RoleDept{}).Error; err != nil {
+		return err
+	}
+	if len(deptIds) == 0 {
+		return nil
+	}
+	rows := make([]model.RoleDept, 0, len(deptIds))
+	for _, deptId := range deptIds {
+		rows = append(rows, model.RoleDept{RoleId: roleId, DeptId: deptId})
+	}
+	return r.DB(ctx).Create(&rows).Error
+}
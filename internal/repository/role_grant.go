@@ -0,0 +1,99 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const roleGrantListLimit = 100
+
+type RoleGrantRepository interface {
+	Create(ctx context.Context, grant *model.RoleGrant) error
+	GetById(ctx context.Context, id uint) (*model.RoleGrant, error)
+	ListByUser(ctx context.Context, userId string, ended bool) ([]model.RoleGrant, error)
+	Revoke(ctx context.Context, id uint, revokedBy string, endedAt time.Time) (bool, error)
+	EndExpired(ctx context.Context, now time.Time) ([]model.RoleGrant, error)
+}
+
+func NewRoleGrantRepository(r *Repository) RoleGrantRepository {
+	return &roleGrantRepository{
+		Repository: r,
+	}
+}
+
+type roleGrantRepository struct {
+	*Repository
+}
+
+func (r *roleGrantRepository) Create(ctx context.Context, grant *model.RoleGrant) error {
+	return r.DB(ctx).Create(grant).Error
+}
+
+func (r *roleGrantRepository) GetById(ctx context.Context, id uint) (*model.RoleGrant, error) {
+	var grant model.RoleGrant
+	if err := r.DB(ctx).Where("id = ?", id).First(&grant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// ListByUser 用户最近的临时授权，ended为false时只返回还未结束的
+func (r *roleGrantRepository) ListByUser(ctx context.Context, userId string, ended bool) ([]model.RoleGrant, error) {
+	var grants []model.RoleGrant
+	db := r.DB(ctx).Where("user_id = ?", userId)
+	if !ended {
+		db = db.Where("ended_at IS NULL")
+	}
+	if err := db.Order("id DESC").Limit(roleGrantListLimit).Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// Revoke 提前结束授权，授权已结束时返回false
+func (r *roleGrantRepository) Revoke(ctx context.Context, id uint, revokedBy string, endedAt time.Time) (bool, error) {
+	result := r.DB(ctx).Model(&model.RoleGrant{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"ended_at":   endedAt,
+			"end_reason": model.RoleGrantEndRevoked,
+			"revoked_by": revokedBy,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// EndExpired 结束now之前到期的授权，结束时间记为到期时间，返回本次结束的授权
+func (r *roleGrantRepository) EndExpired(ctx context.Context, now time.Time) ([]model.RoleGrant, error) {
+	var grants []model.RoleGrant
+	if err := r.DB(ctx).Where("ended_at IS NULL AND ends_at <= ?", now).Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, 0, len(grants))
+	for _, grant := range grants {
+		ids = append(ids, grant.Id)
+	}
+	if err := r.DB(ctx).Model(&model.RoleGrant{}).
+		Where("id IN ? AND ended_at IS NULL", ids).
+		Updates(map[string]interface{}{
+			"ended_at":   gorm.Expr("ends_at"),
+			"end_reason": model.RoleGrantEndExpired,
+		}).Error; err != nil {
+		return nil, err
+	}
+	for i := range grants {
+		endedAt := grants[i].EndsAt
+		grants[i].EndedAt = &endedAt
+		grants[i].EndReason = model.RoleGrantEndExpired
+	}
+	return grants, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	GetBySessionId(ctx context.Context, sessionId string) (*model.Session, error)
+	ListActiveByUser(ctx context.Context, userId string) ([]model.Session, error)
+	Touch(ctx context.Context, sessionId string, ip string, seenAt time.Time, expiresAt time.Time) error
+}
+
+func NewSessionRepository(r *Repository) SessionRepository {
+	return &sessionRepository{
+		Repository: r,
+	}
+}
+
+type sessionRepository struct {
+	*Repository
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *model.Session) error {
+	if err := r.DB(ctx).Create(session).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sessionRepository) GetBySessionId(ctx context.Context, sessionId string) (*model.Session, error) {
+	var session model.Session
+	if err := r.DB(ctx).Where("session_id = ?", sessionId).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUser 未吊销且未过期的会话，最近活跃的在前
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userId string) ([]model.Session, error) {
+	var sessions []model.Session
+	if err := r.DB(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, time.Now()).
+		Order("last_seen_at desc").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Touch 刷新令牌轮换时更新最近活跃时间和IP
+func (r *sessionRepository) Touch(ctx context.Context, sessionId string, ip string, seenAt time.Time, expiresAt time.Time) error {
+	if err := r.DB(ctx).Model(&model.Session{}).
+		Where("session_id = ?", sessionId).
+		Updates(map[string]interface{}{
+			"ip":           ip,
+			"last_seen_at": seenAt,
+			"expires_at":   expiresAt,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
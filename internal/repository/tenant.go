@@ -0,0 +1,145 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// TenantRepository 租户数据跨越全部租户，调用方需要传入WithoutTenant返回的ctx
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *model.Tenant) error
+	Update(ctx context.Context, tenant *model.Tenant) error
+	GetById(ctx context.Context, id uint) (*model.Tenant, error)
+	List(ctx context.Context, keyword string, offset int, limit int) ([]model.Tenant, int64, error)
+	Delete(ctx context.Context, id uint) error
+	CountUsers(ctx context.Context, id uint) (int64, error)
+	CreateDefaultRole(ctx context.Context, id uint) (*model.Role, error)
+	GetDefaultRole(ctx context.Context, id uint) (*model.Role, error)
+	MoveUsers(ctx context.Context, userIds []string, id uint, defaultRoleId uint) error
+}
+
+func NewTenantRepository(r *Repository) TenantRepository {
+	return &tenantRepository{
+		Repository: r,
+	}
+}
+
+type tenantRepository struct {
+	*Repository
+}
+
+// Create 标识与已删除的租户重复时同样返回ErrTenantCodeExists
+func (r *tenantRepository) Create(ctx context.Context, tenant *model.Tenant) error {
+	if err := r.DB(ctx).Create(tenant).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return v1.ErrTenantCodeExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *tenantRepository) Update(ctx context.Context, tenant *model.Tenant) error {
+	return r.DB(ctx).Model(tenant).Select("name").Updates(tenant).Error
+}
+
+func (r *tenantRepository) GetById(ctx context.Context, id uint) (*model.Tenant, error) {
+	var tenant model.Tenant
+	if err := r.DB(ctx).Where("id = ?", id).First(&tenant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (r *tenantRepository) List(ctx context.Context, keyword string, offset int, limit int) ([]model.Tenant, int64, error) {
+	db := r.DB(ctx).Model(&model.Tenant{})
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		db = db.Where("code LIKE ? OR name LIKE ?", like, like)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var tenants []model.Tenant
+	if err := db.Order("id ASC").Offset(offset).Limit(limit).Find(&tenants).Error; err != nil {
+		return nil, 0, err
+	}
+	return tenants, total, nil
+}
+
+// Delete 软删除租户及其角色和部门，需要在事务中调用
+func (r *tenantRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.DB(ctx).Where("tenant_id = ?", id).Delete(&model.Role{}).Error; err != nil {
+		return err
+	}
+	if err := r.DB(ctx).Where("tenant_id = ?", id).Delete(&model.Dept{}).Error; err != nil {
+		return err
+	}
+	return r.DB(ctx).Delete(&model.Tenant{}, id).Error
+}
+
+func (r *tenantRepository) CountUsers(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	if err := r.DB(ctx).Model(&model.User{}).Where("tenant_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateDefaultRole 创建租户的默认角色，与注册时创建的默认角色相同
+func (r *tenantRepository) CreateDefaultRole(ctx context.Context, id uint) (*model.Role, error) {
+	role := &model.Role{
+		TenantId:  id,
+		RoleLabel: model.DefaultRoleLabel,
+		RoleName:  "普通用户",
+	}
+	var permission model.Permission
+	err := r.DB(ctx).Where("path = ?", "auth_0").First(&permission).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		role.Permissions = []model.Permission{permission}
+	}
+	if err = r.DB(ctx).Create(role).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *tenantRepository) GetDefaultRole(ctx context.Context, id uint) (*model.Role, error) {
+	var role model.Role
+	if err := r.DB(ctx).Where("tenant_id = ? AND role_label = ?", id, model.DefaultRoleLabel).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// MoveUsers 将用户移到租户中：离开原部门，原租户的角色全部移除后获得新租户的默认角色，API密钥随之转移
+func (r *tenantRepository) MoveUsers(ctx context.Context, userIds []string, id uint, defaultRoleId uint) error {
+	if err := r.DB(ctx).Model(&model.User{}).Where("user_id IN ?", userIds).
+		Updates(map[string]interface{}{"tenant_id": id, "dept_id": 0}).Error; err != nil {
+		return err
+	}
+	if err := r.DB(ctx).Model(&model.ApiKey{}).Where("user_id IN ?", userIds).Update("tenant_id", id).Error; err != nil {
+		return err
+	}
+	if err := r.DB(ctx).Exec("DELETE FROM user_role WHERE user_user_id IN ?", userIds).Error; err != nil {
+		return err
+	}
+	rows := make([]map[string]interface{}, 0, len(userIds))
+	for _, userId := range userIds {
+		rows = append(rows, map[string]interface{}{"user_user_id": userId, "role_id": defaultRoleId})
+	}
+	return r.DB(ctx).Table("user_role").CreateInBatches(rows, 500).Error
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"admin-webrtc-go/pkg/jwt"
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type withoutTenantKey struct{}
+
+// WithoutTenant 返回不按租户过滤的ctx，只用于平台管理员跨租户的操作
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTenantKey{}, true)
+}
+
+// TenantIdFromCtx 返回调用者所属的租户；ctx中没有登录信息（登录、定时任务等）或使用WithoutTenant时返回false
+func TenantIdFromCtx(ctx context.Context) (uint, bool) {
+	if skip, _ := ctx.Value(withoutTenantKey{}).(bool); skip {
+		return 0, false
+	}
+	claims, ok := ctx.Value(ctxClaimsKey).(*jwt.MyCustomClaims)
+	if !ok {
+		return 0, false
+	}
+	return claims.TenantId, true
+}
+
+// tenantScope 查询、更新和删除包含TenantId字段的模型时只作用于该租户的记录；
+// 通过Table查询到其他结构体或Pluck到基本类型时无法识别模型，需要自行按租户过滤
+func tenantScope(tenantId uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		stmt := db.Statement
+		value := stmt.Model
+		if value == nil {
+			value = stmt.Dest
+		}
+		if value == nil || stmt.Parse(value) != nil {
+			return db
+		}
+		field := stmt.Schema.LookUpField("TenantId")
+		if field == nil {
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: stmt.Table, Name: field.DBName}, Value: tenantId})
+	}
+}
+
+// stampTenant 创建记录时未指定租户的设置为调用者所属的租户
+func stampTenant(db *gorm.DB) {
+	stmt := db.Statement
+	tenantId, ok := TenantIdFromCtx(stmt.Context)
+	if !ok || tenantId == 0 || stmt.Schema == nil {
+		return
+	}
+	field := stmt.Schema.LookUpField("TenantId")
+	if field == nil {
+		return
+	}
+	stamp := func(rv reflect.Value) {
+		if _, zero := field.ValueOf(stmt.Context, rv); zero {
+			_ = field.Set(stmt.Context, rv, tenantId)
+		}
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			stamp(reflect.Indirect(stmt.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		stamp(stmt.ReflectValue)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyId string, revokedAt time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userId string, revokedAt time.Time) error
+}
+
+func NewTokenRepository(r *Repository) TokenRepository {
+	return &tokenRepository{
+		Repository: r,
+	}
+}
+
+type tokenRepository struct {
+	*Repository
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	if err := r.DB(ctx).Create(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.DB(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed 标记令牌已被轮换，只有未使用过的令牌才能更新成功，
+// 返回false说明令牌已被并发请求使用
+func (r *tokenRepository) MarkRefreshTokenUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	result := r.DB(ctx).Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeRefreshTokenFamily 吊销整个令牌族
+func (r *tokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyId string, revokedAt time.Time) error {
+	if err := r.DB(ctx).Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		Update("revoked_at", revokedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens 吊销用户的全部刷新令牌
+func (r *tokenRepository) RevokeUserRefreshTokens(ctx context.Context, userId string, revokedAt time.Time) error {
+	if err := r.DB(ctx).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", revokedAt).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	Get(ctx context.Context, userId string) (*model.UserTwoFactor, error)
+	Save(ctx context.Context, twoFactor *model.UserTwoFactor) error
+	Enable(ctx context.Context, userId string, enabledAt time.Time) error
+	Delete(ctx context.Context, userId string) error
+	UseStep(ctx context.Context, userId string, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userId string, codeHash string, usedAt time.Time) (bool, error)
+	RequiredByRole(ctx context.Context, userId string) (bool, error)
+}
+
+func NewTwoFactorRepository(r *Repository) TwoFactorRepository {
+	return &twoFactorRepository{
+		Repository: r,
+	}
+}
+
+type twoFactorRepository struct {
+	*Repository
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userId string) (*model.UserTwoFactor, error) {
+	var twoFactor model.UserTwoFactor
+	if err := r.DB(ctx).Where("user_id = ?", userId).First(&twoFactor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrNotFound
+		}
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// Save 按用户覆盖密钥，重新生成密钥时绑定状态随之重置
+func (r *twoFactorRepository) Save(ctx context.Context, twoFactor *model.UserTwoFactor) error {
+	return r.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_used_step", "updated_at"}),
+	}).Create(twoFactor).Error
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userId string, enabledAt time.Time) error {
+	return r.DB(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ?", userId).
+		Update("enabled_at", enabledAt).Error
+}
+
+// Delete 删除密钥及全部恢复码
+func (r *twoFactorRepository) Delete(ctx context.Context, userId string) error {
+	if err := r.DB(ctx).Where("user_id = ?", userId).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return r.DB(ctx).Where("user_id = ?", userId).Delete(&model.UserTwoFactor{}).Error
+}
+
+// UseStep 记录通过校验的时间步，返回false表示该时间步或更晚的验证码已经用过
+func (r *twoFactorRepository) UseStep(ctx context.Context, userId string, step int64) (bool, error) {
+	result := r.DB(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userId, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) error {
+	if err := r.DB(ctx).Where("user_id = ?", userId).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]model.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.RecoveryCode{
+			UserId:   userId,
+			CodeHash: hash,
+		})
+	}
+	return r.DB(ctx).Create(&codes).Error
+}
+
+// UseRecoveryCode 核销恢复码，返回false表示恢复码不存在或已使用
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userId string, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.DB(ctx).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RequiredByRole 用户拥有或继承的任一角色要求两步验证
+func (r *twoFactorRepository) RequiredByRole(ctx context.Context, userId string) (bool, error) {
+	roleIds, err := r.userRoleIds(ctx, userId)
+	if err != nil || len(roleIds) == 0 {
+		return false, err
+	}
+	var count int64
+	if err := r.DB(ctx).Model(&model.Role{}).
+		Where("id IN ? AND two_factor = ?", roleIds, true).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
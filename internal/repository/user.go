@@ -7,15 +7,20 @@ import (
 	"errors"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, user *model.User) error
 	GetByID(ctx context.Context, id string) (*model.User, error)
+	GetByIDs(ctx context.Context, ids []string) ([]model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	GetUserWithRolesAndPermission(ctx context.Context, userId string, permissionType string, sort string) (*[]LoginedUser, error)
+	NextRoleGrantChange(ctx context.Context, userId string, now time.Time) (time.Time, error)
 	GetUserDefaultSeed(ctx context.Context, user *model.User) error
+	GetRolesByLabels(ctx context.Context, labels []string) ([]model.Role, error)
+	GetUserRoleIds(ctx context.Context, userId string) ([]uint, error)
 }
 
 func NewUserRepository(r *Repository) UserRepository {
@@ -39,7 +44,10 @@ type LoginedUser struct {
 	Level          uint   `json:"level"`
 	Sort           string `json:"sort"`
 	ParentId       uint   `json:"parent_id"`
+	Icon           string `json:"icon"`
 	Path           string `json:"path"`
+	Method         string `json:"method"`
+	Effect         string `json:"effect"` // allow或deny
 	CreatedAt      string `json:"created_at"`
 	UpdatedAt      string `json:"updated_at"`
 }
@@ -73,6 +81,14 @@ func (r *userRepository) GetByID(ctx context.Context, userId string) (*model.Use
 	return &user, nil
 }
 
+func (r *userRepository) GetByIDs(ctx context.Context, userIds []string) ([]model.User, error) {
+	var users []model.User
+	if err := r.DB(ctx).Where("user_id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
 	if err := r.DB(ctx).Where("email = ?", email).First(&user).Error; err != nil {
@@ -84,8 +100,12 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.U
 	return &user, nil
 }
 
-// GetUserDefaultSeed 初始化数据库
+// GetUserDefaultSeed 初始化数据库，用户获得所属租户的默认角色；未指定租户时属于调用者所属的租户
 func (r *userRepository) GetUserDefaultSeed(ctx context.Context, user *model.User) error {
+	if user.TenantId == 0 {
+		user.TenantId, _ = TenantIdFromCtx(ctx)
+	}
+
 	// 初始化permission
 	permission := model.Permission{
 		PermissionName: "默认权限",
@@ -100,13 +120,15 @@ func (r *userRepository) GetUserDefaultSeed(ctx context.Context, user *model.Use
 
 	// 初始化role
 	role := model.Role{
-		RoleLabel: "normal",
+		TenantId:  user.TenantId,
+		RoleLabel: model.DefaultRoleLabel,
 		RoleName:  "普通用户",
 		Permissions: []model.Permission{
 			permission,
 		},
 	}
-	if err := r.DB(ctx).FirstOrCreate(&role, model.Role{RoleLabel: role.RoleLabel}).Error; err != nil {
+	// 平台租户的id为0，不能使用结构体作为条件
+	if err := r.DB(ctx).Where("tenant_id = ? AND role_label = ?", role.TenantId, role.RoleLabel).FirstOrCreate(&role).Error; err != nil {
 		r.logger.WithContext(ctx).Error("init default Role failed!", zap.Error(err))
 		return err
 	}
@@ -121,21 +143,63 @@ func (r *userRepository) GetUserDefaultSeed(ctx context.Context, user *model.Use
 	return nil
 }
 
+func (r *userRepository) GetRolesByLabels(ctx context.Context, labels []string) ([]model.Role, error) {
+	var roles []model.Role
+	if err := r.DB(ctx).Where("role_label IN ?", labels).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetUserRoleIds 用户当前拥有的全部角色，包括继承的角色和生效的临时授权
+func (r *userRepository) GetUserRoleIds(ctx context.Context, userId string) ([]uint, error) {
+	return r.userRoleIds(ctx, userId)
+}
+
+// NextRoleGrantChange 返回用户未结束的临时授权中now之后最近的开始或结束时间，没有时返回零值
+func (r *userRepository) NextRoleGrantChange(ctx context.Context, userId string, now time.Time) (time.Time, error) {
+	var grants []model.RoleGrant
+	if err := r.DB(ctx).Select("starts_at", "ends_at").
+		Where("user_id = ? AND ended_at IS NULL AND ends_at > ?", userId, now).
+		Find(&grants).Error; err != nil {
+		return time.Time{}, err
+	}
+	var next time.Time
+	for _, grant := range grants {
+		change := grant.EndsAt
+		if grant.StartsAt.After(now) {
+			change = grant.StartsAt
+		}
+		if next.IsZero() || change.Before(next) {
+			next = change
+		}
+	}
+	return next, nil
+}
+
 func (r *userRepository) GetUserWithRolesAndPermission(ctx context.Context, userId string, permissionType string, sort string) (*[]LoginedUser, error) {
 	if sort != "asc" && sort != "desc" {
 		sort = "asc"
 	}
 
-	// TODO 左连接查出当前user的所拥有的权限
+	// 用户的角色包括沿父角色继承的角色，继承的权限中role_id为父角色
+	roleIds, err := r.userRoleIds(ctx, userId)
+	if err != nil {
+		r.logger.WithContext(ctx).Error("databaseError!", zap.Error(err))
+		return nil, err
+	}
+	if len(roleIds) == 0 {
+		return nil, v1.ErrEmptyRecord
+	}
+
 	var users []LoginedUser
-	connect := r.DB(ctx).Table("users").Select("users.user_id, users.email, user_role.role_id, role.id, "+
-		"role.role_name, role.role_label, role_permissions.permission_id, permission.permission_type, permission.route, "+
+	connect := r.DB(ctx).Table("users").Select("users.user_id, users.email, role.id AS role_id, "+
+		"role.role_name, role.role_label, role_permissions.permission_id, role_permissions.effect, permission.permission_type, permission.route, "+
 		"permission.route_file, permission.level, permission.sort, permission.parent_id, permission.path, permission.created_at, "+
-		"permission.updated_at, permission.permission_name").
-		Joins("left join user_role on users.user_id = user_role.user_user_id").
-		Joins("left join role on user_role.role_id = role.id").
-		Joins("left join role_permissions on role.id = role_permissions.role_id").
-		Joins("left join permission on role_permissions.permission_id = permission.id").
+		"permission.updated_at, permission.permission_name, permission.method, permission.icon").
+		Joins("join role on role.id IN ?", roleIds).
+		Joins("join role_permissions on role.id = role_permissions.role_id").
+		Joins("join permission on role_permissions.permission_id = permission.id AND permission.deleted_at IS NULL").
 		Where("users.user_id = ? AND permission.permission_type = ?", userId, permissionType).
 		Scan(&users)
 	// 执行查询语句时的出现异常
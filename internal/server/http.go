@@ -21,7 +21,24 @@ func NewHTTPServer(
 	conf *viper.Viper,
 	jwt *jwt.JWT,
 	userHandler *handler.UserHandler,
+	tokenHandler *handler.TokenHandler,
+	sessionHandler *handler.SessionHandler,
+	twoFactorHandler *handler.TwoFactorHandler,
+	accountHandler *handler.AccountHandler,
+	oidcHandler *handler.OIDCHandler,
+	apiKeyHandler *handler.ApiKeyHandler,
+	impersonationHandler *handler.ImpersonationHandler,
+	rbacHandler *handler.RBACHandler,
+	roleHandler *handler.RoleHandler,
+	permissionHandler *handler.PermissionHandler,
+	deptHandler *handler.DeptHandler,
+	tenantHandler *handler.TenantHandler,
+	roleGrantHandler *handler.RoleGrantHandler,
+	accessRequestHandler *handler.AccessRequestHandler,
 	userService service.UserService,
+	tokenService service.TokenService,
+	apiKeyService service.ApiKeyService,
+	rbacService service.RBACService,
 ) *http.Server {
 	gin.SetMode(gin.DebugMode)
 	s := http.NewServer(
@@ -52,6 +69,8 @@ func NewHTTPServer(
 		})
 	})
 
+	s.GET("/.well-known/jwks.json", tokenHandler.JWKS)
+
 	v1 := s.Group("/v1")
 	{
 		// No route group has permission
@@ -59,15 +78,23 @@ func NewHTTPServer(
 		{
 			noAuthRouter.POST("/register", userHandler.Register)
 			noAuthRouter.POST("/login", userHandler.Login)
+			noAuthRouter.POST("/login/2fa", userHandler.LoginTwoFactor)
+			noAuthRouter.POST("/login/2fa/setup", userHandler.LoginTwoFactorSetup)
+			noAuthRouter.POST("/token/refresh", tokenHandler.Refresh)
+			noAuthRouter.POST("/email/verify", accountHandler.VerifyEmail)
+			noAuthRouter.POST("/password/forgot", accountHandler.ForgotPassword)
+			noAuthRouter.POST("/password/reset", accountHandler.ResetPassword)
+			noAuthRouter.GET("/oidc/authorize", oidcHandler.Authorize)
+			noAuthRouter.POST("/oidc/callback", oidcHandler.Callback)
 		}
 		// Non-strict permission routing group
-		noStrictAuthRouter := v1.Group("/").Use(middleware.NoStrictAuth(jwt, logger))
+		noStrictAuthRouter := v1.Group("/").Use(middleware.NoStrictAuth(jwt, tokenService, logger))
 		{
 			noStrictAuthRouter.GET("/user", userHandler.GetProfile)
 			noStrictAuthRouter.GET("/getMenuTree", userHandler.GetMenuTree)
 		}
 		// 需要非严格校验Api权限的分组
-		noStrictApiAuthRouter := v1.Group("/:api").Use(middleware.NoStrictAuth(jwt, logger), middleware.RBACAuth(jwt, userService, logger))
+		noStrictApiAuthRouter := v1.Group("/:api").Use(middleware.NoStrictAuth(jwt, tokenService, logger), middleware.RBACAuth(jwt, userService, tokenService, apiKeyService, logger))
 		{
 			noStrictApiAuthRouter.GET("/apiAuthTest", func(c *gin.Context) {
 				c.JSON(200, gin.H{
@@ -76,16 +103,105 @@ func NewHTTPServer(
 			})
 		}
 		// Strict permission routing group
-		strictAuthRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, logger))
+		// 账号安全和审批相关的操作不允许使用模拟令牌和API密钥
+		denyImpersonation := middleware.DenyImpersonation(logger)
+		denyApiKey := middleware.DenyApiKey(logger)
+		strictAuthRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, tokenService, apiKeyService, logger))
 		{
-			strictAuthRouter.PUT("/user", userHandler.UpdateProfile)
+			strictAuthRouter.PUT("/user", denyImpersonation, denyApiKey, userHandler.UpdateProfile)
+			strictAuthRouter.POST("/logout", tokenHandler.Logout)
+			strictAuthRouter.POST("/logout/all", denyImpersonation, denyApiKey, tokenHandler.LogoutAll)
+			strictAuthRouter.GET("/sessions", denyApiKey, sessionHandler.ListSessions)
+			strictAuthRouter.DELETE("/sessions/:sessionId", denyImpersonation, denyApiKey, sessionHandler.RevokeSession)
+			strictAuthRouter.POST("/user/2fa/setup", denyImpersonation, denyApiKey, twoFactorHandler.Setup)
+			strictAuthRouter.POST("/user/2fa/confirm", denyImpersonation, denyApiKey, twoFactorHandler.Confirm)
+			strictAuthRouter.POST("/user/2fa/disable", denyImpersonation, denyApiKey, twoFactorHandler.Disable)
+			strictAuthRouter.POST("/user/2fa/recovery-codes", denyImpersonation, denyApiKey, twoFactorHandler.RegenerateRecoveryCodes)
+			strictAuthRouter.POST("/email/verify/send", denyImpersonation, denyApiKey, accountHandler.SendVerifyEmail)
+			strictAuthRouter.POST("/password/change", denyImpersonation, denyApiKey, accountHandler.ChangePassword)
+			strictAuthRouter.POST("/api-keys", denyImpersonation, denyApiKey, apiKeyHandler.CreateApiKey)
+			strictAuthRouter.GET("/api-keys", denyApiKey, apiKeyHandler.ListApiKeys)
+			strictAuthRouter.DELETE("/api-keys/:keyId", denyImpersonation, denyApiKey, apiKeyHandler.RevokeApiKey)
+			strictAuthRouter.POST("/impersonation/stop", impersonationHandler.StopImpersonation)
+			strictAuthRouter.POST("/access-requests", denyImpersonation, denyApiKey, accessRequestHandler.CreateAccessRequest)
+			strictAuthRouter.GET("/user/access-requests", denyApiKey, accessRequestHandler.ListMyAccessRequests)
+			strictAuthRouter.GET("/access-requests/pending", denyApiKey, accessRequestHandler.ListPendingAccessRequests)
+			strictAuthRouter.GET("/access-requests/:requestId", denyApiKey, accessRequestHandler.GetAccessRequest)
+			strictAuthRouter.POST("/access-requests/:requestId/approve", denyImpersonation, denyApiKey, accessRequestHandler.ApproveAccessRequest)
+			strictAuthRouter.POST("/access-requests/:requestId/reject", denyImpersonation, denyApiKey, accessRequestHandler.RejectAccessRequest)
+			strictAuthRouter.POST("/access-requests/:requestId/cancel", denyImpersonation, denyApiKey, accessRequestHandler.CancelAccessRequest)
 		}
 		// 需要严格校验Api权限的分组
-		strictApiAuthRouter := v1.Group("/:api").Use(middleware.StrictAuth(jwt, logger), middleware.RBACAuth(jwt, userService, logger))
+		strictApiAuthRouter := v1.Group("/:api").Use(middleware.StrictAuth(jwt, tokenService, apiKeyService, logger), middleware.RBACAuth(jwt, userService, tokenService, apiKeyService, logger))
 		{
 			strictApiAuthRouter.GET("apiStrictAuthTest")
 		}
+		// 按完整路由模板和请求方法校验Api权限的分组，权限path如/v1/users/*
+		rbacRouter := v1.Group("/").Use(middleware.StrictAuth(jwt, tokenService, apiKeyService, logger), middleware.RBACAuth(jwt, userService, tokenService, apiKeyService, logger))
+		{
+			rbacRouter.POST("/users/:userId/impersonate", impersonationHandler.Impersonate)
+			rbacRouter.GET("/impersonations", impersonationHandler.ListImpersonations)
+			rbacRouter.GET("/users/:userId/sessions", sessionHandler.ListUserSessions)
+			rbacRouter.DELETE("/users/:userId/sessions", sessionHandler.ForceRevokeUserSessions)
+			rbacRouter.DELETE("/users/:userId/sessions/:sessionId", sessionHandler.ForceRevokeSession)
+			rbacRouter.DELETE("/users/:userId/lock", userHandler.UnlockLogin)
+
+			rbacRouter.POST("/roles", roleHandler.CreateRole)
+			rbacRouter.GET("/roles", roleHandler.ListRoles)
+			rbacRouter.GET("/roles/:roleId", roleHandler.GetRole)
+			rbacRouter.PUT("/roles/:roleId", roleHandler.UpdateRole)
+			rbacRouter.DELETE("/roles/:roleId", roleHandler.DeleteRole)
+			rbacRouter.POST("/roles/:roleId/restore", roleHandler.RestoreRole)
+			rbacRouter.POST("/roles/:roleId/permissions", roleHandler.AttachPermissions)
+			rbacRouter.DELETE("/roles/:roleId/permissions", roleHandler.DetachPermissions)
+			rbacRouter.GET("/roles/:roleId/users", roleHandler.ListRoleUsers)
+			rbacRouter.POST("/roles/:roleId/users", roleHandler.AssignRoleUsers)
+			rbacRouter.GET("/users/:userId/roles", roleHandler.GetUserRoles)
+			rbacRouter.PUT("/users/:userId/roles", roleHandler.SetUserRoles)
+			rbacRouter.POST("/users/:userId/roles", roleHandler.AddUserRoles)
+			rbacRouter.DELETE("/users/:userId/roles", roleHandler.RemoveUserRoles)
+			rbacRouter.GET("/users/:userId/role-grants", roleGrantHandler.ListRoleGrants)
+			rbacRouter.POST("/users/:userId/role-grants", roleGrantHandler.CreateRoleGrant)
+			rbacRouter.DELETE("/users/:userId/role-grants/:grantId", roleGrantHandler.RevokeRoleGrant)
+			rbacRouter.GET("/access-requests", accessRequestHandler.ListAccessRequests)
+			rbacRouter.POST("/service-accounts", apiKeyHandler.CreateServiceAccount)
+			rbacRouter.POST("/service-accounts/:userId/api-keys", apiKeyHandler.CreateServiceAccountKey)
+			rbacRouter.GET("/users/:userId/api-keys", apiKeyHandler.ListUserApiKeys)
+			rbacRouter.DELETE("/users/:userId/api-keys/:keyId", apiKeyHandler.ForceRevokeApiKey)
+			rbacRouter.POST("/rbac/explain", rbacHandler.ExplainPermission)
+			rbacRouter.GET("/rbac/cache", rbacHandler.GetCacheStats)
+			rbacRouter.DELETE("/rbac/cache", rbacHandler.FlushCache)
+
+			rbacRouter.GET("/permissions/tree", permissionHandler.GetPermissionTree)
+			rbacRouter.POST("/permissions", permissionHandler.CreatePermission)
+			rbacRouter.PUT("/permissions/sort", permissionHandler.SortPermissions)
+			rbacRouter.PUT("/permissions/:permissionId", permissionHandler.UpdatePermission)
+			rbacRouter.DELETE("/permissions/:permissionId", permissionHandler.DeletePermission)
+			rbacRouter.POST("/permissions/:permissionId/move", permissionHandler.MovePermission)
+			rbacRouter.GET("/depts/tree", deptHandler.GetDeptTree)
+			rbacRouter.POST("/depts", deptHandler.CreateDept)
+			rbacRouter.GET("/depts/:deptId", deptHandler.GetDept)
+			rbacRouter.PUT("/depts/:deptId", deptHandler.UpdateDept)
+			rbacRouter.DELETE("/depts/:deptId", deptHandler.DeleteDept)
+			rbacRouter.GET("/depts/:deptId/users", deptHandler.ListDeptUsers)
+			rbacRouter.POST("/depts/:deptId/users", deptHandler.AddDeptUsers)
+			rbacRouter.DELETE("/depts/:deptId/users", deptHandler.RemoveDeptUsers)
+
+			rbacRouter.POST("/tenants", tenantHandler.CreateTenant)
+			rbacRouter.GET("/tenants", tenantHandler.ListTenants)
+			rbacRouter.GET("/tenants/:tenantId", tenantHandler.GetTenant)
+			rbacRouter.PUT("/tenants/:tenantId", tenantHandler.UpdateTenant)
+			rbacRouter.DELETE("/tenants/:tenantId", tenantHandler.DeleteTenant)
+			rbacRouter.POST("/tenants/:tenantId/users", tenantHandler.MoveTenantUsers)
+		}
+	}
+
+	// 权限解释需要把请求路径解析为路由模板
+	routes := make([]service.RouteInfo, 0)
+	for _, route := range s.Routes() {
+		routes = append(routes, service.RouteInfo{Method: route.Method, Path: route.Path})
 	}
+	rbacService.SetRoutes(routes)
 
 	return s
 }
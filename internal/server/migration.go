@@ -21,10 +21,37 @@ func NewMigrate(db *gorm.DB, log *log.Logger) *Migrate {
 	}
 }
 func (m *Migrate) Start(ctx context.Context) error {
-	if err := m.db.AutoMigrate(&model.User{}, &model.Role{}, &model.Permission{}); err != nil {
+	// role_permissions使用自定义的关联表以保存effect列
+	if err := m.db.SetupJoinTable(&model.Role{}, "Permissions", &model.RolePermission{}); err != nil {
+		m.log.Error("setup join table error", zap.Error(err))
+		return err
+	}
+	if err := m.db.AutoMigrate(&model.User{}, &model.Role{}, &model.Permission{}, &model.RefreshToken{},
+		&model.RevokedToken{}, &model.UserTokenRevocation{}, &model.Session{}, &model.UserTwoFactor{},
+		&model.RecoveryCode{}, &model.LoginAttempt{}, &model.PasswordHistory{},
+		&model.UserIdentity{}, &model.OIDCState{}, &model.ApiKey{}, &model.Impersonation{},
+		&model.Dept{}, &model.RoleDept{}, &model.Tenant{},
+		&model.RoleGrant{}, &model.AccessRequest{}, &model.AccessRequestEvent{}); err != nil {
 		m.log.Error("user migrate error", zap.Error(err))
 		return err
 	}
+	// 角色标识改为租户内唯一，移除旧的全局唯一约束
+	if m.db.Migrator().HasConstraint(&model.Role{}, "uni_role_role_label") {
+		if err := m.db.Migrator().DropConstraint(&model.Role{}, "uni_role_role_label"); err != nil {
+			m.log.Error("user migrate error", zap.Error(err))
+			return err
+		}
+	}
+	// 会话和模拟登录审计按租户隔离，已有记录补上所属用户的租户
+	for _, sql := range []string{
+		"UPDATE session SET tenant_id = (SELECT tenant_id FROM users WHERE users.user_id = session.user_id) WHERE tenant_id = 0 AND EXISTS (SELECT 1 FROM users WHERE users.user_id = session.user_id)",
+		"UPDATE impersonation SET tenant_id = (SELECT tenant_id FROM users WHERE users.user_id = impersonation.target_user_id) WHERE tenant_id = 0 AND EXISTS (SELECT 1 FROM users WHERE users.user_id = impersonation.target_user_id)",
+	} {
+		if err := m.db.Exec(sql).Error; err != nil {
+			m.log.Error("user migrate error", zap.Error(err))
+			return err
+		}
+	}
 	m.log.Info("AutoMigrate success")
 	os.Exit(0)
 	return nil
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"admin-webrtc-go/docs"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/log"
+	"admin-webrtc-go/pkg/server/http"
+	"context"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// RouteSync 启动时按已注册的路由同步api权限
+type RouteSync struct {
+	log               *log.Logger
+	conf              *viper.Viper
+	httpServer        *http.Server
+	permissionService service.PermissionService
+}
+
+func NewRouteSync(
+	log *log.Logger,
+	conf *viper.Viper,
+	httpServer *http.Server,
+	permissionService service.PermissionService,
+) *RouteSync {
+	return &RouteSync{
+		log:               log,
+		conf:              conf,
+		httpServer:        httpServer,
+		permissionService: permissionService,
+	}
+}
+
+func (r *RouteSync) Start(ctx context.Context) error {
+	if !r.conf.GetBool("security.rbac.sync_routes") {
+		return nil
+	}
+	routes := make([]service.RouteInfo, 0)
+	for _, route := range r.httpServer.Routes() {
+		routes = append(routes, service.RouteInfo{Method: route.Method, Path: route.Path})
+	}
+	// 权限名称取自swag生成的文档，与docs/swagger.json内容相同
+	names, err := service.SwaggerRouteNames(docs.SwaggerInfo.ReadDoc(), routes)
+	if err != nil {
+		r.log.Error("parse swagger doc error", zap.Error(err))
+		return err
+	}
+	result, err := r.permissionService.SyncRoutes(ctx, routes, names)
+	if err != nil {
+		r.log.Error("sync route permissions error", zap.Error(err))
+		return err
+	}
+	for _, permission := range result.Stale {
+		r.log.Warn("api permission matches no route",
+			zap.Uint("id", permission.Id), zap.String("name", permission.PermissionName),
+			zap.String("path", permission.Path), zap.String("method", permission.Method))
+	}
+	r.log.Info("sync route permissions success", zap.Int("routes", len(routes)),
+		zap.Int("created", len(result.Created)), zap.Int("renamed", len(result.Renamed)), zap.Int("stale", len(result.Stale)))
+	return nil
+}
+
+func (r *RouteSync) Stop(ctx context.Context) error {
+	return nil
+}
@@ -3,19 +3,22 @@ package server
 import (
 	"context"
 	"github.com/go-co-op/gocron"
+	"admin-webrtc-go/internal/service"
 	"admin-webrtc-go/pkg/log"
 	"go.uber.org/zap"
 	"time"
 )
 
 type Task struct {
-	log       *log.Logger
-	scheduler *gocron.Scheduler
+	log              *log.Logger
+	scheduler        *gocron.Scheduler
+	roleGrantService service.RoleGrantService
 }
 
-func NewTask(log *log.Logger) *Task {
+func NewTask(log *log.Logger, roleGrantService service.RoleGrantService) *Task {
 	return &Task{
-		log: log,
+		log:              log,
+		roleGrantService: roleGrantService,
 	}
 }
 func (t *Task) Start(ctx context.Context) error {
@@ -28,18 +31,19 @@ func (t *Task) Start(ctx context.Context) error {
 	// if you are in China, you will need to change the time zone as follows
 	// t.scheduler = gocron.NewScheduler(time.FixedZone("PRC", 8*60*60))
 
-	_, err := t.scheduler.CronWithSeconds("0/3 * * * * *").Do(func() {
-		t.log.Info("I'm a Task1.")
+	// 每分钟结束到期的临时授权，同一时间只运行一次
+	_, err := t.scheduler.Every("1m").SingletonMode().Do(func() {
+		count, err := t.roleGrantService.EndExpired(ctx)
+		if err != nil {
+			t.log.Error("EndExpiredRoleGrants error", zap.Error(err))
+			return
+		}
+		if count > 0 {
+			t.log.Info("EndExpiredRoleGrants", zap.Int("count", count))
+		}
 	})
 	if err != nil {
-		t.log.Error("Task1 error", zap.Error(err))
-	}
-
-	_, err = t.scheduler.Every("3s").Do(func() {
-		t.log.Info("I'm a Task2.")
-	})
-	if err != nil {
-		t.log.Error("Task2 error", zap.Error(err))
+		t.log.Error("EndExpiredRoleGrants task error", zap.Error(err))
 	}
 
 	t.scheduler.StartBlocking()
@@ -0,0 +1,357 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/duke-git/lancet/v2/slice"
+	"time"
+)
+
+type AccessRequestService interface {
+	Create(ctx context.Context, userId string, req *v1.CreateAccessRequestRequest) (*v1.AccessRequestData, error)
+	Get(ctx context.Context, userId string, id uint) (*v1.AccessRequestData, error)
+	ListMine(ctx context.Context, userId string) ([]v1.AccessRequestData, error)
+	ListPending(ctx context.Context, approverId string) ([]v1.AccessRequestData, error)
+	List(ctx context.Context, req *v1.ListAccessRequestsRequest) (*v1.ListAccessRequestsResponseData, error)
+	Approve(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error)
+	Reject(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error)
+	Cancel(ctx context.Context, userId string, id uint) (*v1.AccessRequestData, error)
+}
+
+func NewAccessRequestService(
+	service *Service,
+	accessRequestRepo repository.AccessRequestRepository,
+	roleRepo repository.RoleRepository,
+	roleGrantRepo repository.RoleGrantRepository,
+	permissionCacheRepo repository.PermissionCacheRepository,
+) AccessRequestService {
+	return &accessRequestService{
+		accessRequestRepo:   accessRequestRepo,
+		roleRepo:            roleRepo,
+		roleGrantRepo:       roleGrantRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		Service:             service,
+	}
+}
+
+type accessRequestService struct {
+	accessRequestRepo   repository.AccessRequestRepository
+	roleRepo            repository.RoleRepository
+	roleGrantRepo       repository.RoleGrantRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	*Service
+}
+
+// Create 申请角色，角色必须设置了审批人角色，已直接拥有或已有待审批申请的角色不能再申请
+func (s *accessRequestService) Create(ctx context.Context, userId string, req *v1.CreateAccessRequestRequest) (*v1.AccessRequestData, error) {
+	request := &model.AccessRequest{
+		UserId:        userId,
+		RoleId:        req.RoleId,
+		Reason:        req.Reason,
+		DurationHours: req.DurationHours,
+		Status:        model.AccessRequestPending,
+	}
+	var role *model.Role
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if role, err = s.getRole(ctx, req.RoleId); err != nil {
+			return err
+		}
+		if role.ApproverRoleId == 0 {
+			return v1.ErrRoleNotRequestable
+		}
+		has, err := s.accessRequestRepo.HasRole(ctx, userId, req.RoleId)
+		if err != nil {
+			return err
+		}
+		if has {
+			return v1.ErrRoleAlreadyAssigned
+		}
+		pending, err := s.accessRequestRepo.HasPending(ctx, userId, req.RoleId)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return v1.ErrAccessRequestPending
+		}
+		if err = s.accessRequestRepo.Create(ctx, request); err != nil {
+			return err
+		}
+		return s.accessRequestRepo.AddEvent(ctx, &model.AccessRequestEvent{
+			TenantId:  request.TenantId,
+			RequestId: request.Id,
+			ToStatus:  model.AccessRequestPending,
+			ActorId:   userId,
+			Comment:   req.Reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := accessRequestToData(request, role)
+	return &data, nil
+}
+
+// Get 返回申请及其状态变化记录，只有申请人和审批人可以查看
+func (s *accessRequestService) Get(ctx context.Context, userId string, id uint) (*v1.AccessRequestData, error) {
+	request, err := s.accessRequestRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.roleRepo.GetById(ctx, request.RoleId, true)
+	if err != nil && !errors.Is(err, v1.ErrNotFound) {
+		return nil, err
+	}
+	if request.UserId != userId {
+		if role == nil {
+			return nil, v1.ErrNotFound
+		}
+		if err = s.checkApprover(ctx, userId, role); err != nil {
+			if errors.Is(err, v1.ErrAccessRequestForbidden) {
+				return nil, v1.ErrNotFound
+			}
+			return nil, err
+		}
+	}
+	events, err := s.accessRequestRepo.GetEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data := accessRequestToData(request, role)
+	data.Events = make([]v1.AccessRequestEventData, 0, len(events))
+	for _, event := range events {
+		data.Events = append(data.Events, v1.AccessRequestEventData{
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ActorId:    event.ActorId,
+			Comment:    event.Comment,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return &data, nil
+}
+
+func (s *accessRequestService) ListMine(ctx context.Context, userId string) ([]v1.AccessRequestData, error) {
+	requests, err := s.accessRequestRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDataList(ctx, requests)
+}
+
+// ListPending 返回approverId可以审批的待审批申请
+func (s *accessRequestService) ListPending(ctx context.Context, approverId string) ([]v1.AccessRequestData, error) {
+	roleIds, err := s.accessRequestRepo.GetUserRoleIds(ctx, approverId)
+	if err != nil {
+		return nil, err
+	}
+	if len(roleIds) == 0 {
+		return []v1.AccessRequestData{}, nil
+	}
+	requests, err := s.accessRequestRepo.ListPendingByApprover(ctx, roleIds, approverId)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDataList(ctx, requests)
+}
+
+func (s *accessRequestService) List(ctx context.Context, req *v1.ListAccessRequestsRequest) (*v1.ListAccessRequestsResponseData, error) {
+	offset, limit := req.Offset()
+	requests, total, err := s.accessRequestRepo.List(ctx, req.Status, req.UserId, req.RoleId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.toDataList(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ListAccessRequestsResponseData{List: list, Total: total}, nil
+}
+
+// Approve 批准申请并在同一事务中为申请人分配角色；限时申请创建从批准时开始的临时授权
+func (s *accessRequestService) Approve(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error) {
+	var request *model.AccessRequest
+	var role *model.Role
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if request, role, err = s.getDecidable(ctx, approverId, id); err != nil {
+			return err
+		}
+		now := time.Now()
+		if request.DurationHours > 0 {
+			grant := &model.RoleGrant{
+				TenantId:  request.TenantId,
+				UserId:    request.UserId,
+				RoleId:    request.RoleId,
+				StartsAt:  now,
+				EndsAt:    now.Add(time.Duration(request.DurationHours) * time.Hour),
+				Reason:    fmt.Sprintf("access request #%d: %s", request.Id, request.Reason),
+				GrantedBy: approverId,
+			}
+			if err = s.roleGrantRepo.Create(ctx, grant); err != nil {
+				return err
+			}
+			request.GrantId = grant.Id
+		} else if err = s.roleRepo.AddUserRoles(ctx, []string{request.UserId}, []uint{request.RoleId}); err != nil {
+			return err
+		}
+		return s.transition(ctx, request, model.AccessRequestApproved, approverId, comment, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, request.UserId)
+	data := accessRequestToData(request, role)
+	return &data, nil
+}
+
+func (s *accessRequestService) Reject(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error) {
+	var request *model.AccessRequest
+	var role *model.Role
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if request, role, err = s.getDecidable(ctx, approverId, id); err != nil {
+			return err
+		}
+		return s.transition(ctx, request, model.AccessRequestRejected, approverId, comment, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := accessRequestToData(request, role)
+	return &data, nil
+}
+
+// Cancel 申请人撤回待审批的申请
+func (s *accessRequestService) Cancel(ctx context.Context, userId string, id uint) (*v1.AccessRequestData, error) {
+	var request *model.AccessRequest
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if request, err = s.accessRequestRepo.GetById(ctx, id); err != nil {
+			return err
+		}
+		if request.UserId != userId {
+			return v1.ErrNotFound
+		}
+		return s.transition(ctx, request, model.AccessRequestCancelled, userId, "", time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := accessRequestToData(request, nil)
+	return &data, nil
+}
+
+// getDecidable 返回approverId可以审批的待审批申请及申请的角色
+func (s *accessRequestService) getDecidable(ctx context.Context, approverId string, id uint) (*model.AccessRequest, *model.Role, error) {
+	request, err := s.accessRequestRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request.Status != model.AccessRequestPending {
+		return nil, nil, v1.ErrAccessRequestDecided
+	}
+	if request.UserId == approverId {
+		return nil, nil, v1.ErrAccessRequestSelfApproval
+	}
+	role, err := s.getRole(ctx, request.RoleId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = s.checkApprover(ctx, approverId, role); err != nil {
+		return nil, nil, err
+	}
+	return request, role, nil
+}
+
+// checkApprover 拥有角色的审批人角色（包括继承和临时授权）的用户才能审批
+func (s *accessRequestService) checkApprover(ctx context.Context, userId string, role *model.Role) error {
+	if role.ApproverRoleId == 0 {
+		return v1.ErrAccessRequestForbidden
+	}
+	roleIds, err := s.accessRequestRepo.GetUserRoleIds(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !slice.Contain(roleIds, role.ApproverRoleId) {
+		return v1.ErrAccessRequestForbidden
+	}
+	return nil
+}
+
+// transition 改变申请状态并记录，申请已被其他人处理时返回ErrAccessRequestDecided
+func (s *accessRequestService) transition(ctx context.Context, request *model.AccessRequest, status string, actorId string, comment string, now time.Time) error {
+	ok, err := s.accessRequestRepo.Transition(ctx, request, status, actorId, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return v1.ErrAccessRequestDecided
+	}
+	return s.accessRequestRepo.AddEvent(ctx, &model.AccessRequestEvent{
+		TenantId:   request.TenantId,
+		RequestId:  request.Id,
+		FromStatus: model.AccessRequestPending,
+		ToStatus:   status,
+		ActorId:    actorId,
+		Comment:    comment,
+	})
+}
+
+func (s *accessRequestService) getRole(ctx context.Context, id uint) (*model.Role, error) {
+	role, err := s.roleRepo.GetById(ctx, id, false)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *accessRequestService) toDataList(ctx context.Context, requests []model.AccessRequest) ([]v1.AccessRequestData, error) {
+	roleIds := make([]uint, 0, len(requests))
+	for _, request := range requests {
+		roleIds = append(roleIds, request.RoleId)
+	}
+	roles := make(map[uint]*model.Role, len(roleIds))
+	if len(roleIds) > 0 {
+		list, err := s.roleRepo.GetByIds(ctx, slice.Unique(roleIds))
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			roles[list[i].Id] = &list[i]
+		}
+	}
+	data := make([]v1.AccessRequestData, 0, len(requests))
+	for i := range requests {
+		data = append(data, accessRequestToData(&requests[i], roles[requests[i].RoleId]))
+	}
+	return data, nil
+}
+
+// accessRequestToData role为空表示角色已删除或不需要返回角色信息
+func accessRequestToData(request *model.AccessRequest, role *model.Role) v1.AccessRequestData {
+	data := v1.AccessRequestData{
+		Id:            request.Id,
+		UserId:        request.UserId,
+		RoleId:        request.RoleId,
+		Reason:        request.Reason,
+		DurationHours: request.DurationHours,
+		Status:        request.Status,
+		DecidedBy:     request.DecidedBy,
+		DecidedAt:     request.DecidedAt,
+		GrantId:       request.GrantId,
+		CreatedAt:     request.CreatedAt,
+	}
+	if role != nil {
+		data.RoleLabel = role.RoleLabel
+		data.RoleName = role.RoleName
+	}
+	return data
+}
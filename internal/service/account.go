@@ -0,0 +1,220 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVerifyEmailExpire   = 24 * time.Hour
+	defaultResetPasswordExpire = 30 * time.Minute
+)
+
+type AccountService interface {
+	SendVerifyEmail(ctx context.Context, userId string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, req *v1.ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, userId string, req *v1.ChangePasswordRequest) error
+}
+
+func NewAccountService(
+	service *Service,
+	conf *viper.Viper,
+	userRepo repository.UserRepository,
+	tokenService TokenService,
+	passwordService PasswordService,
+	mailer mailer.Mailer,
+) AccountService {
+	verifyEmailExpire := conf.GetDuration("security.account.verify_email_expire")
+	if verifyEmailExpire <= 0 {
+		verifyEmailExpire = defaultVerifyEmailExpire
+	}
+	resetPasswordExpire := conf.GetDuration("security.account.reset_password_expire")
+	if resetPasswordExpire <= 0 {
+		resetPasswordExpire = defaultResetPasswordExpire
+	}
+	return &accountService{
+		userRepo:            userRepo,
+		tokenService:        tokenService,
+		passwordService:     passwordService,
+		mailer:              mailer,
+		linkBaseUrl:         strings.TrimRight(conf.GetString("mail.link_base_url"), "/"),
+		verifyEmailExpire:   verifyEmailExpire,
+		resetPasswordExpire: resetPasswordExpire,
+		Service:             service,
+	}
+}
+
+type accountService struct {
+	userRepo            repository.UserRepository
+	tokenService        TokenService
+	passwordService     PasswordService
+	mailer              mailer.Mailer
+	linkBaseUrl         string // 邮件中链接指向的前端地址
+	verifyEmailExpire   time.Duration
+	resetPasswordExpire time.Duration
+	*Service
+}
+
+// SendVerifyEmail 向用户当前邮箱发送验证链接，邮箱变更后旧链接随之失效
+func (s *accountService) SendVerifyEmail(ctx context.Context, userId string) error {
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return v1.ErrEmailAlreadyVerified
+	}
+
+	token, err := s.jwt.GenPurposeToken(user.UserId, jwt.PurposeVerifyEmail, user.Email, time.Now().Add(s.verifyEmailExpire))
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "验证邮箱",
+		Body: fmt.Sprintf("请在%s内打开以下链接完成邮箱验证：\n\n%s\n\n如果这不是你本人的操作，请忽略本邮件。",
+			s.verifyEmailExpire, s.link("/verify-email", token)),
+	})
+}
+
+func (s *accountService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.parseLinkToken(ctx, token, jwt.PurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, claims.UserId)
+		if err != nil {
+			if errors.Is(err, v1.ErrNotFound) {
+				return v1.ErrLinkInvalid
+			}
+			return err
+		}
+		if user.Email != claims.Subject {
+			return v1.ErrLinkInvalid
+		}
+		if user.EmailVerifiedAt == nil {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+		}
+		// 链接只能使用一次
+		return s.tokenService.Logout(ctx, claims)
+	})
+}
+
+// ForgotPassword 发送重置密码链接，邮箱未注册时同样返回成功，避免泄露账号是否存在
+func (s *accountService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		s.logger.WithContext(ctx).Info("password reset requested for unknown email")
+		return nil
+	}
+
+	token, err := s.jwt.GenPurposeToken(user.UserId, jwt.PurposeResetPassword, passwordFingerprint(user.Password), time.Now().Add(s.resetPasswordExpire))
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "重置密码",
+		Body: fmt.Sprintf("请在%s内打开以下链接重置密码：\n\n%s\n\n如果这不是你本人的操作，请忽略本邮件，你的密码不会被修改。",
+			s.resetPasswordExpire, s.link("/reset-password", token)),
+	})
+}
+
+// ResetPassword 重置密码后注销该用户的全部会话
+func (s *accountService) ResetPassword(ctx context.Context, req *v1.ResetPasswordRequest) error {
+	claims, err := s.parseLinkToken(ctx, req.Token, jwt.PurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, claims.UserId)
+		if err != nil {
+			if errors.Is(err, v1.ErrNotFound) {
+				return v1.ErrLinkInvalid
+			}
+			return err
+		}
+		// 密码已经改过，之前发出的链接全部作废
+		if passwordFingerprint(user.Password) != claims.Subject {
+			return v1.ErrLinkInvalid
+		}
+
+		if err = s.passwordService.SetPassword(ctx, user, req.Password); err != nil {
+			return err
+		}
+		if err = s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if err = s.tokenService.Logout(ctx, claims); err != nil {
+			return err
+		}
+		return s.tokenService.LogoutAll(ctx, user.UserId)
+	})
+}
+
+// ChangePassword 校验旧密码后修改密码，成功后需要重新登录
+func (s *accountService) ChangePassword(ctx context.Context, userId string, req *v1.ChangePasswordRequest) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
+			return v1.ErrPasswordIncorrect
+		}
+
+		if err = s.passwordService.SetPassword(ctx, user, req.NewPassword); err != nil {
+			return err
+		}
+		if err = s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.tokenService.LogoutAll(ctx, user.UserId)
+	})
+}
+
+func (s *accountService) parseLinkToken(ctx context.Context, token string, purpose string) (*jwt.MyCustomClaims, error) {
+	claims, err := s.jwt.ParsePurposeToken(token, purpose)
+	if err != nil {
+		return nil, v1.ErrLinkInvalid
+	}
+	revoked, err := s.tokenService.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, v1.ErrLinkInvalid
+	}
+	return claims, nil
+}
+
+func (s *accountService) link(path string, token string) string {
+	return s.linkBaseUrl + path + "?token=" + url.QueryEscape(token)
+}
+
+// passwordFingerprint 密码哈希的摘要，不在令牌中暴露哈希本身
+func passwordFingerprint(hashedPassword string) string {
+	return jwt.HashToken(hashedPassword)[:16]
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	apiKeyPrefix = "ak_"
+
+	defaultApiKeyExpire = 90 * 24 * time.Hour
+	// 同一密钥在该间隔内只记录一次使用，避免每个请求都写库
+	apiKeyTouchInterval = time.Minute
+)
+
+type ApiKeyService interface {
+	Create(ctx context.Context, userId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error)
+	CreateForServiceAccount(ctx context.Context, creatorId string, serviceAccountId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error)
+	List(ctx context.Context, userId string) ([]v1.ApiKeyData, error)
+	Revoke(ctx context.Context, userId string, keyId string) error
+	Authenticate(ctx context.Context, rawKey string, ip string) (*model.ApiKey, error)
+	CreateServiceAccount(ctx context.Context, creatorId string, req *v1.CreateServiceAccountRequest) (*v1.ServiceAccountData, error)
+}
+
+func NewApiKeyService(
+	service *Service,
+	conf *viper.Viper,
+	apiKeyRepo repository.ApiKeyRepository,
+	userRepo repository.UserRepository,
+) ApiKeyService {
+	defaultExpire := defaultApiKeyExpire
+	if conf.IsSet("security.api_key.default_expire") {
+		defaultExpire = conf.GetDuration("security.api_key.default_expire")
+	}
+	return &apiKeyService{
+		apiKeyRepo:    apiKeyRepo,
+		userRepo:      userRepo,
+		defaultExpire: defaultExpire,
+		maxExpire:     conf.GetDuration("security.api_key.max_expire"),
+		Service:       service,
+	}
+}
+
+type apiKeyService struct {
+	apiKeyRepo    repository.ApiKeyRepository
+	userRepo      repository.UserRepository
+	defaultExpire time.Duration // 0表示默认永不过期
+	maxExpire     time.Duration // 0表示不限制
+	*Service
+}
+
+// Create 为当前用户创建个人密钥
+func (s *apiKeyService) Create(ctx context.Context, userId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error) {
+	return s.create(ctx, userId, userId, req)
+}
+
+// CreateForServiceAccount 管理员为服务账号创建密钥
+func (s *apiKeyService) CreateForServiceAccount(ctx context.Context, creatorId string, serviceAccountId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error) {
+	user, err := s.userRepo.GetByID(ctx, serviceAccountId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.ServiceAccount {
+		return nil, v1.ErrNotFound
+	}
+	return s.create(ctx, creatorId, serviceAccountId, req)
+}
+
+func (s *apiKeyService) create(ctx context.Context, creatorId string, ownerId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error) {
+	scopes, err := s.validateScopes(ctx, ownerId, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	expire := s.defaultExpire
+	if req.ExpiresInDays > 0 {
+		expire = time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	}
+	if s.maxExpire > 0 && (expire <= 0 || expire > s.maxExpire) {
+		return nil, v1.ErrBadRequest
+	}
+	if expire > 0 {
+		t := time.Now().Add(expire)
+		expiresAt = &t
+	}
+
+	keyId, rawKey, err := generateApiKey()
+	if err != nil {
+		return nil, err
+	}
+	key := &model.ApiKey{
+		KeyId:     keyId,
+		Prefix:    apiKeyPrefix + keyId,
+		KeyHash:   jwt.HashToken(rawKey),
+		Name:      req.Name,
+		UserId:    ownerId,
+		CreatedBy: creatorId,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err = s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &v1.CreateApiKeyData{
+		ApiKeyData: toApiKeyData(key),
+		Key:        rawKey,
+	}, nil
+}
+
+// validateScopes 权限必须存在、类型为api，且所属用户当前拥有该权限并且没有被任一角色拒绝
+func (s *apiKeyService) validateScopes(ctx context.Context, ownerId string, ids []uint) ([]model.Permission, error) {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	permissions, err := s.apiKeyRepo.GetApiPermissions(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(unique) {
+		return nil, v1.ErrApiKeyScopeInvalid
+	}
+
+	owned, err := s.userRepo.GetUserWithRolesAndPermission(ctx, ownerId, "api", "")
+	if err != nil {
+		if errors.Is(err, v1.ErrEmptyRecord) {
+			return nil, v1.ErrApiKeyScopeInvalid
+		}
+		return nil, err
+	}
+	ownedSet := make(map[uint]bool, len(*owned))
+	deniedSet := make(map[uint]bool)
+	for _, row := range *owned {
+		if row.Effect == model.EffectDeny {
+			deniedSet[row.PermissionId] = true
+		} else {
+			ownedSet[row.PermissionId] = true
+		}
+	}
+	for _, permission := range permissions {
+		if !ownedSet[permission.Id] || deniedSet[permission.Id] {
+			return nil, v1.ErrApiKeyScopeInvalid
+		}
+	}
+	return permissions, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, userId string) ([]v1.ApiKeyData, error) {
+	keys, err := s.apiKeyRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]v1.ApiKeyData, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, toApiKeyData(key))
+	}
+	return data, nil
+}
+
+// Revoke 吊销当前用户自己的密钥，不属于该用户的密钥视为不存在
+func (s *apiKeyService) Revoke(ctx context.Context, userId string, keyId string) error {
+	key, err := s.apiKeyRepo.GetByKeyId(ctx, keyId)
+	if err != nil {
+		return err
+	}
+	if key.UserId != userId {
+		return v1.ErrNotFound
+	}
+	return s.apiKeyRepo.Revoke(ctx, keyId, time.Now())
+}
+
+// Authenticate 校验密钥及其所属用户，返回带权限范围的密钥
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string, ip string) (*model.ApiKey, error) {
+	key, err := s.apiKeyRepo.GetByHash(ctx, jwt.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrApiKeyInvalid
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, v1.ErrApiKeyInvalid
+	}
+	if _, err = s.userRepo.GetByID(ctx, key.UserId); err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrApiKeyInvalid
+		}
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		// 记录失败不影响本次请求
+		if err = s.apiKeyRepo.Touch(ctx, key.KeyId, now, ip); err != nil {
+			s.logger.WithContext(ctx).Warn("apiKeyRepo.Touch error", zap.String("KeyId", key.KeyId), zap.Error(err))
+		}
+	}
+	return key, nil
+}
+
+// CreateServiceAccount 服务账号没有密码和邮箱，只能通过API密钥调用接口；只能授予创建者自身拥有的角色
+func (s *apiKeyService) CreateServiceAccount(ctx context.Context, creatorId string, req *v1.CreateServiceAccountRequest) (*v1.ServiceAccountData, error) {
+	userId, err := s.sid.GenString()
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{
+		UserId:         userId,
+		Nickname:       req.Nickname,
+		ServiceAccount: true,
+	}
+
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if len(req.Roles) > 0 {
+			roles, err := s.userRepo.GetRolesByLabels(ctx, req.Roles)
+			if err != nil {
+				return err
+			}
+			if len(roles) != len(req.Roles) {
+				return v1.ErrBadRequest
+			}
+			held, err := s.userRepo.GetUserRoleIds(ctx, creatorId)
+			if err != nil {
+				return err
+			}
+			heldSet := make(map[uint]bool, len(held))
+			for _, id := range held {
+				heldSet[id] = true
+			}
+			for _, role := range roles {
+				if !heldSet[role.Id] {
+					return v1.ErrRoleNotHeld
+				}
+			}
+			user.Roles = roles
+		}
+		return s.userRepo.GetUserDefaultSeed(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ServiceAccountData{
+		UserId:   user.UserId,
+		Nickname: user.Nickname,
+	}, nil
+}
+
+// ApiKeyAllows 密钥的权限范围包含该请求
+func ApiKeyAllows(key *model.ApiKey, req ApiRequest) bool {
+	for _, scope := range key.Scopes {
+		if MatchApiPermission(scope.Path, scope.Method, req) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateApiKey 返回公开的密钥标识和完整密钥，完整密钥只在创建时返回一次
+func generateApiKey() (string, string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	keyId := hex.EncodeToString(id)
+	return keyId, apiKeyPrefix + keyId + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func toApiKeyData(key *model.ApiKey) v1.ApiKeyData {
+	scopes := make([]v1.ApiKeyScope, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, v1.ApiKeyScope{
+			PermissionId:   scope.Id,
+			PermissionName: scope.PermissionName,
+			Path:           scope.Path,
+		})
+	}
+	return v1.ApiKeyData{
+		KeyId:      key.KeyId,
+		Prefix:     key.Prefix,
+		Name:       key.Name,
+		UserId:     key.UserId,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIp: key.LastUsedIp,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"errors"
+	"github.com/duke-git/lancet/v2/slice"
+)
+
+type DeptService interface {
+	Tree(ctx context.Context) ([]*v1.DeptData, error)
+	Create(ctx context.Context, req *v1.CreateDeptRequest) (*v1.DeptData, error)
+	Get(ctx context.Context, id uint) (*v1.DeptData, error)
+	Update(ctx context.Context, id uint, req *v1.UpdateDeptRequest) (*v1.DeptData, error)
+	Delete(ctx context.Context, id uint) error
+	AddUsers(ctx context.Context, id uint, userIds []string) error
+	RemoveUsers(ctx context.Context, id uint, userIds []string) error
+	ListUsers(ctx context.Context, id uint, req *v1.ListDeptUsersRequest) (*v1.ListDeptUsersResponseData, error)
+}
+
+func NewDeptService(service *Service, deptRepo repository.DeptRepository, userRepo repository.UserRepository) DeptService {
+	return &deptService{
+		deptRepo: deptRepo,
+		userRepo: userRepo,
+		Service:  service,
+	}
+}
+
+type deptService struct {
+	deptRepo repository.DeptRepository
+	userRepo repository.UserRepository
+	*Service
+}
+
+// Tree 返回全部部门组成的树，同级部门按sort升序排列
+func (s *deptService) Tree(ctx context.Context) ([]*v1.DeptData, error) {
+	depts, err := s.deptRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*v1.DeptData, 0, len(depts))
+	for i := range depts {
+		nodes = append(nodes, deptToData(&depts[i]))
+	}
+	tree := buildTree(nodes, func(node *v1.DeptData) (uint, uint) {
+		return node.Id, node.ParentId
+	}, func(parent *v1.DeptData, child *v1.DeptData) {
+		parent.Children = append(parent.Children, child)
+	})
+	if tree == nil {
+		tree = []*v1.DeptData{}
+	}
+	return tree, nil
+}
+
+func (s *deptService) Create(ctx context.Context, req *v1.CreateDeptRequest) (*v1.DeptData, error) {
+	dept := &model.Dept{
+		ParentId: req.ParentId,
+		DeptName: req.DeptName,
+		LeaderId: req.LeaderId,
+		Sort:     req.Sort,
+		Status:   deptStatusOrDefault(req.Status),
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.checkParent(ctx, 0, req.ParentId); err != nil {
+			return err
+		}
+		if err := s.checkLeader(ctx, req.LeaderId); err != nil {
+			return err
+		}
+		return s.deptRepo.Create(ctx, dept)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deptToData(dept), nil
+}
+
+func (s *deptService) Get(ctx context.Context, id uint) (*v1.DeptData, error) {
+	dept, err := s.deptRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return deptToData(dept), nil
+}
+
+func (s *deptService) Update(ctx context.Context, id uint, req *v1.UpdateDeptRequest) (*v1.DeptData, error) {
+	var dept *model.Dept
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		dept, err = s.deptRepo.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if dept.ParentId != req.ParentId {
+			if err = s.checkParent(ctx, id, req.ParentId); err != nil {
+				return err
+			}
+		}
+		if dept.LeaderId != req.LeaderId {
+			if err = s.checkLeader(ctx, req.LeaderId); err != nil {
+				return err
+			}
+		}
+		dept.ParentId = req.ParentId
+		dept.DeptName = req.DeptName
+		dept.LeaderId = req.LeaderId
+		dept.Sort = req.Sort
+		dept.Status = deptStatusOrDefault(req.Status)
+		return s.deptRepo.Update(ctx, dept)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deptToData(dept), nil
+}
+
+// Delete 只能删除没有下级部门和成员的部门
+func (s *deptService) Delete(ctx context.Context, id uint) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.deptRepo.GetById(ctx, id); err != nil {
+			return err
+		}
+		children, err := s.deptRepo.CountChildren(ctx, id)
+		if err != nil {
+			return err
+		}
+		users, err := s.deptRepo.CountUsers(ctx, id)
+		if err != nil {
+			return err
+		}
+		if children > 0 || users > 0 {
+			return v1.ErrDeptNotEmpty
+		}
+		return s.deptRepo.Delete(ctx, id)
+	})
+}
+
+// AddUsers 批量将用户加入部门，用户原来所属的部门被替换；任一用户不存在时全部不加入
+func (s *deptService) AddUsers(ctx context.Context, id uint, userIds []string) error {
+	userIds = slice.Unique(userIds)
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		dept, err := s.deptRepo.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if dept.Status == model.DeptStatusDisabled {
+			return v1.ErrDeptDisabled
+		}
+		users, err := s.userRepo.GetByIDs(ctx, userIds)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(userIds) {
+			return v1.ErrUserNotFound
+		}
+		return s.deptRepo.SetUsersDept(ctx, userIds, id)
+	})
+}
+
+func (s *deptService) RemoveUsers(ctx context.Context, id uint, userIds []string) error {
+	if _, err := s.deptRepo.GetById(ctx, id); err != nil {
+		return err
+	}
+	return s.deptRepo.RemoveUsers(ctx, id, slice.Unique(userIds))
+}
+
+func (s *deptService) ListUsers(ctx context.Context, id uint, req *v1.ListDeptUsersRequest) (*v1.ListDeptUsersResponseData, error) {
+	if _, err := s.deptRepo.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	deptIds := []uint{id}
+	if req.IncludeChildren {
+		parents, err := s.deptRepo.GetParents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		deptIds = append(deptIds, repository.DescendantDeptIds(parents, deptIds)...)
+	}
+	offset, limit := req.Offset()
+	users, total, err := s.deptRepo.ListUsers(ctx, deptIds, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	data := &v1.ListDeptUsersResponseData{List: make([]v1.DeptUserData, 0, len(users)), Total: total}
+	for _, user := range users {
+		data.List = append(data.List, v1.DeptUserData{
+			UserId:         user.UserId,
+			Nickname:       user.Nickname,
+			Email:          user.Email,
+			ServiceAccount: user.ServiceAccount,
+			DeptId:         user.DeptId,
+		})
+	}
+	return data, nil
+}
+
+// checkParent 上级部门必须存在，且不能是部门自身或其下级部门
+func (s *deptService) checkParent(ctx context.Context, id uint, parentId uint) error {
+	if parentId == 0 {
+		return nil
+	}
+	parents, err := s.deptRepo.GetParents(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := parents[parentId]; !ok {
+		return v1.ErrDeptNotFound
+	}
+	if id == 0 {
+		return nil
+	}
+	if parentId == id || slice.Contain(repository.DescendantDeptIds(parents, []uint{id}), parentId) {
+		return v1.ErrDeptCycle
+	}
+	return nil
+}
+
+func (s *deptService) checkLeader(ctx context.Context, leaderId string) error {
+	if leaderId == "" {
+		return nil
+	}
+	if _, err := s.userRepo.GetByID(ctx, leaderId); err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return v1.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func deptStatusOrDefault(status string) string {
+	if status == "" {
+		return model.DeptStatusEnabled
+	}
+	return status
+}
+
+func deptToData(dept *model.Dept) *v1.DeptData {
+	return &v1.DeptData{
+		Id:        dept.Id,
+		ParentId:  dept.ParentId,
+		DeptName:  dept.DeptName,
+		LeaderId:  dept.LeaderId,
+		Sort:      dept.Sort,
+		Status:    dept.Status,
+		CreatedAt: dept.CreatedAt,
+		UpdatedAt: dept.UpdatedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"context"
+	"errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	// ImpersonateRoute 模拟登录接口的路由模板，需要单独授予匹配该路由的api权限
+	ImpersonateRoute = "/v1/users/:userId/impersonate"
+
+	defaultImpersonationExpire = 30 * time.Minute
+)
+
+type ImpersonationService interface {
+	Start(ctx context.Context, actor *jwt.MyCustomClaims, targetUserId string, req *v1.ImpersonateRequest, client ClientInfo) (*v1.ImpersonationTokenData, error)
+	Stop(ctx context.Context, claims *jwt.MyCustomClaims) error
+	List(ctx context.Context, req *v1.ListImpersonationsRequest) ([]v1.ImpersonationData, error)
+}
+
+func NewImpersonationService(
+	service *Service,
+	conf *viper.Viper,
+	impersonationRepo repository.ImpersonationRepository,
+	userRepo repository.UserRepository,
+	userService UserService,
+	tokenService TokenService,
+) ImpersonationService {
+	expire := conf.GetDuration("security.impersonation.expire")
+	if expire <= 0 {
+		expire = defaultImpersonationExpire
+	}
+	return &impersonationService{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		userService:       userService,
+		tokenService:      tokenService,
+		expire:            expire,
+		Service:           service,
+	}
+}
+
+type impersonationService struct {
+	impersonationRepo repository.ImpersonationRepository
+	userRepo          repository.UserRepository
+	userService       UserService
+	tokenService      TokenService
+	expire            time.Duration
+	*Service
+}
+
+// Start 签发以目标用户身份访问的短期令牌并记录审计，令牌中同时保留发起人
+func (s *impersonationService) Start(ctx context.Context, actor *jwt.MyCustomClaims, targetUserId string, req *v1.ImpersonateRequest, client ClientInfo) (*v1.ImpersonationTokenData, error) {
+	// 不允许嵌套模拟或通过API密钥发起
+	if actor.ActorId != "" || actor.ApiKeyId != "" {
+		return nil, v1.ErrImpersonationNotAllowed
+	}
+	if targetUserId == actor.UserId {
+		return nil, v1.ErrImpersonationTarget
+	}
+	target, err := s.userRepo.GetByID(ctx, targetUserId)
+	if err != nil {
+		return nil, err
+	}
+	// 同样拥有模拟权限的用户不能被模拟，避免借此获得更高的权限
+	privileged, err := s.userService.CheckAPIAuthPermission(ctx, targetUserId, ApiRequest{
+		Method: http.MethodPost,
+		Route:  ImpersonateRoute,
+	})
+	if err != nil && !errors.Is(err, v1.ErrEmptyRecord) {
+		return nil, err
+	}
+	if privileged {
+		return nil, v1.ErrImpersonationTarget
+	}
+
+	expiresAt := time.Now().Add(s.expire)
+	token, jti, err := s.jwt.GenImpersonationToken(actor.UserId, targetUserId, target.TenantId, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.impersonationRepo.Create(ctx, &model.Impersonation{
+		ActorId:      actor.UserId,
+		TargetUserId: targetUserId,
+		TenantId:     target.TenantId,
+		Reason:       req.Reason,
+		Jti:          jti,
+		Ip:           client.Ip,
+		UserAgent:    client.UserAgent,
+		ExpiresAt:    expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Warn("impersonation started", zap.String("ActorId", actor.UserId),
+		zap.String("UserId", targetUserId), zap.String("Reason", req.Reason))
+
+	return &v1.ImpersonationTokenData{
+		AccessToken: token,
+		ExpiresIn:   int64(s.expire.Seconds()),
+		ExpiresAt:   expiresAt,
+		UserId:      targetUserId,
+	}, nil
+}
+
+// Stop 吊销当前模拟令牌并记录结束时间，管理员自己的令牌不受影响
+func (s *impersonationService) Stop(ctx context.Context, claims *jwt.MyCustomClaims) error {
+	if claims.ActorId == "" {
+		return v1.ErrNotImpersonating
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.tokenService.Logout(ctx, claims); err != nil {
+			return err
+		}
+		return s.impersonationRepo.End(ctx, claims.ID, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+	s.logger.WithContext(ctx).Warn("impersonation stopped", zap.String("ActorId", claims.ActorId), zap.String("UserId", claims.UserId))
+	return nil
+}
+
+func (s *impersonationService) List(ctx context.Context, req *v1.ListImpersonationsRequest) ([]v1.ImpersonationData, error) {
+	list, err := s.impersonationRepo.List(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]v1.ImpersonationData, 0, len(list))
+	for _, item := range list {
+		data = append(data, v1.ImpersonationData{
+			ActorId:   item.ActorId,
+			UserId:    item.TargetUserId,
+			Reason:    item.Reason,
+			Ip:        item.Ip,
+			UserAgent: item.UserAgent,
+			StartedAt: item.CreatedAt,
+			ExpiresAt: item.ExpiresAt,
+			EndedAt:   item.EndedAt,
+		})
+	}
+	return data, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	accountAttemptPrefix = "account:"
+	ipAttemptPrefix      = "ip:"
+)
+
+// RetryAfterError 登录被限制时返回，RetryAfter为客户端需要等待的时间
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%s retry after %s", e.Err.Error(), e.RetryAfter)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+type LoginAttemptService interface {
+	Check(ctx context.Context, userId string, ip string) error
+	Fail(ctx context.Context, userId string, ip string) error
+	Succeed(ctx context.Context, userId string) error
+	Unlock(ctx context.Context, userId string) error
+}
+
+type loginPolicy struct {
+	maxFailures  int           // 连续失败达到该次数后锁定，0表示不锁定
+	lockDuration time.Duration // 锁定时长
+	backoffBase  time.Duration // 第n次失败后需等待backoffBase*2^(n-1)
+	backoffMax   time.Duration
+	window       time.Duration // 距上次失败超过window后重新计数
+}
+
+func NewLoginAttemptService(service *Service, conf *viper.Viper, loginAttemptRepo repository.LoginAttemptRepository) LoginAttemptService {
+	window := conf.GetDuration("security.login.window")
+	if window <= 0 {
+		window = 15 * time.Minute
+	}
+	return &loginAttemptService{
+		loginAttemptRepo: loginAttemptRepo,
+		account: loginPolicy{
+			maxFailures:  conf.GetInt("security.login.account.max_failures"),
+			lockDuration: conf.GetDuration("security.login.account.lock_duration"),
+			backoffBase:  conf.GetDuration("security.login.account.backoff_base"),
+			backoffMax:   conf.GetDuration("security.login.account.backoff_max"),
+			window:       window,
+		},
+		ip: loginPolicy{
+			maxFailures:  conf.GetInt("security.login.ip.max_failures"),
+			lockDuration: conf.GetDuration("security.login.ip.lock_duration"),
+			backoffBase:  conf.GetDuration("security.login.ip.backoff_base"),
+			backoffMax:   conf.GetDuration("security.login.ip.backoff_max"),
+			window:       window,
+		},
+		Service: service,
+	}
+}
+
+type loginAttemptService struct {
+	loginAttemptRepo repository.LoginAttemptRepository
+	account          loginPolicy
+	ip               loginPolicy
+	*Service
+}
+
+// Check 账号或IP处于锁定或退避期间时拒绝登录，userId为空时只检查IP
+func (s *loginAttemptService) Check(ctx context.Context, userId string, ip string) error {
+	if ip != "" {
+		if err := s.check(ctx, ipAttemptPrefix+ip, s.ip, v1.ErrTooManyLoginAttempts); err != nil {
+			return err
+		}
+	}
+	if userId != "" {
+		if err := s.check(ctx, accountAttemptPrefix+userId, s.account, v1.ErrAccountLocked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fail 记录一次失败，userId为空(账号不存在)时只计入IP
+func (s *loginAttemptService) Fail(ctx context.Context, userId string, ip string) error {
+	if ip != "" {
+		if err := s.fail(ctx, ipAttemptPrefix+ip, s.ip); err != nil {
+			return err
+		}
+	}
+	if userId != "" {
+		if err := s.fail(ctx, accountAttemptPrefix+userId, s.account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed 登录成功后清空账号的失败计数，IP计数等待自然过期
+func (s *loginAttemptService) Succeed(ctx context.Context, userId string) error {
+	return s.loginAttemptRepo.Delete(ctx, accountAttemptPrefix+userId)
+}
+
+// Unlock 管理员解除账号锁定
+func (s *loginAttemptService) Unlock(ctx context.Context, userId string) error {
+	return s.loginAttemptRepo.Delete(ctx, accountAttemptPrefix+userId)
+}
+
+func (s *loginAttemptService) check(ctx context.Context, subject string, policy loginPolicy, lockedErr error) error {
+	attempt, err := s.loginAttemptRepo.Get(ctx, subject)
+	if err != nil || attempt == nil {
+		return err
+	}
+	now := time.Now()
+	if attempt.LockedUntil != nil {
+		if now.Before(*attempt.LockedUntil) {
+			return &RetryAfterError{Err: lockedErr, RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+		// 锁定到期后重新计数
+		return s.loginAttemptRepo.Delete(ctx, subject)
+	}
+	if now.Sub(attempt.LastFailedAt) > policy.window {
+		return s.loginAttemptRepo.Delete(ctx, subject)
+	}
+	if wait := policy.backoff(attempt.Failures); wait > 0 {
+		if retryAt := attempt.LastFailedAt.Add(wait); now.Before(retryAt) {
+			return &RetryAfterError{Err: v1.ErrTooManyLoginAttempts, RetryAfter: retryAt.Sub(now)}
+		}
+	}
+	return nil
+}
+
+func (s *loginAttemptService) fail(ctx context.Context, subject string, policy loginPolicy) error {
+	now := time.Now()
+	failures, err := s.loginAttemptRepo.IncrFailures(ctx, subject, now)
+	if err != nil {
+		return err
+	}
+	if policy.maxFailures > 0 && failures >= policy.maxFailures && policy.lockDuration > 0 {
+		s.logger.WithContext(ctx).Warn("login locked", zap.String("subject", subject), zap.Int("failures", failures))
+		return s.loginAttemptRepo.Lock(ctx, subject, now.Add(policy.lockDuration))
+	}
+	return nil
+}
+
+// backoff 第failures次失败后的等待时间
+func (p loginPolicy) backoff(failures int) time.Duration {
+	if p.backoffBase <= 0 || failures <= 0 {
+		return 0
+	}
+	wait := p.backoffBase
+	for i := 1; i < failures && i < 32; i++ {
+		wait *= 2
+		if p.backoffMax > 0 && wait >= p.backoffMax {
+			return p.backoffMax
+		}
+	}
+	return wait
+}
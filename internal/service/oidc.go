@@ -0,0 +1,245 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/oidc"
+	"context"
+	"errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"time"
+)
+
+const defaultOIDCStateExpire = 10 * time.Minute
+
+// RoleMapping 身份提供方用户组到角色标识的映射
+type RoleMapping struct {
+	Group string `mapstructure:"group"`
+	Role  string `mapstructure:"role"`
+}
+
+type OIDCService interface {
+	AuthorizationURL(ctx context.Context) (*v1.OIDCAuthorizationData, error)
+	Callback(ctx context.Context, req *v1.OIDCCallbackRequest, client ClientInfo) (*v1.LoginResponseData, error)
+}
+
+func NewOIDCService(
+	service *Service,
+	conf *viper.Viper,
+	provider *oidc.Provider,
+	oidcRepo repository.OIDCRepository,
+	userRepo repository.UserRepository,
+	permissionCacheRepo repository.PermissionCacheRepository,
+	tokenService TokenService,
+	twoFactorService TwoFactorService,
+	loginAttemptService LoginAttemptService,
+) OIDCService {
+	var roleMappings []RoleMapping
+	if err := conf.UnmarshalKey("security.oidc.role_mapping", &roleMappings); err != nil {
+		panic(err)
+	}
+	stateExpire := conf.GetDuration("security.oidc.state_expire")
+	if stateExpire <= 0 {
+		stateExpire = defaultOIDCStateExpire
+	}
+	autoCreate := true
+	if conf.IsSet("security.oidc.auto_create") {
+		autoCreate = conf.GetBool("security.oidc.auto_create")
+	}
+	return &oidcService{
+		provider:            provider,
+		oidcRepo:            oidcRepo,
+		userRepo:            userRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		tokenService:        tokenService,
+		twoFactorService:    twoFactorService,
+		loginAttemptService: loginAttemptService,
+		roleMappings:        roleMappings,
+		stateExpire:         stateExpire,
+		autoCreate:          autoCreate,
+		linkByEmail:         conf.GetBool("security.oidc.link_by_email"),
+		Service:             service,
+	}
+}
+
+type oidcService struct {
+	provider            *oidc.Provider
+	oidcRepo            repository.OIDCRepository
+	userRepo            repository.UserRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	tokenService        TokenService
+	twoFactorService    TwoFactorService
+	loginAttemptService LoginAttemptService
+	roleMappings        []RoleMapping
+	stateExpire         time.Duration
+	autoCreate          bool // 外部身份没有关联本地用户时自动创建
+	linkByEmail         bool // 邮箱已被身份提供方验证时关联同邮箱的本地用户
+	*Service
+}
+
+// AuthorizationURL 生成state、nonce和PKCE校验码，返回身份提供方的授权地址
+func (s *oidcService) AuthorizationURL(ctx context.Context) (*v1.OIDCAuthorizationData, error) {
+	if !s.provider.Enabled() {
+		return nil, v1.ErrOIDCDisabled
+	}
+	state := &model.OIDCState{ExpiresAt: time.Now().Add(s.stateExpire)}
+	for _, v := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		random, err := oidc.GenerateVerifier()
+		if err != nil {
+			return nil, err
+		}
+		*v = random
+	}
+
+	authorizationUrl, err := s.provider.AuthCodeURL(ctx, state.State, state.Nonce, oidc.S256Challenge(state.CodeVerifier))
+	if err != nil {
+		return nil, err
+	}
+	if err = s.oidcRepo.CreateState(ctx, state); err != nil {
+		return nil, err
+	}
+	return &v1.OIDCAuthorizationData{
+		AuthorizationUrl: authorizationUrl,
+		State:            state.State,
+	}, nil
+}
+
+// Callback 用授权码换取并校验ID令牌，关联或创建本地用户后签发令牌
+func (s *oidcService) Callback(ctx context.Context, req *v1.OIDCCallbackRequest, client ClientInfo) (*v1.LoginResponseData, error) {
+	if !s.provider.Enabled() {
+		return nil, v1.ErrOIDCDisabled
+	}
+	if err := s.loginAttemptService.Check(ctx, "", client.Ip); err != nil {
+		return nil, err
+	}
+	state, err := s.oidcRepo.TakeState(ctx, req.State)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrOIDCStateInvalid
+		}
+		return nil, err
+	}
+	if time.Now().After(state.ExpiresAt) {
+		return nil, v1.ErrOIDCStateInvalid
+	}
+
+	rawIDToken, err := s.provider.Exchange(ctx, req.Code, state.CodeVerifier)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("oidc code exchange failed", zap.Error(err))
+		return nil, v1.ErrOIDCLoginFailed
+	}
+	idToken, err := s.provider.Verify(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("oidc id token verification failed", zap.Error(err))
+		return nil, v1.ErrOIDCLoginFailed
+	}
+
+	var userId string
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.resolveUser(ctx, idToken)
+		if err != nil {
+			return err
+		}
+		userId = user.UserId
+		// 被锁定的账号同样不能通过单点登录绕过
+		if err = s.loginAttemptService.Check(ctx, userId, ""); err != nil {
+			return err
+		}
+		managed, granted := s.mapRoles(idToken.Groups)
+		return s.oidcRepo.SyncRoles(ctx, userId, managed, granted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 用户组映射的角色可能发生变化
+	s.permissionCacheRepo.Invalidate(ctx, userId)
+
+	// 与密码登录相同，需要两步验证时先返回质询令牌
+	if data, err := s.twoFactorChallenge(ctx, s.twoFactorService, userId); err != nil || data != nil {
+		return data, err
+	}
+	if err = s.loginAttemptService.Succeed(ctx, userId); err != nil {
+		return nil, err
+	}
+	return s.tokenService.IssueTokens(ctx, userId, client)
+}
+
+// resolveUser 依次按外部身份、已验证邮箱查找本地用户，都没有时按配置创建
+func (s *oidcService) resolveUser(ctx context.Context, idToken *oidc.IDToken) (*model.User, error) {
+	identity, err := s.oidcRepo.GetIdentity(ctx, s.provider.Name(), idToken.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(ctx, identity.UserId)
+	}
+	if !errors.Is(err, v1.ErrNotFound) {
+		return nil, err
+	}
+
+	var user *model.User
+	if idToken.Email != "" {
+		user, err = s.userRepo.GetByEmail(ctx, idToken.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case user != nil:
+		// 未经验证的邮箱可以被任意填写，不能据此关联已有账号
+		if !s.linkByEmail || !idToken.EmailVerified {
+			return nil, v1.ErrEmailAlreadyUse
+		}
+	case !s.autoCreate:
+		return nil, v1.ErrOIDCAccountNotFound
+	default:
+		if user, err = s.createUser(ctx, idToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.oidcRepo.CreateIdentity(ctx, &model.UserIdentity{
+		Provider: s.provider.Name(),
+		Subject:  idToken.Subject,
+		UserId:   user.UserId,
+		Email:    idToken.Email,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser 单点登录创建的用户没有本地密码，只能通过身份提供方或重置密码登录
+func (s *oidcService) createUser(ctx context.Context, idToken *oidc.IDToken) (*model.User, error) {
+	userId, err := s.sid.GenString()
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{
+		UserId:   userId,
+		Nickname: idToken.Name,
+		Email:    idToken.Email,
+	}
+	if idToken.EmailVerified && idToken.Email != "" {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err = s.userRepo.GetUserDefaultSeed(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// mapRoles 返回映射涉及的全部角色和用户组对应的角色
+func (s *oidcService) mapRoles(groups []string) (managed []string, granted []string) {
+	groupSet := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		groupSet[group] = true
+	}
+	for _, mapping := range s.roleMappings {
+		managed = append(managed, mapping.Role)
+		if groupSet[mapping.Group] {
+			granted = append(granted, mapping.Role)
+		}
+	}
+	return managed, granted
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/password"
+	"context"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordPolicyError 密码不符合策略时返回，Violations为未通过的规则
+type PasswordPolicyError struct {
+	Violations []password.Violation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return v1.ErrPasswordPolicy.Error()
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return v1.ErrPasswordPolicy
+}
+
+type PasswordService interface {
+	Validate(ctx context.Context, user *model.User, plain string) error
+	SetPassword(ctx context.Context, user *model.User, plain string) error
+}
+
+func NewPasswordService(service *Service, conf *viper.Viper, passwordHistoryRepo repository.PasswordHistoryRepository) PasswordService {
+	return &passwordService{
+		passwordHistoryRepo: passwordHistoryRepo,
+		policy:              password.NewPolicy(conf),
+		Service:             service,
+	}
+}
+
+type passwordService struct {
+	passwordHistoryRepo repository.PasswordHistoryRepository
+	policy              *password.Policy
+	*Service
+}
+
+// Validate 按策略校验新密码，并检查是否与当前密码或最近使用过的密码相同
+func (s *passwordService) Validate(ctx context.Context, user *model.User, plain string) error {
+	violations := s.policy.Validate(plain)
+	reused, err := s.reused(ctx, user, plain)
+	if err != nil {
+		return err
+	}
+	if reused {
+		violations = append(violations, s.policy.HistoryViolation())
+	}
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// SetPassword 校验通过后把哈希写入user.Password并记录历史，由调用方保存user
+func (s *passwordService) SetPassword(ctx context.Context, user *model.User, plain string) error {
+	if err := s.Validate(ctx, user, plain); err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+
+	if s.policy.History <= 0 {
+		return nil
+	}
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.passwordHistoryRepo.Create(ctx, &model.PasswordHistory{
+			UserId:       user.UserId,
+			PasswordHash: user.Password,
+		}); err != nil {
+			return err
+		}
+		return s.passwordHistoryRepo.Prune(ctx, user.UserId, s.policy.History)
+	})
+}
+
+// reused 启用历史记录之前设置的密码不在历史表中，因此同时比较当前密码
+func (s *passwordService) reused(ctx context.Context, user *model.User, plain string) (bool, error) {
+	if s.policy.History <= 0 || user == nil || user.Password == "" {
+		return false, nil
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(plain)) == nil {
+		return true, nil
+	}
+	histories, err := s.passwordHistoryRepo.ListRecent(ctx, user.UserId, s.policy.History)
+	if err != nil {
+		return false, err
+	}
+	for _, history := range histories {
+		if bcrypt.CompareHashAndPassword([]byte(history.PasswordHash), []byte(plain)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,454 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PermissionTypeMenu   = "menu"
+	PermissionTypeButton = "button"
+	PermissionTypeApi    = "api"
+
+	permissionTimeLayout = "2006-01-02 15:04:05"
+)
+
+type PermissionService interface {
+	Tree(ctx context.Context) ([]*v1.GetMenuTreeResponseData, error)
+	Create(ctx context.Context, req *v1.CreatePermissionRequest) (*v1.GetMenuTreeResponseData, error)
+	Update(ctx context.Context, id uint, req *v1.UpdatePermissionRequest) (*v1.GetMenuTreeResponseData, error)
+	Delete(ctx context.Context, id uint) error
+	Move(ctx context.Context, id uint, req *v1.MovePermissionRequest) error
+	Sort(ctx context.Context, req *v1.SortPermissionsRequest) error
+	SyncRoutes(ctx context.Context, routes []RouteInfo, names map[RouteInfo]string) (*RouteSyncResult, error)
+}
+
+// RouteSyncResult 按路由同步api权限的结果
+type RouteSyncResult struct {
+	Created []model.Permission // 新建的权限
+	Renamed []model.Permission // 名称与swagger注释不一致而更新的权限
+	Stale   []model.Permission // 不匹配任何已注册路由的权限，需要人工确认后删除
+}
+
+func NewPermissionService(service *Service, permissionRepo repository.PermissionRepository, permissionCacheRepo repository.PermissionCacheRepository) PermissionService {
+	return &permissionService{
+		permissionRepo:      permissionRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		Service:             service,
+	}
+}
+
+type permissionService struct {
+	permissionRepo      repository.PermissionRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	*Service
+}
+
+// Tree 返回全部类型的权限节点，同级节点按sort升序排列
+func (s *permissionService) Tree(ctx context.Context) ([]*v1.GetMenuTreeResponseData, error) {
+	permissions, err := s.permissionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*v1.GetMenuTreeResponseData, 0, len(permissions))
+	for i := range permissions {
+		nodes = append(nodes, permissionToNode(&permissions[i]))
+	}
+	tree := buildMenuTree(nodes)
+	recursiveSort(tree, "asc")
+	if tree == nil {
+		tree = []*v1.GetMenuTreeResponseData{}
+	}
+	return tree, nil
+}
+
+func (s *permissionService) Create(ctx context.Context, req *v1.CreatePermissionRequest) (*v1.GetMenuTreeResponseData, error) {
+	if err := validatePermission(req.PermissionType, req.Path, req.Method); err != nil {
+		return nil, err
+	}
+	now := time.Now().Format(permissionTimeLayout)
+	permission := &model.Permission{
+		PermissionName: req.PermissionName,
+		PermissionType: req.PermissionType,
+		ParentId:       req.ParentId,
+		Icon:           req.Icon,
+		Route:          req.Route,
+		RouteFile:      req.RouteFile,
+		Path:           req.Path,
+		Method:         req.Method,
+		Sort:           req.Sort,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		level, err := s.childLevel(ctx, req.ParentId)
+		if err != nil {
+			return err
+		}
+		permission.Level = level
+		if permission.Sort == "" {
+			count, err := s.permissionRepo.CountChildren(ctx, req.ParentId)
+			if err != nil {
+				return err
+			}
+			permission.Sort = formatSort(int(count) + 1)
+		}
+		return s.permissionRepo.Create(ctx, permission)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return permissionToNode(permission), nil
+}
+
+func (s *permissionService) Update(ctx context.Context, id uint, req *v1.UpdatePermissionRequest) (*v1.GetMenuTreeResponseData, error) {
+	if err := validatePermission(req.PermissionType, req.Path, req.Method); err != nil {
+		return nil, err
+	}
+	var permission *model.Permission
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		permission, err = s.permissionRepo.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		// 只有菜单可以有子节点
+		if permission.PermissionType == PermissionTypeMenu && req.PermissionType != PermissionTypeMenu {
+			count, err := s.permissionRepo.CountChildren(ctx, id)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return v1.ErrPermissionChildren
+			}
+		}
+		permission.PermissionName = req.PermissionName
+		permission.PermissionType = req.PermissionType
+		permission.Icon = req.Icon
+		permission.Route = req.Route
+		permission.RouteFile = req.RouteFile
+		permission.Path = req.Path
+		permission.Method = req.Method
+		if req.Sort != "" {
+			permission.Sort = req.Sort
+		}
+		permission.UpdatedAt = time.Now().Format(permissionTimeLayout)
+		return s.permissionRepo.Update(ctx, permission)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.permissionCacheRepo.InvalidateAll(ctx)
+	return permissionToNode(permission), nil
+}
+
+// Delete 有子节点时不能删除，需要先删除或移走子节点
+func (s *permissionService) Delete(ctx context.Context, id uint) error {
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.permissionRepo.GetById(ctx, id); err != nil {
+			return err
+		}
+		count, err := s.permissionRepo.CountChildren(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrPermissionChildren
+		}
+		return s.permissionRepo.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.InvalidateAll(ctx)
+	return nil
+}
+
+// Move 将节点及其子孙节点移动到新的父节点下，新的父节点不能是该节点自身或其子孙节点
+func (s *permissionService) Move(ctx context.Context, id uint, req *v1.MovePermissionRequest) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		permissions, err := s.permissionRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+		byId := make(map[uint]*model.Permission, len(permissions))
+		children := make(map[uint][]uint, len(permissions))
+		for i := range permissions {
+			byId[permissions[i].Id] = &permissions[i]
+			children[permissions[i].ParentId] = append(children[permissions[i].ParentId], permissions[i].Id)
+		}
+		permission, ok := byId[id]
+		if !ok {
+			return v1.ErrNotFound
+		}
+
+		level := 1
+		if req.ParentId != 0 {
+			parent, ok := byId[req.ParentId]
+			if !ok || parent.PermissionType != PermissionTypeMenu {
+				return v1.ErrPermissionParent
+			}
+			// 沿新的父节点向上查找，遇到自身说明会形成环；步数上限防止已有数据中的环导致死循环
+			for p, steps := req.ParentId, 0; p != 0 && steps <= len(permissions); p, steps = parentOf(byId, p), steps+1 {
+				if p == id {
+					return v1.ErrPermissionCycle
+				}
+			}
+			level = parent.Level + 1
+		}
+
+		sort := req.Sort
+		if sort == "" {
+			count := len(children[req.ParentId])
+			if permission.ParentId != req.ParentId {
+				count++
+			}
+			sort = formatSort(count)
+		}
+		if err = s.permissionRepo.Move(ctx, id, req.ParentId, sort); err != nil {
+			return err
+		}
+		return s.permissionRepo.ShiftLevel(ctx, subtreeIds(children, id), level-permission.Level)
+	})
+}
+
+// Sort 按ids的顺序重新设置同级节点的sort，ids必须正好是parentId下的全部子节点
+func (s *permissionService) Sort(ctx context.Context, req *v1.SortPermissionsRequest) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		permissions, err := s.permissionRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+		siblings := make(map[uint]bool)
+		for _, permission := range permissions {
+			if permission.ParentId == req.ParentId {
+				siblings[permission.Id] = true
+			}
+		}
+		if len(req.Ids) != len(siblings) {
+			return v1.ErrBadRequest
+		}
+		for _, id := range req.Ids {
+			if !siblings[id] {
+				return v1.ErrBadRequest
+			}
+			// 重复的id
+			siblings[id] = false
+		}
+		for i, id := range req.Ids {
+			if err = s.permissionRepo.UpdateSort(ctx, id, formatSort(i+1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// childLevel 校验父节点并返回子节点的层级，根节点的层级为1
+func (s *permissionService) childLevel(ctx context.Context, parentId uint) (int, error) {
+	if parentId == 0 {
+		return 1, nil
+	}
+	parent, err := s.permissionRepo.GetById(ctx, parentId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return 0, v1.ErrPermissionParent
+		}
+		return 0, err
+	}
+	if parent.PermissionType != PermissionTypeMenu {
+		return 0, v1.ErrPermissionParent
+	}
+	return parent.Level + 1, nil
+}
+
+// validatePermission api类型必须有path，method只能是HTTP方法或all
+func validatePermission(permissionType string, path string, method string) error {
+	if permissionType == PermissionTypeApi && strings.TrimSpace(path) == "" {
+		return v1.ErrPermissionInvalid
+	}
+	if method == "" {
+		return nil
+	}
+	for _, m := range strings.Split(method, ",") {
+		switch strings.ToUpper(strings.TrimSpace(m)) {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+			http.MethodHead, http.MethodOptions, strings.ToUpper(MethodAll), "*":
+		default:
+			return v1.ErrPermissionInvalid
+		}
+	}
+	return nil
+}
+
+func parentOf(byId map[uint]*model.Permission, id uint) uint {
+	if permission, ok := byId[id]; ok {
+		return permission.ParentId
+	}
+	return 0
+}
+
+func subtreeIds(children map[uint][]uint, id uint) []uint {
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// formatSort sort按字符串比较，补零保证顺序正确
+func formatSort(n int) string {
+	return fmt.Sprintf("%04d", n)
+}
+
+func permissionToNode(permission *model.Permission) *v1.GetMenuTreeResponseData {
+	return &v1.GetMenuTreeResponseData{
+		Key:            strconv.FormatUint(uint64(permission.Id), 10),
+		Label:          permission.PermissionName,
+		Sort:           permission.Sort,
+		PermissionType: permission.PermissionType,
+		ParentId:       strconv.FormatUint(uint64(permission.ParentId), 10),
+		Level:          uint(permission.Level),
+		Icon:           permission.Icon,
+		Route:          permission.Route,
+		RouteFile:      permission.RouteFile,
+		Path:           permission.Path,
+		Method:         permission.Method,
+		CreatedAt:      permission.CreatedAt,
+		UpdatedAt:      permission.UpdatedAt,
+		Children:       []*v1.GetMenuTreeResponseData{},
+	}
+}
+
+// SyncRoutes 为每个路由和方法补充path为路由模板的api权限，名称取自names，没有注释时为“方法 路由”；
+// 已有同一路由和方法的权限时只更新名称。不会删除权限，只返回不匹配任何路由的权限，旧版按api参数匹配的权限不检查
+func (s *permissionService) SyncRoutes(ctx context.Context, routes []RouteInfo, names map[RouteInfo]string) (*RouteSyncResult, error) {
+	result := &RouteSyncResult{}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		permissions, err := s.permissionRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+		existing := make(map[RouteInfo]*model.Permission)
+		for i := range permissions {
+			permission := &permissions[i]
+			if permission.PermissionType != PermissionTypeApi || !strings.HasPrefix(permission.Path, "/") {
+				continue
+			}
+			key := RouteInfo{Method: strings.ToUpper(permission.Method), Path: permission.Path}
+			if _, ok := existing[key]; !ok {
+				existing[key] = permission
+			}
+			if !matchAnyRoute(permission, routes) {
+				result.Stale = append(result.Stale, *permission)
+			}
+		}
+
+		count, err := s.permissionRepo.CountChildren(ctx, 0)
+		if err != nil {
+			return err
+		}
+		level, err := s.childLevel(ctx, 0)
+		if err != nil {
+			return err
+		}
+		now := time.Now().Format(permissionTimeLayout)
+		for _, route := range routes {
+			name := names[route]
+			if permission, ok := existing[route]; ok {
+				if name == "" || permission.PermissionName == name {
+					continue
+				}
+				permission.PermissionName = name
+				permission.UpdatedAt = now
+				if err := s.permissionRepo.Update(ctx, permission); err != nil {
+					return err
+				}
+				result.Renamed = append(result.Renamed, *permission)
+				continue
+			}
+			if name == "" {
+				name = route.Method + " " + route.Path
+			}
+			count++
+			permission := &model.Permission{
+				PermissionName: name,
+				PermissionType: PermissionTypeApi,
+				Level:          level,
+				Path:           route.Path,
+				Method:         route.Method,
+				Sort:           formatSort(int(count)),
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := s.permissionRepo.Create(ctx, permission); err != nil {
+				return err
+			}
+			existing[route] = permission
+			result.Created = append(result.Created, *permission)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func matchAnyRoute(permission *model.Permission, routes []RouteInfo) bool {
+	for _, route := range routes {
+		if MatchApiPermission(permission.Path, permission.Method, ApiRequest{Method: route.Method, Route: route.Path}) {
+			return true
+		}
+	}
+	return false
+}
+
+// SwaggerRouteNames 从swagger文档中取每个路由和方法的summary。
+// 文档中的路径不含basePath，参数写作{userId}，按已注册的路由解析为路由模板；旧版/:api分组中的路由取第一个匹配的路径
+func SwaggerRouteNames(doc string, routes []RouteInfo) (map[RouteInfo]string, error) {
+	var swagger struct {
+		BasePath string                                         `json:"basePath"`
+		Paths    map[string]map[string]struct{ Summary string } `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &swagger); err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	names := make(map[RouteInfo]string)
+	for _, path := range paths {
+		for method, operation := range swagger.Paths[path] {
+			method = strings.ToUpper(method)
+			route, _, ok := ResolveRoute(routes, method, strings.TrimSuffix(swagger.BasePath, "/")+path)
+			if !ok || operation.Summary == "" {
+				continue
+			}
+			key := RouteInfo{Method: method, Path: route}
+			if _, ok := names[key]; !ok {
+				names[key] = operation.Summary
+			}
+		}
+	}
+	return names, nil
+}
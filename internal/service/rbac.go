@@ -0,0 +1,399 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"github.com/duke-git/lancet/v2/slice"
+	"strings"
+)
+
+// MethodAll 权限的Method为all或为空时匹配全部请求方法
+const MethodAll = "all"
+
+// ApiRequest RBAC校验的请求
+type ApiRequest struct {
+	Method string
+	Route  string // gin注册的完整路由模板，如/v1/users/:userId
+	Api    string // 旧版/:api分组中的api参数，其他路由为空
+}
+
+// RouteInfo gin注册的路由
+type RouteInfo struct {
+	Method string
+	Path   string
+}
+
+// 权限评估结果的原因
+const (
+	ExplainDenyRule  = "deny_rule"
+	ExplainAllowRule = "allow_rule"
+	ExplainNoMatch   = "no_match"
+	ExplainNoRoles   = "no_roles"
+)
+
+// 角色的来源
+const (
+	RoleSourceDirect    = "direct"
+	RoleSourceGrant     = "grant"
+	RoleSourceProposed  = "proposed"
+	RoleSourceInherited = "inherited"
+)
+
+type RBACService interface {
+	CacheStats(ctx context.Context) *v1.PermissionCacheStatsData
+	FlushCache(ctx context.Context)
+	SetRoutes(routes []RouteInfo)
+	Explain(ctx context.Context, req *v1.ExplainPermissionRequest) (*v1.ExplainPermissionData, error)
+}
+
+func NewRBACService(
+	service *Service,
+	permissionCacheRepo repository.PermissionCacheRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+) RBACService {
+	return &rbacService{
+		permissionCacheRepo: permissionCacheRepo,
+		userRepo:            userRepo,
+		roleRepo:            roleRepo,
+		Service:             service,
+	}
+}
+
+type rbacService struct {
+	permissionCacheRepo repository.PermissionCacheRepository
+	userRepo            repository.UserRepository
+	roleRepo            repository.RoleRepository
+	routes              []RouteInfo
+	*Service
+}
+
+func (s *rbacService) CacheStats(ctx context.Context) *v1.PermissionCacheStatsData {
+	stats := s.permissionCacheRepo.Stats()
+	data := &v1.PermissionCacheStatsData{
+		Enabled:       stats.Enabled,
+		Redis:         stats.Redis,
+		Size:          stats.Size,
+		Capacity:      stats.Capacity,
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		Evictions:     stats.Evictions,
+		Invalidations: stats.Invalidations,
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		data.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return data
+}
+
+// FlushCache 直接修改数据库中的角色或权限后，手动清除全部权限缓存
+func (s *rbacService) FlushCache(ctx context.Context) {
+	s.permissionCacheRepo.InvalidateAll(ctx)
+}
+
+// SetRoutes 保存已注册的路由，由http服务注册完全部路由后调用一次
+func (s *rbacService) SetRoutes(routes []RouteInfo) {
+	s.routes = routes
+}
+
+// Explain 按RBACAuth相同的规则评估用户能否访问该路由，并返回匹配的角色、权限和deny规则；
+// 直接查询数据库，不经过权限缓存。dryRun不为空时按调整后的角色评估，同时返回当前角色的结果
+func (s *rbacService) Explain(ctx context.Context, req *v1.ExplainPermissionRequest) (*v1.ExplainPermissionData, error) {
+	method := strings.ToUpper(req.Method)
+	route, params, ok := ResolveRoute(s.routes, method, req.Path)
+	if !ok {
+		return nil, v1.ErrRouteNotFound
+	}
+	if _, err := s.userRepo.GetByID(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	direct, granted, err := s.roleRepo.GetAssignedRoleIds(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := s.roleRepo.GetParents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := assignedRoleSources(direct, granted, nil, nil)
+	proposed := current
+	if req.DryRun != nil {
+		if len(req.DryRun.AddRoleIds) > 0 {
+			roles, err := s.roleRepo.GetByIds(ctx, req.DryRun.AddRoleIds)
+			if err != nil {
+				return nil, err
+			}
+			if len(roles) != len(slice.Unique(req.DryRun.AddRoleIds)) {
+				return nil, v1.ErrRoleNotFound
+			}
+		}
+		proposed = assignedRoleSources(direct, granted, req.DryRun.AddRoleIds, req.DryRun.RemoveRoleIds)
+	}
+	currentIds := repository.InheritedRoleIds(parents, current.ids)
+	proposedIds := repository.InheritedRoleIds(parents, proposed.ids)
+	roleIds := slice.Union(currentIds, proposedIds)
+
+	roles, err := s.roleRepo.GetByIds(ctx, roleIds)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.roleRepo.GetApiPermissions(ctx, roleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	apiReq := ApiRequest{Method: method, Route: route, Api: params["api"]}
+	data := &v1.ExplainPermissionData{
+		UserId:   req.UserId,
+		Method:   method,
+		Path:     req.Path,
+		Route:    route,
+		DryRun:   req.DryRun != nil,
+		Decision: explainDecision(apiReq, proposedIds, proposed, roles, permissions),
+	}
+	if req.DryRun != nil {
+		decision := explainDecision(apiReq, currentIds, current, roles, permissions)
+		data.Current = &decision
+	}
+	return data, nil
+}
+
+// roleSource 直接分配或临时授权的角色及来源，保持分配顺序
+type roleSource struct {
+	ids     []uint
+	sources map[uint]string
+}
+
+// assignedRoleSources 合并直接分配和临时授权的角色，再按演练新增和移除的角色调整；同时分配的角色记为direct
+func assignedRoleSources(direct []uint, granted []uint, add []uint, remove []uint) roleSource {
+	assigned := roleSource{sources: make(map[uint]string, len(direct)+len(granted)+len(add))}
+	appendSource := func(ids []uint, source string) {
+		for _, id := range ids {
+			if _, ok := assigned.sources[id]; ok || slice.Contain(remove, id) {
+				continue
+			}
+			assigned.sources[id] = source
+			assigned.ids = append(assigned.ids, id)
+		}
+	}
+	appendSource(direct, RoleSourceDirect)
+	appendSource(granted, RoleSourceGrant)
+	appendSource(add, RoleSourceProposed)
+	return assigned
+}
+
+// explainDecision 只使用roleIds中角色的权限评估请求：匹配到deny规则时拒绝，否则匹配到allow权限时允许
+func explainDecision(req ApiRequest, roleIds []uint, assigned roleSource, roles []model.Role, permissions []repository.RoleApiPermission) v1.ExplainDecisionData {
+	decision := v1.ExplainDecisionData{
+		Roles:       make([]v1.ExplainRoleData, 0, len(roleIds)),
+		Permissions: make([]v1.ExplainRuleData, 0),
+		DenyRules:   make([]v1.ExplainRuleData, 0),
+	}
+	inRoles := make(map[uint]bool, len(roleIds))
+	for _, id := range roleIds {
+		inRoles[id] = true
+	}
+	for _, role := range roles {
+		if !inRoles[role.Id] {
+			continue
+		}
+		source, ok := assigned.sources[role.Id]
+		if !ok {
+			source = RoleSourceInherited
+		}
+		decision.Roles = append(decision.Roles, v1.ExplainRoleData{
+			Id:        role.Id,
+			RoleLabel: role.RoleLabel,
+			RoleName:  role.RoleName,
+			Source:    source,
+		})
+	}
+	if len(decision.Roles) == 0 {
+		decision.Reason = ExplainNoRoles
+		return decision
+	}
+
+	// 同一权限被多个角色关联时合并为一条规则
+	for _, permission := range permissions {
+		if !inRoles[permission.RoleId] || !MatchApiPermission(permission.Path, permission.Method, req) {
+			continue
+		}
+		rules := &decision.Permissions
+		if permission.Effect == model.EffectDeny {
+			rules = &decision.DenyRules
+		}
+		merged := false
+		for i := range *rules {
+			if (*rules)[i].PermissionId == permission.Id {
+				(*rules)[i].RoleIds = append((*rules)[i].RoleIds, permission.RoleId)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			*rules = append(*rules, v1.ExplainRuleData{
+				PermissionId:   permission.Id,
+				PermissionName: permission.PermissionName,
+				Path:           permission.Path,
+				Method:         permission.Method,
+				Effect:         permission.Effect,
+				RoleIds:        []uint{permission.RoleId},
+			})
+		}
+	}
+	switch {
+	case len(decision.DenyRules) > 0:
+		decision.Reason = ExplainDenyRule
+	case len(decision.Permissions) > 0:
+		decision.Allowed = true
+		decision.Reason = ExplainAllowRule
+	default:
+		decision.Reason = ExplainNoMatch
+	}
+	return decision
+}
+
+// ResolveRoute 返回与method和path匹配的已注册路由模板及路由参数，path也可以直接是已注册的路由模板；
+// 多个路由匹配时与gin相同，静态段优先于参数段，参数段优先于*通配段
+func ResolveRoute(routes []RouteInfo, method string, path string) (string, map[string]string, bool) {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	pathSegments := splitRoute(path)
+	var (
+		best       string
+		bestKinds  []int
+		bestParams map[string]string
+	)
+	for _, route := range routes {
+		if route.Method != method {
+			continue
+		}
+		if route.Path == path {
+			return route.Path, routeParams(splitRoute(route.Path), pathSegments, true), true
+		}
+		kinds, ok := matchRouteTemplate(splitRoute(route.Path), pathSegments)
+		if !ok {
+			continue
+		}
+		if bestKinds == nil || lessKinds(kinds, bestKinds) {
+			best, bestKinds = route.Path, kinds
+			bestParams = routeParams(splitRoute(route.Path), pathSegments, false)
+		}
+	}
+	return best, bestParams, bestKinds != nil
+}
+
+// 路由段的类型，值越小优先级越高
+const (
+	segmentStatic = iota
+	segmentParam
+	segmentCatchAll
+)
+
+// matchRouteTemplate 判断路径是否匹配gin路由模板，返回模板每一段的类型
+func matchRouteTemplate(template []string, path []string) ([]int, bool) {
+	kinds := make([]int, 0, len(template))
+	for i, segment := range template {
+		if strings.HasPrefix(segment, "*") {
+			return append(kinds, segmentCatchAll), true
+		}
+		if i >= len(path) {
+			return nil, false
+		}
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			kinds = append(kinds, segmentParam)
+		case segment == path[i]:
+			kinds = append(kinds, segmentStatic)
+		default:
+			return nil, false
+		}
+	}
+	return kinds, len(template) == len(path)
+}
+
+func lessKinds(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) > len(b)
+}
+
+// routeParams 提取路由参数，path就是路由模板时没有参数值
+func routeParams(template []string, path []string, isTemplate bool) map[string]string {
+	params := make(map[string]string)
+	if isTemplate {
+		return params
+	}
+	for i, segment := range template {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			params[segment[1:]] = path[i]
+		case strings.HasPrefix(segment, "*"):
+			params[segment[1:]] = "/" + strings.Join(path[i:], "/")
+		}
+	}
+	return params
+}
+
+// MatchApiPermission 判断api权限是否允许该请求。
+// path以/开头时匹配完整路由模板：*匹配任意一段，位于末尾时匹配其后的全部路径，如/v1/users/*；
+// 否则按旧规则与/:api分组中的api参数比较。
+// method不区分大小写，多个用逗号分隔，all或为空表示全部方法
+func MatchApiPermission(path string, method string, req ApiRequest) bool {
+	if !matchMethod(method, req.Method) {
+		return false
+	}
+	if !strings.HasPrefix(path, "/") {
+		return req.Api != "" && path == req.Api
+	}
+	return matchRoute(path, req.Route)
+}
+
+func matchMethod(allowed string, method string) bool {
+	if allowed == "" {
+		return true
+	}
+	for _, m := range strings.Split(allowed, ",") {
+		m = strings.TrimSpace(m)
+		if strings.EqualFold(m, MethodAll) || m == "*" || strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRoute(pattern string, route string) bool {
+	patternSegments := splitRoute(pattern)
+	routeSegments := splitRoute(route)
+	for i, segment := range patternSegments {
+		if i >= len(routeSegments) {
+			return false
+		}
+		if segment == "*" {
+			// 末尾的*匹配剩余的全部路径
+			if i == len(patternSegments)-1 {
+				return true
+			}
+			continue
+		}
+		if segment != routeSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(routeSegments)
+}
+
+func splitRoute(route string) []string {
+	route = strings.Trim(route, "/")
+	if route == "" {
+		return nil
+	}
+	return strings.Split(route, "/")
+}
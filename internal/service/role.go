@@ -0,0 +1,401 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"github.com/duke-git/lancet/v2/slice"
+)
+
+type RoleService interface {
+	Create(ctx context.Context, req *v1.CreateRoleRequest) (*v1.RoleData, error)
+	List(ctx context.Context, req *v1.ListRolesRequest) (*v1.ListRolesResponseData, error)
+	Get(ctx context.Context, id uint) (*v1.RoleData, error)
+	Update(ctx context.Context, id uint, req *v1.UpdateRoleRequest) (*v1.RoleData, error)
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	AttachPermissions(ctx context.Context, id uint, permissionIds []uint, effect string) error
+	DetachPermissions(ctx context.Context, id uint, permissionIds []uint) error
+	GetUserRoles(ctx context.Context, userId string) ([]v1.RoleData, error)
+	SetUserRoles(ctx context.Context, userId string, roleIds []uint) error
+	AddUserRoles(ctx context.Context, userId string, roleIds []uint) error
+	RemoveUserRoles(ctx context.Context, userId string, roleIds []uint) error
+	AssignUsers(ctx context.Context, id uint, userIds []string) error
+	ListUsers(ctx context.Context, id uint, req *v1.ListRoleUsersRequest) (*v1.ListRoleUsersResponseData, error)
+}
+
+func NewRoleService(service *Service, roleRepo repository.RoleRepository, userRepo repository.UserRepository, permissionCacheRepo repository.PermissionCacheRepository) RoleService {
+	return &roleService{
+		roleRepo:            roleRepo,
+		userRepo:            userRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		Service:             service,
+	}
+}
+
+type roleService struct {
+	roleRepo            repository.RoleRepository
+	userRepo            repository.UserRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	*Service
+}
+
+func (s *roleService) Create(ctx context.Context, req *v1.CreateRoleRequest) (*v1.RoleData, error) {
+	role := &model.Role{
+		RoleLabel:      req.RoleLabel,
+		RoleName:       req.RoleName,
+		ParentId:       req.ParentId,
+		TwoFactor:      req.TwoFactor,
+		DataScope:      dataScopeOrDefault(req.DataScope),
+		ApproverRoleId: req.ApproverRoleId,
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.checkParent(ctx, 0, req.ParentId); err != nil {
+			return err
+		}
+		if req.ApproverRoleId != 0 {
+			if err := s.checkRoles(ctx, []uint{req.ApproverRoleId}); err != nil {
+				return err
+			}
+		}
+		if err := s.roleRepo.Create(ctx, role); err != nil {
+			return err
+		}
+		if role.DataScope != model.DataScopeCustom {
+			return nil
+		}
+		return s.roleRepo.SetDataScopeDepts(ctx, role.Id, slice.Unique(req.DataScopeDeptIds))
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := roleToData(role)
+	return &data, nil
+}
+
+func (s *roleService) List(ctx context.Context, req *v1.ListRolesRequest) (*v1.ListRolesResponseData, error) {
+	offset, limit := req.Offset()
+	roles, total, err := s.roleRepo.List(ctx, req.Keyword, req.Deleted, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	data := &v1.ListRolesResponseData{List: make([]v1.RoleData, 0, len(roles)), Total: total}
+	for i := range roles {
+		data.List = append(data.List, roleToData(&roles[i]))
+	}
+	return data, nil
+}
+
+// Get 返回角色及其直接关联的权限，不包括继承的权限
+func (s *roleService) Get(ctx context.Context, id uint) (*v1.RoleData, error) {
+	role, err := s.roleRepo.GetById(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.roleRepo.GetPermissions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data := roleToData(role)
+	if role.DataScope == model.DataScopeCustom {
+		if data.DataScopeDeptIds, err = s.roleRepo.GetDataScopeDepts(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	data.Permissions = make([]v1.RolePermissionData, 0, len(permissions))
+	for _, permission := range permissions {
+		data.Permissions = append(data.Permissions, v1.RolePermissionData{
+			Id:             permission.Id,
+			PermissionName: permission.PermissionName,
+			PermissionType: permission.PermissionType,
+			Path:           permission.Path,
+			Method:         permission.Method,
+			Effect:         permission.Effect,
+		})
+	}
+	return &data, nil
+}
+
+func (s *roleService) Update(ctx context.Context, id uint, req *v1.UpdateRoleRequest) (*v1.RoleData, error) {
+	var role *model.Role
+	parentChanged := false
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		role, err = s.roleRepo.GetById(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		// 注册和初始化数据按标识查找默认角色
+		if role.RoleLabel == model.DefaultRoleLabel && req.RoleLabel != model.DefaultRoleLabel {
+			return v1.ErrRoleProtected
+		}
+		if role.ParentId != req.ParentId {
+			if err = s.checkParent(ctx, id, req.ParentId); err != nil {
+				return err
+			}
+			parentChanged = true
+		}
+		// 可以由本角色的用户审批同角色的申请
+		if req.ApproverRoleId != 0 && req.ApproverRoleId != id && req.ApproverRoleId != role.ApproverRoleId {
+			if err = s.checkRoles(ctx, []uint{req.ApproverRoleId}); err != nil {
+				return err
+			}
+		}
+		role.ApproverRoleId = req.ApproverRoleId
+		role.RoleLabel = req.RoleLabel
+		role.RoleName = req.RoleName
+		role.ParentId = req.ParentId
+		role.TwoFactor = req.TwoFactor
+		role.DataScope = dataScopeOrDefault(req.DataScope)
+		if err = s.roleRepo.Update(ctx, role); err != nil {
+			return err
+		}
+		var deptIds []uint
+		if role.DataScope == model.DataScopeCustom {
+			deptIds = slice.Unique(req.DataScopeDeptIds)
+		}
+		return s.roleRepo.SetDataScopeDepts(ctx, id, deptIds)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parentChanged {
+		s.permissionCacheRepo.InvalidateAll(ctx)
+	}
+	data := roleToData(role)
+	return &data, nil
+}
+
+// Delete 软删除角色，拥有该角色的用户立即失去其权限
+func (s *roleService) Delete(ctx context.Context, id uint) error {
+	role, err := s.roleRepo.GetById(ctx, id, false)
+	if err != nil {
+		return err
+	}
+	if role.RoleLabel == model.DefaultRoleLabel {
+		return v1.ErrRoleProtected
+	}
+	if err = s.roleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.permissionCacheRepo.InvalidateAll(ctx)
+	return nil
+}
+
+func (s *roleService) Restore(ctx context.Context, id uint) error {
+	role, err := s.roleRepo.GetById(ctx, id, true)
+	if err != nil {
+		return err
+	}
+	if !role.DeletedAt.Valid {
+		return nil
+	}
+	if err = s.roleRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+	s.permissionCacheRepo.InvalidateAll(ctx)
+	return nil
+}
+
+// AttachPermissions effect为deny时拒绝这些权限，优先于其他角色的allow，为空时按allow处理
+func (s *roleService) AttachPermissions(ctx context.Context, id uint, permissionIds []uint, effect string) error {
+	if effect == "" {
+		effect = model.EffectAllow
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.roleRepo.GetById(ctx, id, false); err != nil {
+			return err
+		}
+		return s.roleRepo.AttachPermissions(ctx, id, slice.Unique(permissionIds), effect)
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.InvalidateAll(ctx)
+	return nil
+}
+
+func (s *roleService) DetachPermissions(ctx context.Context, id uint, permissionIds []uint) error {
+	if _, err := s.roleRepo.GetById(ctx, id, false); err != nil {
+		return err
+	}
+	if err := s.roleRepo.DetachPermissions(ctx, id, slice.Unique(permissionIds)); err != nil {
+		return err
+	}
+	s.permissionCacheRepo.InvalidateAll(ctx)
+	return nil
+}
+
+func (s *roleService) GetUserRoles(ctx context.Context, userId string) ([]v1.RoleData, error) {
+	if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+		return nil, err
+	}
+	roles, err := s.roleRepo.GetUserRoles(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]v1.RoleData, 0, len(roles))
+	for i := range roles {
+		data = append(data, roleToData(&roles[i]))
+	}
+	return data, nil
+}
+
+// SetUserRoles 用roleIds替换用户的全部角色
+func (s *roleService) SetUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+			return err
+		}
+		roleIds = slice.Unique(roleIds)
+		if err := s.checkRoles(ctx, roleIds); err != nil {
+			return err
+		}
+		if err := s.roleRepo.ClearUserRoles(ctx, userId); err != nil {
+			return err
+		}
+		return s.roleRepo.AddUserRoles(ctx, []string{userId}, roleIds)
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userId)
+	return nil
+}
+
+func (s *roleService) AddUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+			return err
+		}
+		roleIds = slice.Unique(roleIds)
+		if err := s.checkRoles(ctx, roleIds); err != nil {
+			return err
+		}
+		return s.roleRepo.AddUserRoles(ctx, []string{userId}, roleIds)
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userId)
+	return nil
+}
+
+func (s *roleService) RemoveUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+			return err
+		}
+		return s.roleRepo.RemoveUserRoles(ctx, userId, slice.Unique(roleIds))
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userId)
+	return nil
+}
+
+// AssignUsers 批量为用户关联角色，任一用户不存在时全部不关联
+func (s *roleService) AssignUsers(ctx context.Context, id uint, userIds []string) error {
+	userIds = slice.Unique(userIds)
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.roleRepo.GetById(ctx, id, false); err != nil {
+			return err
+		}
+		users, err := s.userRepo.GetByIDs(ctx, userIds)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(userIds) {
+			return v1.ErrUserNotFound
+		}
+		return s.roleRepo.AddUserRoles(ctx, userIds, []uint{id})
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userIds...)
+	return nil
+}
+
+func (s *roleService) ListUsers(ctx context.Context, id uint, req *v1.ListRoleUsersRequest) (*v1.ListRoleUsersResponseData, error) {
+	if _, err := s.roleRepo.GetById(ctx, id, false); err != nil {
+		return nil, err
+	}
+	offset, limit := req.Offset()
+	users, total, err := s.roleRepo.ListUsers(ctx, id, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	data := &v1.ListRoleUsersResponseData{List: make([]v1.RoleUserData, 0, len(users)), Total: total}
+	for _, user := range users {
+		data.List = append(data.List, v1.RoleUserData{
+			UserId:         user.UserId,
+			Nickname:       user.Nickname,
+			Email:          user.Email,
+			ServiceAccount: user.ServiceAccount,
+		})
+	}
+	return data, nil
+}
+
+// checkParent 父角色必须存在且未删除，沿父角色向上不能回到角色自身
+func (s *roleService) checkParent(ctx context.Context, id uint, parentId uint) error {
+	if parentId == 0 {
+		return nil
+	}
+	parents, err := s.roleRepo.GetParents(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := parents[parentId]; !ok {
+		return v1.ErrRoleNotFound
+	}
+	for _, ancestor := range repository.InheritedRoleIds(parents, []uint{parentId}) {
+		if ancestor == id {
+			return v1.ErrRoleCycle
+		}
+	}
+	return nil
+}
+
+// checkRoles 角色必须存在且未删除
+func (s *roleService) checkRoles(ctx context.Context, roleIds []uint) error {
+	if len(roleIds) == 0 {
+		return nil
+	}
+	roles, err := s.roleRepo.GetByIds(ctx, roleIds)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(roleIds) {
+		return v1.ErrRoleNotFound
+	}
+	return nil
+}
+
+func roleToData(role *model.Role) v1.RoleData {
+	data := v1.RoleData{
+		Id:             role.Id,
+		RoleLabel:      role.RoleLabel,
+		RoleName:       role.RoleName,
+		ParentId:       role.ParentId,
+		TwoFactor:      role.TwoFactor,
+		DataScope:      role.DataScope,
+		ApproverRoleId: role.ApproverRoleId,
+		CreatedAt:      role.CreatedAt,
+		UpdatedAt:      role.UpdatedAt,
+	}
+	if role.DeletedAt.Valid {
+		data.DeletedAt = &role.DeletedAt.Time
+	}
+	return data
+}
+
+func dataScopeOrDefault(dataScope string) string {
+	if dataScope == "" {
+		return model.DataScopeAll
+	}
+	return dataScope
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+type RoleGrantService interface {
+	Create(ctx context.Context, actorId string, userId string, req *v1.CreateRoleGrantRequest) (*v1.RoleGrantData, error)
+	List(ctx context.Context, userId string, req *v1.ListRoleGrantsRequest) ([]v1.RoleGrantData, error)
+	Revoke(ctx context.Context, actorId string, userId string, id uint) error
+	EndExpired(ctx context.Context) (int, error)
+}
+
+func NewRoleGrantService(
+	service *Service,
+	roleGrantRepo repository.RoleGrantRepository,
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	permissionCacheRepo repository.PermissionCacheRepository,
+) RoleGrantService {
+	return &roleGrantService{
+		roleGrantRepo:       roleGrantRepo,
+		roleRepo:            roleRepo,
+		userRepo:            userRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		Service:             service,
+	}
+}
+
+type roleGrantService struct {
+	roleGrantRepo       repository.RoleGrantRepository
+	roleRepo            repository.RoleRepository
+	userRepo            repository.UserRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	*Service
+}
+
+// Create 限时授予用户角色，开始时间为空时立即生效
+func (s *roleGrantService) Create(ctx context.Context, actorId string, userId string, req *v1.CreateRoleGrantRequest) (*v1.RoleGrantData, error) {
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if !req.EndsAt.After(startsAt) || !req.EndsAt.After(now) {
+		return nil, v1.ErrRoleGrantPeriod
+	}
+
+	grant := &model.RoleGrant{
+		UserId:    userId,
+		RoleId:    req.RoleId,
+		StartsAt:  startsAt,
+		EndsAt:    req.EndsAt,
+		Reason:    req.Reason,
+		GrantedBy: actorId,
+	}
+	var role *model.Role
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		role, err = s.roleRepo.GetById(ctx, req.RoleId, false)
+		if err != nil {
+			if errors.Is(err, v1.ErrNotFound) {
+				return v1.ErrRoleNotFound
+			}
+			return err
+		}
+		// 授权跟随用户所在的租户
+		grant.TenantId = user.TenantId
+		return s.roleGrantRepo.Create(ctx, grant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userId)
+	data := roleGrantToData(grant, role)
+	return &data, nil
+}
+
+func (s *roleGrantService) List(ctx context.Context, userId string, req *v1.ListRoleGrantsRequest) ([]v1.RoleGrantData, error) {
+	if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+		return nil, err
+	}
+	grants, err := s.roleGrantRepo.ListByUser(ctx, userId, req.Ended)
+	if err != nil {
+		return nil, err
+	}
+	roleIds := make([]uint, 0, len(grants))
+	for _, grant := range grants {
+		roleIds = append(roleIds, grant.RoleId)
+	}
+	roles := make(map[uint]*model.Role, len(roleIds))
+	if len(roleIds) > 0 {
+		list, err := s.roleRepo.GetByIds(ctx, roleIds)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			roles[list[i].Id] = &list[i]
+		}
+	}
+	data := make([]v1.RoleGrantData, 0, len(grants))
+	for i := range grants {
+		data = append(data, roleGrantToData(&grants[i], roles[grants[i].RoleId]))
+	}
+	return data, nil
+}
+
+// Revoke 提前结束用户的临时授权
+func (s *roleGrantService) Revoke(ctx context.Context, actorId string, userId string, id uint) error {
+	grant, err := s.roleGrantRepo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if grant.UserId != userId {
+		return v1.ErrNotFound
+	}
+	revoked, err := s.roleGrantRepo.Revoke(ctx, id, actorId, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return v1.ErrRoleGrantEnded
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userId)
+	return nil
+}
+
+// EndExpired 记录到期授权的结束时间并清除相关用户的权限缓存，返回本次结束的授权数；
+// 到期的授权在权限判断时已经不再生效，权限缓存也不会超过授权的结束时间，这里只负责记录结束原因
+func (s *roleGrantService) EndExpired(ctx context.Context) (int, error) {
+	grants, err := s.roleGrantRepo.EndExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	userIds := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		s.logger.WithContext(ctx).Info("role grant ended",
+			zap.Uint("GrantId", grant.Id),
+			zap.String("UserId", grant.UserId),
+			zap.Uint("RoleId", grant.RoleId),
+			zap.Time("EndedAt", grant.EndsAt))
+		userIds = append(userIds, grant.UserId)
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userIds...)
+	return len(grants), nil
+}
+
+// roleGrantToData role为空表示角色已删除
+func roleGrantToData(grant *model.RoleGrant, role *model.Role) v1.RoleGrantData {
+	data := v1.RoleGrantData{
+		Id:        grant.Id,
+		UserId:    grant.UserId,
+		RoleId:    grant.RoleId,
+		StartsAt:  grant.StartsAt,
+		EndsAt:    grant.EndsAt,
+		Reason:    grant.Reason,
+		GrantedBy: grant.GrantedBy,
+		EndedAt:   grant.EndedAt,
+		EndReason: grant.EndReason,
+		RevokedBy: grant.RevokedBy,
+		CreatedAt: grant.CreatedAt,
+	}
+	if role != nil {
+		data.RoleLabel = role.RoleLabel
+		data.RoleName = role.RoleName
+	}
+	return data
+}
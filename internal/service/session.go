@@ -0,0 +1,78 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/repository"
+	"context"
+)
+
+type SessionService interface {
+	ListSessions(ctx context.Context, userId string, currentSessionId string) ([]*v1.SessionData, error)
+	RevokeOwnSession(ctx context.Context, userId string, sessionId string) error
+	ForceRevokeSession(ctx context.Context, userId string, sessionId string) error
+	ForceRevokeUserSessions(ctx context.Context, userId string) error
+}
+
+func NewSessionService(service *Service, sessionRepo repository.SessionRepository, userRepo repository.UserRepository, tokenService TokenService) SessionService {
+	return &sessionService{
+		sessionRepo:  sessionRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
+		Service:      service,
+	}
+}
+
+type sessionService struct {
+	sessionRepo  repository.SessionRepository
+	userRepo     repository.UserRepository
+	tokenService TokenService
+	*Service
+}
+
+func (s *sessionService) ListSessions(ctx context.Context, userId string, currentSessionId string) ([]*v1.SessionData, error) {
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*v1.SessionData, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, &v1.SessionData{
+			SessionId:  session.SessionId,
+			UserId:     session.UserId,
+			Device:     session.Device,
+			Ip:         session.Ip,
+			UserAgent:  session.UserAgent,
+			IssuedAt:   session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.SessionId == currentSessionId,
+		})
+	}
+	return list, nil
+}
+
+// RevokeOwnSession 用户只能结束属于自己的会话
+func (s *sessionService) RevokeOwnSession(ctx context.Context, userId string, sessionId string) error {
+	session, err := s.sessionRepo.GetBySessionId(ctx, sessionId)
+	if err != nil {
+		return err
+	}
+	if session.UserId != userId {
+		return v1.ErrNotFound
+	}
+	return s.tokenService.RevokeSession(ctx, sessionId)
+}
+
+// ForceRevokeSession 管理员结束指定用户的会话，会话不属于该用户时视为不存在
+func (s *sessionService) ForceRevokeSession(ctx context.Context, userId string, sessionId string) error {
+	return s.RevokeOwnSession(ctx, userId, sessionId)
+}
+
+// ForceRevokeUserSessions 用户级吊销不区分租户，先在调用者所属的租户内确认用户存在
+func (s *sessionService) ForceRevokeUserSessions(ctx context.Context, userId string) error {
+	if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+		return err
+	}
+	return s.tokenService.LogoutAll(ctx, userId)
+}
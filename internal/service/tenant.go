@@ -0,0 +1,160 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"context"
+	"github.com/duke-git/lancet/v2/slice"
+)
+
+// TenantService 平台管理员管理租户，全部操作跨越租户，不按调用者所属的租户过滤
+type TenantService interface {
+	Create(ctx context.Context, req *v1.CreateTenantRequest) (*v1.TenantData, error)
+	List(ctx context.Context, req *v1.ListTenantsRequest) (*v1.ListTenantsResponseData, error)
+	Get(ctx context.Context, id uint) (*v1.TenantData, error)
+	Update(ctx context.Context, id uint, req *v1.UpdateTenantRequest) (*v1.TenantData, error)
+	Delete(ctx context.Context, id uint) error
+	MoveUsers(ctx context.Context, id uint, userIds []string) error
+}
+
+func NewTenantService(
+	service *Service,
+	tenantRepo repository.TenantRepository,
+	userRepo repository.UserRepository,
+	permissionCacheRepo repository.PermissionCacheRepository,
+	tokenService TokenService,
+) TenantService {
+	return &tenantService{
+		tenantRepo:          tenantRepo,
+		userRepo:            userRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		tokenService:        tokenService,
+		Service:             service,
+	}
+}
+
+type tenantService struct {
+	tenantRepo          repository.TenantRepository
+	userRepo            repository.UserRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	tokenService        TokenService
+	*Service
+}
+
+// Create 创建租户及其默认角色
+func (s *tenantService) Create(ctx context.Context, req *v1.CreateTenantRequest) (*v1.TenantData, error) {
+	ctx = repository.WithoutTenant(ctx)
+	tenant := &model.Tenant{
+		Code: req.Code,
+		Name: req.Name,
+	}
+	err := s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.tenantRepo.Create(ctx, tenant); err != nil {
+			return err
+		}
+		_, err := s.tenantRepo.CreateDefaultRole(ctx, tenant.Id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenantToData(tenant), nil
+}
+
+func (s *tenantService) List(ctx context.Context, req *v1.ListTenantsRequest) (*v1.ListTenantsResponseData, error) {
+	offset, limit := req.Offset()
+	tenants, total, err := s.tenantRepo.List(repository.WithoutTenant(ctx), req.Keyword, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	data := &v1.ListTenantsResponseData{List: make([]v1.TenantData, 0, len(tenants)), Total: total}
+	for i := range tenants {
+		data.List = append(data.List, *tenantToData(&tenants[i]))
+	}
+	return data, nil
+}
+
+func (s *tenantService) Get(ctx context.Context, id uint) (*v1.TenantData, error) {
+	tenant, err := s.tenantRepo.GetById(repository.WithoutTenant(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return tenantToData(tenant), nil
+}
+
+func (s *tenantService) Update(ctx context.Context, id uint, req *v1.UpdateTenantRequest) (*v1.TenantData, error) {
+	ctx = repository.WithoutTenant(ctx)
+	tenant, err := s.tenantRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tenant.Name = req.Name
+	if err = s.tenantRepo.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenantToData(tenant), nil
+}
+
+// Delete 只能删除没有用户的租户，其角色和部门一并删除
+func (s *tenantService) Delete(ctx context.Context, id uint) error {
+	return s.tm.Transaction(repository.WithoutTenant(ctx), func(ctx context.Context) error {
+		if _, err := s.tenantRepo.GetById(ctx, id); err != nil {
+			return err
+		}
+		count, err := s.tenantRepo.CountUsers(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return v1.ErrTenantNotEmpty
+		}
+		return s.tenantRepo.Delete(ctx, id)
+	})
+}
+
+// MoveUsers 将用户移到租户中，用户只保留新租户的默认角色；
+// 已签发的令牌中仍是原来的租户，在同一事务中注销用户的全部会话
+func (s *tenantService) MoveUsers(ctx context.Context, id uint, userIds []string) error {
+	userIds = slice.Unique(userIds)
+	err := s.tm.Transaction(repository.WithoutTenant(ctx), func(ctx context.Context) error {
+		if _, err := s.tenantRepo.GetById(ctx, id); err != nil {
+			return err
+		}
+		users, err := s.userRepo.GetByIDs(ctx, userIds)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(userIds) {
+			return v1.ErrUserNotFound
+		}
+		role, err := s.tenantRepo.GetDefaultRole(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = s.tenantRepo.MoveUsers(ctx, userIds, id, role.Id); err != nil {
+			return err
+		}
+		for _, userId := range userIds {
+			if err = s.tokenService.LogoutAll(ctx, userId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.permissionCacheRepo.Invalidate(ctx, userIds...)
+	return nil
+}
+
+func tenantToData(tenant *model.Tenant) *v1.TenantData {
+	return &v1.TenantData{
+		Id:        tenant.Id,
+		Code:      tenant.Code,
+		Name:      tenant.Name,
+		CreatedAt: tenant.CreatedAt,
+		UpdatedAt: tenant.UpdatedAt,
+	}
+}
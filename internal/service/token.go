@@ -0,0 +1,245 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ClientInfo 发起登录或刷新请求的客户端信息，记录到会话中
+type ClientInfo struct {
+	Device    string
+	Ip        string
+	UserAgent string
+}
+
+type TokenService interface {
+	IssueTokens(ctx context.Context, userId string, client ClientInfo) (*v1.LoginResponseData, error)
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*v1.LoginResponseData, error)
+	Logout(ctx context.Context, claims *jwt.MyCustomClaims) error
+	LogoutAll(ctx context.Context, userId string) error
+	RevokeSession(ctx context.Context, sessionId string) error
+	IsRevoked(ctx context.Context, claims *jwt.MyCustomClaims) (bool, error)
+	JWKS() jwt.JSONWebKeySet
+}
+
+func NewTokenService(
+	service *Service,
+	tokenRepo repository.TokenRepository,
+	revocationRepo repository.RevocationRepository,
+	sessionRepo repository.SessionRepository,
+	userRepo repository.UserRepository,
+) TokenService {
+	return &tokenService{
+		tokenRepo:      tokenRepo,
+		revocationRepo: revocationRepo,
+		sessionRepo:    sessionRepo,
+		userRepo:       userRepo,
+		Service:        service,
+	}
+}
+
+type tokenService struct {
+	tokenRepo      repository.TokenRepository
+	revocationRepo repository.RevocationRepository
+	sessionRepo    repository.SessionRepository
+	userRepo       repository.UserRepository
+	*Service
+}
+
+// IssueTokens 登录成功后开启新会话，会话ID同时作为刷新令牌族ID
+func (s *tokenService) IssueTokens(ctx context.Context, userId string, client ClientInfo) (*v1.LoginResponseData, error) {
+	sessionId, err := s.sid.GenString()
+	if err != nil {
+		return nil, err
+	}
+
+	var data *v1.LoginResponseData
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userId)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		// 登录时ctx中没有租户信息，需要显式写入用户所属的租户
+		if err := s.sessionRepo.Create(ctx, &model.Session{
+			SessionId:  sessionId,
+			UserId:     userId,
+			TenantId:   user.TenantId,
+			Device:     client.Device,
+			Ip:         client.Ip,
+			UserAgent:  client.UserAgent,
+			LastSeenAt: now,
+			ExpiresAt:  now.Add(s.jwt.RefreshExpire()),
+		}); err != nil {
+			return err
+		}
+		data, err = s.issue(ctx, user, sessionId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Refresh 轮换刷新令牌：旧令牌只能使用一次，重复使用会吊销整个令牌族
+func (s *tokenService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*v1.LoginResponseData, error) {
+	token, err := s.tokenRepo.GetRefreshTokenByHash(ctx, jwt.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrRefreshTokenInvalid
+		}
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return nil, v1.ErrRefreshTokenInvalid
+	}
+	if token.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, token)
+	}
+	now := time.Now()
+	if now.After(token.ExpiresAt) {
+		return nil, v1.ErrRefreshTokenInvalid
+	}
+
+	var (
+		data   *v1.LoginResponseData
+		reused bool
+	)
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		ok, err := s.tokenRepo.MarkRefreshTokenUsed(ctx, token.Id, now)
+		if err != nil {
+			return err
+		}
+		// 并发请求已抢先使用了该令牌
+		if !ok {
+			reused = true
+			return nil
+		}
+		// 每次签发时重新读取用户所属的租户，用户被移到其他租户后刷新得到的令牌随之变化
+		user, err := s.userRepo.GetByID(ctx, token.UserId)
+		// 用户已删除
+		if errors.Is(err, v1.ErrNotFound) {
+			return v1.ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if data, err = s.issue(ctx, user, token.FamilyId); err != nil {
+			return err
+		}
+		return s.sessionRepo.Touch(ctx, token.FamilyId, client.Ip, now, now.Add(s.jwt.RefreshExpire()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, s.revokeReusedFamily(ctx, token)
+	}
+	return data, nil
+}
+
+// Logout 吊销当前访问令牌及其所属会话
+func (s *tokenService) Logout(ctx context.Context, claims *jwt.MyCustomClaims) error {
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if claims.ID != "" {
+			revoked := &model.RevokedToken{
+				Jti:    claims.ID,
+				UserId: claims.UserId,
+			}
+			if claims.ExpiresAt != nil {
+				revoked.ExpiresAt = claims.ExpiresAt.Time
+			}
+			if err := s.revocationRepo.RevokeToken(ctx, revoked); err != nil {
+				return err
+			}
+		}
+		if claims.SessionId == "" {
+			return nil
+		}
+		return s.RevokeSession(ctx, claims.SessionId)
+	})
+}
+
+// LogoutAll 注销用户的所有会话：此前签发的访问令牌和全部刷新令牌均失效
+func (s *tokenService) LogoutAll(ctx context.Context, userId string) error {
+	now := time.Now()
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.revocationRepo.RevokeUserTokens(ctx, userId, now); err != nil {
+			return err
+		}
+		return s.tokenRepo.RevokeUserRefreshTokens(ctx, userId, now)
+	})
+}
+
+// RevokeSession 结束会话：吊销会话下的访问令牌和刷新令牌族
+func (s *tokenService) RevokeSession(ctx context.Context, sessionId string) error {
+	now := time.Now()
+	return s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.revocationRepo.RevokeSession(ctx, sessionId, now); err != nil {
+			return err
+		}
+		return s.tokenRepo.RevokeRefreshTokenFamily(ctx, sessionId, now)
+	})
+}
+
+func (s *tokenService) IsRevoked(ctx context.Context, claims *jwt.MyCustomClaims) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	revoked, err := s.revocationRepo.IsRevoked(ctx, claims.ID, claims.SessionId, claims.UserId, issuedAt)
+	if err != nil || revoked || claims.ActorId == "" {
+		return revoked, err
+	}
+	// 发起模拟的管理员注销全部会话后，其签发的模拟令牌一并失效
+	return s.revocationRepo.IsRevoked(ctx, "", "", claims.ActorId, issuedAt)
+}
+
+func (s *tokenService) JWKS() jwt.JSONWebKeySet {
+	return s.jwt.JWKS()
+}
+
+// issue 签发访问令牌和会话下新的刷新令牌
+func (s *tokenService) issue(ctx context.Context, user *model.User, sessionId string) (*v1.LoginResponseData, error) {
+	accessToken, _, err := s.jwt.GenAccessToken(jwt.MyCustomClaims{
+		UserId:    user.UserId,
+		SessionId: sessionId,
+		TenantId:  user.TenantId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.jwt.GenRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.tokenRepo.CreateRefreshToken(ctx, &model.RefreshToken{
+		TokenHash: jwt.HashToken(refreshToken),
+		FamilyId:  sessionId,
+		UserId:    user.UserId,
+		ExpiresAt: time.Now().Add(s.jwt.RefreshExpire()),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &v1.LoginResponseData{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwt.AccessExpire().Seconds()),
+	}, nil
+}
+
+func (s *tokenService) revokeReusedFamily(ctx context.Context, token *model.RefreshToken) error {
+	s.logger.WithContext(ctx).Warn("refresh token reuse detected", zap.String("UserId", token.UserId), zap.String("FamilyId", token.FamilyId))
+	if err := s.RevokeSession(ctx, token.FamilyId); err != nil {
+		return err
+	}
+	return v1.ErrRefreshTokenReused
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/pkg/totp"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTotpIssuer = "admin-webrtc-go"
+	recoveryCodeCount = 10
+)
+
+type TwoFactorService interface {
+	Setup(ctx context.Context, userId string) (*v1.TwoFactorSetupData, error)
+	Confirm(ctx context.Context, userId string, code string) ([]string, error)
+	Disable(ctx context.Context, userId string, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error)
+	Verify(ctx context.Context, userId string, code string) error
+	Status(ctx context.Context, userId string) (enabled bool, required bool, err error)
+}
+
+func NewTwoFactorService(
+	service *Service,
+	conf *viper.Viper,
+	twoFactorRepo repository.TwoFactorRepository,
+	userRepo repository.UserRepository,
+	loginAttemptService LoginAttemptService,
+) TwoFactorService {
+	issuer := conf.GetString("security.totp.issuer")
+	if issuer == "" {
+		issuer = defaultTotpIssuer
+	}
+	return &twoFactorService{
+		twoFactorRepo:       twoFactorRepo,
+		userRepo:            userRepo,
+		loginAttemptService: loginAttemptService,
+		issuer:              issuer,
+		skew:                conf.GetInt("security.totp.skew"),
+		Service:             service,
+	}
+}
+
+type twoFactorService struct {
+	twoFactorRepo       repository.TwoFactorRepository
+	userRepo            repository.UserRepository
+	loginAttemptService LoginAttemptService
+	issuer              string
+	skew                int // 允许的时钟偏差(时间步)
+	*Service
+}
+
+// Setup 生成新密钥，需调用Confirm校验验证码后才会生效
+func (s *twoFactorService) Setup(ctx context.Context, userId string) (*v1.TwoFactorSetupData, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userId)
+	if err != nil && !errors.Is(err, v1.ErrNotFound) {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		return nil, v1.ErrTwoFactorAlreadyEnabled
+	}
+	user, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.twoFactorRepo.Save(ctx, &model.UserTwoFactor{
+		UserId: userId,
+		Secret: secret,
+	}); err != nil {
+		return nil, err
+	}
+	return &v1.TwoFactorSetupData{
+		Secret:     secret,
+		OtpauthUri: totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm 校验验证码后启用两步验证，并返回一组新的恢复码
+func (s *twoFactorService) Confirm(ctx context.Context, userId string, code string) ([]string, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return nil, v1.ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, v1.ErrTwoFactorAlreadyEnabled
+	}
+
+	var codes []string
+	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.verifyTotp(ctx, twoFactor, code); err != nil {
+			return err
+		}
+		if err := s.twoFactorRepo.Enable(ctx, userId, time.Now()); err != nil {
+			return err
+		}
+		codes, err = s.replaceRecoveryCodes(ctx, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 关闭两步验证，角色要求两步验证的用户不能关闭
+func (s *twoFactorService) Disable(ctx context.Context, userId string, code string) error {
+	required, err := s.twoFactorRepo.RequiredByRole(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if required {
+		return v1.ErrTwoFactorRequired
+	}
+	return s.limitAttempts(ctx, userId, func() error {
+		return s.tm.Transaction(ctx, func(ctx context.Context) error {
+			if err := s.Verify(ctx, userId, code); err != nil {
+				return err
+			}
+			return s.twoFactorRepo.Delete(ctx, userId)
+		})
+	})
+}
+
+// RegenerateRecoveryCodes 作废旧的恢复码并生成新的一组
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error) {
+	var codes []string
+	err := s.limitAttempts(ctx, userId, func() error {
+		return s.tm.Transaction(ctx, func(ctx context.Context) error {
+			if err := s.Verify(ctx, userId, code); err != nil {
+				return err
+			}
+			var err error
+			codes, err = s.replaceRecoveryCodes(ctx, userId)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// limitAttempts 验证码与登录共用账号的失败计数，防止持有访问令牌的人暴力猜测；
+// 在事务外检查和记录，事务回滚不会撤销失败次数
+func (s *twoFactorService) limitAttempts(ctx context.Context, userId string, verify func() error) error {
+	if err := s.loginAttemptService.Check(ctx, userId, ""); err != nil {
+		return err
+	}
+	if err := verify(); err != nil {
+		if errors.Is(err, v1.ErrTwoFactorCodeInvalid) {
+			if err := s.loginAttemptService.Fail(ctx, userId, ""); err != nil {
+				s.logger.WithContext(ctx).Error("loginAttemptService.Fail error", zap.Error(err))
+			}
+		}
+		return err
+	}
+	return s.loginAttemptService.Succeed(ctx, userId)
+}
+
+// Verify 校验验证码，6位数字按TOTP处理，其余按恢复码处理
+func (s *twoFactorService) Verify(ctx context.Context, userId string, code string) error {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return v1.ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if twoFactor.EnabledAt == nil {
+		return v1.ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTotp(ctx, twoFactor, code)
+	}
+	ok, err := s.twoFactorRepo.UseRecoveryCode(ctx, userId, jwt.HashToken(normalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return v1.ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+func (s *twoFactorService) Status(ctx context.Context, userId string) (bool, bool, error) {
+	required, err := s.twoFactorRepo.RequiredByRole(ctx, userId)
+	if err != nil {
+		return false, false, err
+	}
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userId)
+	if err != nil {
+		if errors.Is(err, v1.ErrNotFound) {
+			return false, required, nil
+		}
+		return false, false, err
+	}
+	return twoFactor.EnabledAt != nil, required, nil
+}
+
+func (s *twoFactorService) verifyTotp(ctx context.Context, twoFactor *model.UserTwoFactor, code string) error {
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), s.skew)
+	if !ok {
+		return v1.ErrTwoFactorCodeInvalid
+	}
+	// 同一验证码在有效窗口内只能使用一次
+	ok, err := s.twoFactorRepo.UseStep(ctx, twoFactor.UserId, step)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return v1.ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+func (s *twoFactorService) replaceRecoveryCodes(ctx context.Context, userId string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, jwt.HashToken(normalizeRecoveryCode(code)))
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode 生成形如abcde-fghij的恢复码
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和连字符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -4,8 +4,10 @@ import (
 	v1 "admin-webrtc-go/api/v1"
 	"admin-webrtc-go/internal/model"
 	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
 	"context"
 	"errors"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	sortPkg "sort"
 	"strconv"
@@ -14,17 +16,35 @@ import (
 
 type UserService interface {
 	Register(ctx context.Context, req *v1.RegisterRequest) error
-	Login(ctx context.Context, req *v1.LoginRequest) (string, error)
+	Login(ctx context.Context, req *v1.LoginRequest, client ClientInfo) (*v1.LoginResponseData, error)
+	LoginTwoFactorSetup(ctx context.Context, req *v1.TwoFactorLoginSetupRequest) (*v1.TwoFactorSetupData, error)
+	LoginTwoFactor(ctx context.Context, req *v1.TwoFactorLoginRequest, client ClientInfo) (*v1.LoginResponseData, error)
+	UnlockLogin(ctx context.Context, userId string) error
 	GetProfile(ctx context.Context, userId string) (*v1.GetProfileResponseData, error)
 	UpdateProfile(ctx context.Context, userId string, req *v1.UpdateProfileRequest) error
-	CheckAPIAuthPermission(ctx context.Context, userId string, api string) (bool, error)
+	CheckAPIAuthPermission(ctx context.Context, userId string, req ApiRequest) (bool, error)
 	GetMenuTreeByUserAuth(ctx context.Context, userId string, sort string) ([]*v1.GetMenuTreeResponseData, error)
 }
 
-func NewUserService(service *Service, userRepo repository.UserRepository) UserService {
+func NewUserService(
+	service *Service,
+	userRepo repository.UserRepository,
+	permissionCacheRepo repository.PermissionCacheRepository,
+	tokenService TokenService,
+	twoFactorService TwoFactorService,
+	accountService AccountService,
+	loginAttemptService LoginAttemptService,
+	passwordService PasswordService,
+) UserService {
 	return &userService{
-		userRepo: userRepo,
-		Service:  service,
+		userRepo:            userRepo,
+		permissionCacheRepo: permissionCacheRepo,
+		tokenService:        tokenService,
+		twoFactorService:    twoFactorService,
+		accountService:      accountService,
+		loginAttemptService: loginAttemptService,
+		passwordService:     passwordService,
+		Service:             service,
 	}
 }
 
@@ -34,7 +54,13 @@ type treeNode struct {
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo            repository.UserRepository
+	permissionCacheRepo repository.PermissionCacheRepository
+	tokenService        TokenService
+	twoFactorService    TwoFactorService
+	accountService      AccountService
+	loginAttemptService LoginAttemptService
+	passwordService     PasswordService
 	*Service
 }
 
@@ -48,22 +74,20 @@ func (s *userService) Register(ctx context.Context, req *v1.RegisterRequest) err
 		return v1.ErrEmailAlreadyUse
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return err
-	}
 	// Generate user ID
 	userId, err := s.sid.GenString()
 	if err != nil {
 		return err
 	}
 	user = &model.User{
-		UserId:   userId,
-		Email:    req.Email,
-		Password: string(hashedPassword),
+		UserId: userId,
+		Email:  req.Email,
 	}
 
 	err = s.tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.passwordService.SetPassword(ctx, user, req.Password); err != nil {
+			return err
+		}
 		if err := s.userRepo.GetUserDefaultSeed(ctx, user); err != nil {
 			return err
 		}
@@ -79,25 +103,145 @@ func (s *userService) Register(ctx context.Context, req *v1.RegisterRequest) err
 	//	// TODO: other repo
 	//	return nil
 	//})
-	return err
+	if err != nil {
+		return err
+	}
+
+	// 验证邮件发送失败不影响注册，用户可以稍后重新发送
+	if err = s.accountService.SendVerifyEmail(ctx, user.UserId); err != nil {
+		s.logger.WithContext(ctx).Warn("send verify email failed", zap.String("UserId", user.UserId), zap.Error(err))
+	}
+	return nil
 }
 
-func (s *userService) Login(ctx context.Context, req *v1.LoginRequest) (string, error) {
+func (s *userService) Login(ctx context.Context, req *v1.LoginRequest, client ClientInfo) (*v1.LoginResponseData, error) {
+	if err := s.loginAttemptService.Check(ctx, "", client.Ip); err != nil {
+		return nil, err
+	}
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil || user == nil {
-		return "", v1.ErrUnauthorized
+		s.loginFailed(ctx, "", client.Ip)
+		return nil, v1.ErrUnauthorized
+	}
+	if err = s.loginAttemptService.Check(ctx, user.UserId, ""); err != nil {
+		return nil, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return "", err
+		s.loginFailed(ctx, user.UserId, client.Ip)
+		return nil, v1.ErrUnauthorized
+	}
+
+	if data, err := s.twoFactorChallenge(ctx, s.twoFactorService, user.UserId); err != nil || data != nil {
+		return data, err
 	}
-	token, err := s.jwt.GenToken(user.UserId, time.Now().Add(time.Hour*24*90))
+
+	if err = s.loginAttemptService.Succeed(ctx, user.UserId); err != nil {
+		return nil, err
+	}
+	return s.tokenService.IssueTokens(ctx, user.UserId, client)
+}
+
+// twoFactorChallenge 启用了两步验证或角色要求两步验证时返回质询令牌，否则返回nil由调用方直接签发令牌
+func (s *Service) twoFactorChallenge(ctx context.Context, twoFactorService TwoFactorService, userId string) (*v1.LoginResponseData, error) {
+	enabled, required, err := twoFactorService.Status(ctx, userId)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if !enabled && !required {
+		return nil, nil
 	}
+	purpose := jwt.PurposeTwoFactor
+	if !enabled {
+		purpose = jwt.PurposeTwoFactorSetup
+	}
+	challenge, err := s.jwt.GenChallengeToken(userId, purpose)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.LoginResponseData{
+		TwoFactorRequired:      enabled,
+		TwoFactorSetupRequired: !enabled,
+		ChallengeToken:         challenge,
+	}, nil
+}
 
-	return token, nil
+// LoginTwoFactorSetup 角色要求两步验证但尚未绑定的用户，凭质询令牌生成密钥
+func (s *userService) LoginTwoFactorSetup(ctx context.Context, req *v1.TwoFactorLoginSetupRequest) (*v1.TwoFactorSetupData, error) {
+	claims, err := s.parseChallenge(ctx, req.ChallengeToken, jwt.PurposeTwoFactorSetup)
+	if err != nil {
+		return nil, err
+	}
+	return s.twoFactorService.Setup(ctx, claims.UserId)
+}
+
+// LoginTwoFactor 校验验证码或恢复码后完成登录，质询令牌随即作废
+func (s *userService) LoginTwoFactor(ctx context.Context, req *v1.TwoFactorLoginRequest, client ClientInfo) (*v1.LoginResponseData, error) {
+	claims, err := s.parseChallenge(ctx, req.ChallengeToken, jwt.PurposeTwoFactor, jwt.PurposeTwoFactorSetup)
+	if err != nil {
+		return nil, err
+	}
+	// 验证码同样计入失败次数，防止暴力猜测
+	if err = s.loginAttemptService.Check(ctx, claims.UserId, client.Ip); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if claims.Purpose == jwt.PurposeTwoFactorSetup {
+		recoveryCodes, err = s.twoFactorService.Confirm(ctx, claims.UserId, req.Code)
+	} else {
+		err = s.twoFactorService.Verify(ctx, claims.UserId, req.Code)
+	}
+	if err != nil {
+		if errors.Is(err, v1.ErrTwoFactorCodeInvalid) {
+			s.loginFailed(ctx, claims.UserId, client.Ip)
+		}
+		return nil, err
+	}
+	if err = s.tokenService.Logout(ctx, claims); err != nil {
+		return nil, err
+	}
+	if err = s.loginAttemptService.Succeed(ctx, claims.UserId); err != nil {
+		return nil, err
+	}
+
+	data, err := s.tokenService.IssueTokens(ctx, claims.UserId, client)
+	if err != nil {
+		return nil, err
+	}
+	data.RecoveryCodes = recoveryCodes
+	return data, nil
+}
+
+// UnlockLogin 解除账号因登录失败过多导致的锁定
+func (s *userService) UnlockLogin(ctx context.Context, userId string) error {
+	if _, err := s.userRepo.GetByID(ctx, userId); err != nil {
+		return err
+	}
+	return s.loginAttemptService.Unlock(ctx, userId)
+}
+
+// loginFailed 记录失败次数，记录失败不影响返回给客户端的错误
+func (s *userService) loginFailed(ctx context.Context, userId string, ip string) {
+	if err := s.loginAttemptService.Fail(ctx, userId, ip); err != nil {
+		s.logger.WithContext(ctx).Error("loginAttemptService.Fail error", zap.Error(err))
+	}
+}
+
+func (s *userService) parseChallenge(ctx context.Context, challengeToken string, purposes ...string) (*jwt.MyCustomClaims, error) {
+	claims, err := s.jwt.ParsePurposeToken(challengeToken, purposes...)
+	if err != nil {
+		return nil, v1.ErrChallengeInvalid
+	}
+	revoked, err := s.tokenService.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, v1.ErrChallengeInvalid
+	}
+	return claims, nil
 }
 
 func (s *userService) GetProfile(ctx context.Context, userId string) (*v1.GetProfileResponseData, error) {
@@ -107,8 +251,9 @@ func (s *userService) GetProfile(ctx context.Context, userId string) (*v1.GetPro
 	}
 
 	return &v1.GetProfileResponseData{
-		UserId:   user.UserId,
-		Nickname: user.Nickname,
+		UserId:        user.UserId,
+		Nickname:      user.Nickname,
+		EmailVerified: user.EmailVerifiedAt != nil,
 	}, nil
 }
 
@@ -118,6 +263,10 @@ func (s *userService) UpdateProfile(ctx context.Context, userId string, req *v1.
 		return err
 	}
 
+	// 更换邮箱后需要重新验证
+	if user.Email != req.Email {
+		user.EmailVerifiedAt = nil
+	}
 	user.Email = req.Email
 	user.Nickname = req.Nickname
 
@@ -128,19 +277,56 @@ func (s *userService) UpdateProfile(ctx context.Context, userId string, req *v1.
 	return nil
 }
 
-func (s *userService) CheckAPIAuthPermission(ctx context.Context, userId string, api string) (bool, error) {
-	users, err := s.userRepo.GetUserWithRolesAndPermission(ctx, userId, "api", "")
-	// 数据库查不到用户的权限，返回false
+// CheckAPIAuthPermission 按以下顺序判断，先命中的生效：
+// 1. 用户任一角色（包括继承的角色）拒绝的api权限匹配请求时拒绝访问；
+// 2. 任一角色允许的api权限匹配请求时允许访问；
+// 3. 都不匹配时拒绝访问
+func (s *userService) CheckAPIAuthPermission(ctx context.Context, userId string, req ApiRequest) (bool, error) {
+	permissions, err := s.permissionCacheRepo.Load(ctx, userId, s.loadApiPermissions)
 	if err != nil {
 		return false, err
 	}
-	for _, user := range *users {
-		if user.Path == api {
-			return true, nil
+	// 数据库查不到用户的权限，返回false
+	if len(permissions) == 0 {
+		return false, v1.ErrEmptyRecord
+	}
+	allowed := false
+	for _, permission := range permissions {
+		if MatchApiPermission(permission.Path, permission.Method, req) {
+			if permission.Deny {
+				return false, nil
+			}
+			allowed = true
 		}
 	}
 
-	return false, nil
+	return allowed, nil
+}
+
+// loadApiPermissions 查询用户的api权限并去重，没有权限时返回空集合以便同样被缓存；
+// 同时返回最近的临时授权开始或结束时间，缓存不会跨过该时间
+func (s *userService) loadApiPermissions(ctx context.Context, userId string) ([]repository.ApiPermission, time.Time, error) {
+	validUntil, err := s.userRepo.NextRoleGrantChange(ctx, userId, time.Now())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	users, err := s.userRepo.GetUserWithRolesAndPermission(ctx, userId, "api", "")
+	if err != nil {
+		if errors.Is(err, v1.ErrEmptyRecord) {
+			return []repository.ApiPermission{}, validUntil, nil
+		}
+		return nil, time.Time{}, err
+	}
+	seen := make(map[repository.ApiPermission]bool, len(*users))
+	permissions := make([]repository.ApiPermission, 0, len(*users))
+	for _, user := range *users {
+		permission := repository.ApiPermission{Path: user.Path, Method: user.Method, Deny: user.Effect == model.EffectDeny}
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, validUntil, nil
 }
 
 func (s *userService) GetMenuTreeByUserAuth(ctx context.Context, userId string, sort string) ([]*v1.GetMenuTreeResponseData, error) {
@@ -149,7 +335,7 @@ func (s *userService) GetMenuTreeByUserAuth(ctx context.Context, userId string,
 		return nil, err
 	}
 
-	menuTree := convertToTree(users)
+	menuTree := pruneDenied(convertToTree(users), deniedPermissions(users))
 	if sort == "asc" || sort == "desc" {
 		recursiveSort(menuTree, sort)
 	}
@@ -163,28 +349,81 @@ func (s *userService) GetMenuTreeByUserAuth(ctx context.Context, userId string,
 }
 
 func convertToTree(users *[]repository.LoginedUser) []*v1.GetMenuTreeResponseData {
-	nodeMap := make(map[string]*v1.GetMenuTreeResponseData)
+	nodes := make([]*v1.GetMenuTreeResponseData, 0, len(*users))
+	for _, user := range *users {
+		nodes = append(nodes, &v1.GetMenuTreeResponseData{
+			Key:            strconv.FormatUint(uint64(user.PermissionId), 10),
+			Label:          user.PermissionName,
+			Sort:           user.Sort,
+			PermissionType: user.PermissionType,
+			ParentId:       strconv.FormatUint(uint64(user.ParentId), 10),
+			Level:          user.Level,
+			Icon:           user.Icon,
+			Route:          user.Route,
+			RouteFile:      user.RouteFile,
+			Path:           user.Path,
+			Method:         user.Method,
+			CreatedAt:      user.CreatedAt,
+			UpdatedAt:      user.UpdatedAt,
+			Children:       []*v1.GetMenuTreeResponseData{},
+		})
+	}
+	return buildMenuTree(nodes)
+}
 
+// deniedPermissions 返回被用户任一角色拒绝的权限
+func deniedPermissions(users *[]repository.LoginedUser) map[string]bool {
+	denied := make(map[string]bool)
 	for _, user := range *users {
-		node := &v1.GetMenuTreeResponseData{
-			Key:       strconv.FormatUint(uint64(user.PermissionId), 10),
-			Label:     user.PermissionName,
-			Sort:      user.Sort,
-			ParentId:  strconv.FormatUint(uint64(user.ParentId), 10),
-			Level:     user.Level,
-			Route:     user.Route,
-			RouteFile: user.RouteFile,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Children:  []*v1.GetMenuTreeResponseData{},
+		if user.Effect == model.EffectDeny {
+			denied[strconv.FormatUint(uint64(user.PermissionId), 10)] = true
+		}
+	}
+	return denied
+}
+
+// pruneDenied 移除被拒绝的菜单及其全部子菜单，即使其他角色允许
+func pruneDenied(nodes []*v1.GetMenuTreeResponseData, denied map[string]bool) []*v1.GetMenuTreeResponseData {
+	if len(denied) == 0 {
+		return nodes
+	}
+	var result []*v1.GetMenuTreeResponseData
+	for _, node := range nodes {
+		if denied[node.Key] {
+			continue
 		}
-		nodeMap[strconv.FormatUint(uint64(user.PermissionId), 10)] = node
+		node.Children = pruneDenied(node.Children, denied)
+		result = append(result, node)
 	}
+	return result
+}
 
-	var root []*v1.GetMenuTreeResponseData
-	for _, node := range nodeMap {
-		if parent, exist := nodeMap[node.ParentId]; exist {
-			parent.Children = append(parent.Children, node)
+// buildMenuTree 按ParentId组装节点并保持nodes中的顺序，父节点不在nodes中的作为根节点，Key重复时只保留最后一个
+func buildMenuTree(nodes []*v1.GetMenuTreeResponseData) []*v1.GetMenuTreeResponseData {
+	return buildTree(nodes, func(node *v1.GetMenuTreeResponseData) (string, string) {
+		return node.Key, node.ParentId
+	}, func(parent *v1.GetMenuTreeResponseData, child *v1.GetMenuTreeResponseData) {
+		parent.Children = append(parent.Children, child)
+	})
+}
+
+// buildTree 按父节点组装树并保持nodes中的顺序，父节点不在nodes中的节点作为根节点；
+// key返回节点及其父节点的标识，标识重复时只保留最后一个节点
+func buildTree[K comparable, T any](nodes []*T, key func(node *T) (K, K), appendChild func(parent *T, child *T)) []*T {
+	nodeMap := make(map[K]*T, len(nodes))
+	for _, node := range nodes {
+		id, _ := key(node)
+		nodeMap[id] = node
+	}
+
+	var root []*T
+	for _, node := range nodes {
+		id, parentId := key(node)
+		if nodeMap[id] != node {
+			continue
+		}
+		if parent, exist := nodeMap[parentId]; exist {
+			appendChild(parent, node)
 		} else {
 			root = append(root, node)
 		}
@@ -195,12 +434,12 @@ func convertToTree(users *[]repository.LoginedUser) []*v1.GetMenuTreeResponseDat
 
 func recursiveSort(nodes []*v1.GetMenuTreeResponseData, sort string) {
 	if sort == "asc" {
-		sortPkg.Slice(nodes, func(i, j int) bool {
+		sortPkg.SliceStable(nodes, func(i, j int) bool {
 			return nodes[i].Sort < nodes[j].Sort
 		})
 	}
 	if sort == "desc" {
-		sortPkg.Slice(nodes, func(i, j int) bool {
+		sortPkg.SliceStable(nodes, func(i, j int) bool {
 			return nodes[i].Sort > nodes[j].Sort
 		})
 	}
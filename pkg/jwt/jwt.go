@@ -1,7 +1,12 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"regexp"
 	"time"
 
@@ -9,53 +14,225 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	defaultAccessExpire  = 15 * time.Minute
+	defaultRefreshExpire = 7 * 24 * time.Hour
+
+	challengeExpire = 5 * time.Minute
+)
+
+// 专用令牌的用途，带用途的令牌不能作为访问令牌使用
+const (
+	PurposeTwoFactor      = "2fa"            // 待输入两步验证码
+	PurposeTwoFactorSetup = "2fa_setup"      // 角色要求两步验证但尚未绑定
+	PurposeVerifyEmail    = "verify_email"   // 邮箱验证链接，sub为待验证的邮箱
+	PurposeResetPassword  = "reset_password" // 重置密码链接，sub为当前密码的指纹
+)
+
 type JWT struct {
-	key []byte
+	keys          map[string]*signingKey
+	active        *signingKey
+	validMethods  []string
+	jwks          JSONWebKeySet
+	accessExpire  time.Duration
+	refreshExpire time.Duration
 }
 
 type MyCustomClaims struct {
-	UserId string
+	UserId    string
+	SessionId string `json:",omitempty"` // 登录会话，与刷新令牌族一一对应
+	Purpose   string `json:",omitempty"` // 为空表示访问令牌
+	ApiKeyId  string `json:"-"`          // 通过API密钥认证时由中间件设置，不会出现在令牌中
+	ActorId   string `json:",omitempty"` // 模拟登录时为发起模拟的管理员，UserId为被模拟的用户
+	TenantId  uint   `json:",omitempty"` // 用户所属租户，0表示平台租户
 	jwt.RegisteredClaims
 }
 
 func NewJwt(conf *viper.Viper) *JWT {
-	return &JWT{key: []byte(conf.GetString("security.jwt.key"))}
+	accessExpire := conf.GetDuration("security.jwt.access_expire")
+	if accessExpire <= 0 {
+		accessExpire = defaultAccessExpire
+	}
+	refreshExpire := conf.GetDuration("security.jwt.refresh_expire")
+	if refreshExpire <= 0 {
+		refreshExpire = defaultRefreshExpire
+	}
+
+	keys, active, err := loadKeys(conf)
+	if err != nil {
+		panic(err)
+	}
+	j := &JWT{
+		keys:          make(map[string]*signingKey, len(keys)),
+		active:        active,
+		jwks:          JSONWebKeySet{Keys: []JSONWebKey{}},
+		accessExpire:  accessExpire,
+		refreshExpire: refreshExpire,
+	}
+	methods := make(map[string]bool)
+	for _, key := range keys {
+		j.keys[key.kid] = key
+		if !methods[key.method.Alg()] {
+			methods[key.method.Alg()] = true
+			j.validMethods = append(j.validMethods, key.method.Alg())
+		}
+		if jwk, ok := key.jwk(); ok {
+			j.jwks.Keys = append(j.jwks.Keys, jwk)
+		}
+	}
+	return j
+}
+
+// JWKS 当前及退役的非对称公钥，供其他服务验签
+func (j *JWT) JWKS() JSONWebKeySet {
+	return j.jwks
+}
+
+// AccessExpire 访问令牌有效期
+func (j *JWT) AccessExpire() time.Duration {
+	return j.accessExpire
+}
+
+// RefreshExpire 刷新令牌有效期
+func (j *JWT) RefreshExpire() time.Duration {
+	return j.refreshExpire
 }
 
 func (j *JWT) GenToken(userId string, expiresAt time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, MyCustomClaims{
-		UserId: userId,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "",
-			Subject:   "",
-			ID:        "",
-			Audience:  []string{},
-		},
-	})
+	return j.Sign(MyCustomClaims{UserId: userId}, expiresAt)
+}
+
+// Sign 补全jti、签发时间等标准字段后使用当前密钥签名，只保留调用方设置的sub
+func (j *JWT) Sign(claims MyCustomClaims, expiresAt time.Time) (string, error) {
+	token, _, err := j.sign(claims, expiresAt)
+	return token, err
+}
+
+func (j *JWT) sign(claims MyCustomClaims, expiresAt time.Time) (string, string, error) {
+	// jti用于服务端吊销单个令牌
+	jti, err := randomString(16)
+	if err != nil {
+		return "", "", err
+	}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		Issuer:    "",
+		Subject:   claims.Subject,
+		ID:        jti,
+		Audience:  []string{},
+	}
+	token := jwt.NewWithClaims(j.active.method, claims)
+	if j.active.kid != "" {
+		token.Header["kid"] = j.active.kid
+	}
 
 	// Sign and get the complete encoded token as a string using the key
-	tokenString, err := token.SignedString(j.key)
+	tokenString, err := token.SignedString(j.active.signKey)
+	if err != nil {
+		return "", "", err
+	}
+	return tokenString, jti, nil
+}
+
+// GenAccessToken 生成短期访问令牌，返回令牌及其过期时间
+func (j *JWT) GenAccessToken(claims MyCustomClaims) (string, time.Time, error) {
+	expiresAt := time.Now().Add(j.accessExpire)
+	token, err := j.Sign(claims, expiresAt)
 	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// GenImpersonationToken 签发模拟登录的访问令牌，返回令牌及其jti，不附带会话和刷新令牌
+func (j *JWT) GenImpersonationToken(actorId string, userId string, tenantId uint, expiresAt time.Time) (string, string, error) {
+	return j.sign(MyCustomClaims{UserId: userId, ActorId: actorId, TenantId: tenantId}, expiresAt)
+}
+
+// GenChallengeToken 密码校验通过后签发的短期质询令牌，用于完成两步登录
+func (j *JWT) GenChallengeToken(userId string, purpose string) (string, error) {
+	return j.GenPurposeToken(userId, purpose, "", time.Now().Add(challengeExpire))
+}
+
+// GenPurposeToken 签发指定用途的令牌，subject用于把令牌绑定到签发时的状态
+func (j *JWT) GenPurposeToken(userId string, purpose string, subject string, expiresAt time.Time) (string, error) {
+	claims := MyCustomClaims{UserId: userId, Purpose: purpose}
+	claims.Subject = subject
+	return j.Sign(claims, expiresAt)
+}
+
+// GenRefreshToken 生成不透明的随机刷新令牌，服务端只保存其哈希值
+func (j *JWT) GenRefreshToken() (string, error) {
+	return randomString(32)
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-	return tokenString, nil
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken 计算令牌的sha256摘要，用于落库和查询
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
+// ParseToken 解析访问令牌，质询令牌会被拒绝
 func (j *JWT) ParseToken(tokenString string) (*MyCustomClaims, error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("token is not an access token")
+	}
+	return claims, nil
+}
+
+// ParsePurposeToken 解析带用途的令牌，用途须为purposes之一
+func (j *JWT) ParsePurposeToken(tokenString string, purposes ...string) (*MyCustomClaims, error) {
+	claims, err := j.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	for _, purpose := range purposes {
+		if claims.Purpose == purpose {
+			return claims, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected token purpose %q", claims.Purpose)
+}
+
+func (j *JWT) parse(tokenString string) (*MyCustomClaims, error) {
 	re := regexp.MustCompile(`(?i)Bearer `)
 	tokenString = re.ReplaceAllString(tokenString, "")
 	if tokenString == "" {
 		return nil, errors.New("token is empty")
 	}
-	token, err := jwt.ParseWithClaims(tokenString, &MyCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return j.key, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &MyCustomClaims{}, j.keyFunc, jwt.WithValidMethods(j.validMethods))
+	if err != nil {
+		return nil, err
+	}
 	if claims, ok := token.Claims.(*MyCustomClaims); ok && token.Valid {
 		return claims, nil
-	} else {
-		return nil, err
 	}
+	return nil, errors.New("token is invalid")
+}
+
+// keyFunc 按kid选择验签密钥，并要求令牌声明的算法与该密钥的算法一致
+func (j *JWT) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for kid %q", token.Method.Alg(), kid)
+	}
+	return key.verifyKey, nil
 }
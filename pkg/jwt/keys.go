@@ -0,0 +1,184 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+// KeyConfig security.jwt.keys中的一项，除active_kid指定的密钥外其余均为退役密钥，只用于验签
+type KeyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`        // HS256、RS256或EdDSA
+	Secret         string `mapstructure:"secret"`           // HS256密钥
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM私钥，退役密钥可不配置
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // PEM公钥，配置了私钥时可省略
+}
+
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{} // 退役密钥为nil
+	verifyKey interface{}
+}
+
+// JSONWebKey RFC 7517公钥
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// loadKeys 未配置security.jwt.keys时沿用security.jwt.key作为HS256密钥
+func loadKeys(conf *viper.Viper) ([]*signingKey, *signingKey, error) {
+	var configs []KeyConfig
+	if err := conf.UnmarshalKey("security.jwt.keys", &configs); err != nil {
+		return nil, nil, err
+	}
+	if len(configs) == 0 {
+		key := &signingKey{
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(conf.GetString("security.jwt.key")),
+			verifyKey: []byte(conf.GetString("security.jwt.key")),
+		}
+		return []*signingKey{key}, key, nil
+	}
+
+	var (
+		keys   = make([]*signingKey, 0, len(configs))
+		active *signingKey
+		seen   = make(map[string]bool, len(configs))
+	)
+	activeKid := conf.GetString("security.jwt.active_kid")
+	for _, c := range configs {
+		if seen[c.Kid] {
+			return nil, nil, fmt.Errorf("duplicate jwt kid %q", c.Kid)
+		}
+		seen[c.Kid] = true
+		key, err := newSigningKey(c)
+		if err != nil {
+			return nil, nil, fmt.Errorf("jwt key %q: %w", c.Kid, err)
+		}
+		if c.Kid == activeKid {
+			active = key
+		}
+		keys = append(keys, key)
+	}
+
+	if active == nil {
+		return nil, nil, fmt.Errorf("active jwt kid %q not found", activeKid)
+	}
+	if active.signKey == nil {
+		return nil, nil, fmt.Errorf("active jwt key %q has no private key", activeKid)
+	}
+	return keys, active, nil
+}
+
+func newSigningKey(c KeyConfig) (*signingKey, error) {
+	key := &signingKey{kid: c.Kid}
+	switch c.Algorithm {
+	case "HS256":
+		if c.Secret == "" {
+			return nil, fmt.Errorf("secret is required")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(c.Secret)
+		key.verifyKey = []byte(c.Secret)
+	case "RS256":
+		key.method = jwt.SigningMethodRS256
+		if c.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(c.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		}
+		if c.PublicKeyFile != "" {
+			pem, err := os.ReadFile(c.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = publicKey
+		}
+	case "EdDSA":
+		key.method = jwt.SigningMethodEdDSA
+		if c.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(c.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = privateKey.(ed25519.PrivateKey).Public()
+		}
+		if c.PublicKeyFile != "" {
+			pem, err := os.ReadFile(c.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := jwt.ParseEdPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = publicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", c.Algorithm)
+	}
+	if key.verifyKey == nil {
+		return nil, fmt.Errorf("private_key_file or public_key_file is required")
+	}
+	return key, nil
+}
+
+// jwk 对称密钥不对外公开
+func (k *signingKey) jwk() (JSONWebKey, bool) {
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JSONWebKey{
+			Kty: "RSA",
+			Kid: k.kid,
+			Use: "sig",
+			Alg: k.method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JSONWebKey{
+			Kty: "OKP",
+			Kid: k.kid,
+			Use: "sig",
+			Alg: k.method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}, true
+	}
+	return JSONWebKey{}, false
+}
@@ -0,0 +1,103 @@
+package mailer
+
+import (
+	"admin-webrtc-go/pkg/log"
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string // 纯文本正文
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewMailer 按mail.driver选择实现：smtp发送真实邮件，file写入本地目录，log只打印日志，后两者用于本地开发和测试
+func NewMailer(conf *viper.Viper, logger *log.Logger) Mailer {
+	from := conf.GetString("mail.from")
+	switch conf.GetString("mail.driver") {
+	case "smtp":
+		return NewSMTPMailer(
+			conf.GetString("mail.smtp.host"),
+			conf.GetInt("mail.smtp.port"),
+			conf.GetString("mail.smtp.username"),
+			conf.GetString("mail.smtp.password"),
+			from,
+		)
+	case "file":
+		return NewFileMailer(conf.GetString("mail.file_dir"), from, logger)
+	default:
+		return NewLogMailer(from, logger)
+	}
+}
+
+// FileMailer 每封邮件保存为一个.eml文件
+type FileMailer struct {
+	dir    string
+	from   string
+	logger *log.Logger
+}
+
+func NewFileMailer(dir string, from string, logger *log.Logger) *FileMailer {
+	if dir == "" {
+		dir = "storage/mail"
+	}
+	return &FileMailer{dir: dir, from: from, logger: logger}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(m.dir, fmt.Sprintf("%d.eml", time.Now().UnixNano()))
+	if err := os.WriteFile(name, buildMessage(m.from, msg), 0o600); err != nil {
+		return err
+	}
+	m.logger.WithContext(ctx).Info("mail saved", zap.Strings("to", msg.To), zap.String("subject", msg.Subject), zap.String("file", name))
+	return nil
+}
+
+// LogMailer 只把邮件内容写入日志
+type LogMailer struct {
+	from   string
+	logger *log.Logger
+}
+
+func NewLogMailer(from string, logger *log.Logger) *LogMailer {
+	return &LogMailer{from: from, logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	m.logger.WithContext(ctx).Info("mail", zap.String("from", m.from), zap.Strings("to", msg.To), zap.String("subject", msg.Subject), zap.String("body", msg.Body))
+	return nil
+}
+
+// buildMessage 拼装RFC 5322格式的邮件，主题按RFC 2047编码以支持中文
+func buildMessage(from string, msg *Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mimeEncode(msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func mimeEncode(s string) string {
+	return mime.QEncoding.Encode("UTF-8", s)
+}
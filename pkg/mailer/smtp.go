@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPMailer 通过SMTP发送邮件，服务器支持时自动启用STARTTLS
+type SMTPMailer struct {
+	addr     string
+	auth     smtp.Auth
+	from     string // 邮件头中的发件人，可以带显示名
+	envelope string // MAIL FROM使用的纯地址
+}
+
+func NewSMTPMailer(host string, port int, username string, password string, from string) *SMTPMailer {
+	m := &SMTPMailer{
+		addr:     fmt.Sprintf("%s:%d", host, port),
+		from:     from,
+		envelope: from,
+	}
+	if addr, err := mail.ParseAddress(from); err == nil {
+		m.envelope = addr.Address
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	for _, to := range msg.To {
+		// 防止通过收件人注入额外的邮件头
+		if strings.ContainsAny(to, "\r\n") {
+			return fmt.Errorf("invalid recipient %q", to)
+		}
+	}
+	return smtp.SendMail(m.addr, m.auth, m.envelope, msg.To, buildMessage(m.from, msg))
+}
@@ -0,0 +1,325 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+var ErrDisabled = errors.New("oidc is not enabled")
+
+// Config security.oidc配置
+type Config struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Name         string   `mapstructure:"name"` // 身份提供方名称，与外部用户ID一起标识外部身份
+	Issuer       string   `mapstructure:"issuer"`
+	ClientId     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectUrl  string   `mapstructure:"redirect_url"` // 前端回调地址，需要在身份提供方登记
+	Scopes       []string `mapstructure:"scopes"`
+	GroupsClaim  string   `mapstructure:"groups_claim"` // ID令牌中用户组所在的声明
+}
+
+// Discovery OpenID Provider Metadata中用到的字段
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// IDToken 校验通过的ID令牌
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// Provider OIDC授权码模式客户端，元数据和公钥在首次使用时获取
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      map[string]interface{}
+	keysAt    time.Time
+}
+
+func NewProvider(conf *viper.Viper) *Provider {
+	var config Config
+	if err := conf.UnmarshalKey("security.oidc", &config); err != nil {
+		panic(err)
+	}
+	if config.Name == "" {
+		config.Name = "oidc"
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	config.Issuer = strings.TrimRight(config.Issuer, "/")
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *Provider) Enabled() bool {
+	return p.config.Enabled
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL 生成跳转到身份提供方的授权地址，codeChallenge为PKCE S256质询
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.Discovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientId)
+	query.Set("redirect_uri", p.config.RedirectUrl)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange 用授权码和PKCE校验码换取ID令牌
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (string, error) {
+	discovery, err := p.Discovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectUrl},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic，客户端标识和密钥需要先做表单编码
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientId), url.QueryEscape(p.config.ClientSecret))
+
+	var token struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = p.doJSON(req, &token); err != nil {
+		return "", err
+	}
+	if token.Error != "" {
+		return "", fmt.Errorf("token endpoint: %s %s", token.Error, token.ErrorDescription)
+	}
+	if token.IdToken == "" {
+		return "", errors.New("token endpoint: id_token is missing")
+	}
+	return token.IdToken, nil
+}
+
+// Verify 校验ID令牌的签名、签发方、受众、有效期和nonce
+func (p *Provider) Verify(ctx context.Context, rawIDToken string, nonce string) (*IDToken, error) {
+	discovery, err := p.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientId),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("id token has no exp")
+	}
+	if claimString(claims, "nonce") != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+	// 多个受众时azp必须是本客户端
+	if aud, _ := claims.GetAudience(); len(aud) > 1 && claimString(claims, "azp") != p.config.ClientId {
+		return nil, errors.New("id token azp mismatch")
+	}
+
+	idToken := &IDToken{
+		Subject: claimString(claims, "sub"),
+		Email:   claimString(claims, "email"),
+		Name:    claimString(claims, "name"),
+		Groups:  claimStrings(claims, p.config.GroupsClaim),
+	}
+	if idToken.Subject == "" {
+		return nil, errors.New("id token has no sub")
+	}
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		idToken.EmailVerified = v
+	case string:
+		idToken.EmailVerified = v == "true"
+	}
+	return idToken, nil
+}
+
+// Discovery 获取并缓存身份提供方元数据
+func (p *Provider) Discovery(ctx context.Context) (*Discovery, error) {
+	if !p.config.Enabled {
+		return nil, ErrDisabled
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var discovery Discovery
+	if err = p.doJSON(req, &discovery); err != nil {
+		return nil, err
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("issuer mismatch: expected %q got %q", p.config.Issuer, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return nil, errors.New("incomplete openid configuration")
+	}
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key 按kid取验签公钥，找不到时重新拉取公钥集，应对身份提供方轮换密钥
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	// 限制刷新频率，防止伪造的kid导致频繁请求身份提供方
+	if time.Since(p.keysAt) < 10*time.Second {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.discovery.JwksUri, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set jsonWebKeySet
+	if err = p.doJSON(req, &set); err != nil {
+		return nil, err
+	}
+	p.keys = set.publicKeys()
+	p.keysAt = time.Now()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown kid %q", kid)
+}
+
+// lookupKey 令牌未携带kid且只有一把公钥时直接使用该公钥
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) doJSON(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	// 令牌端点出错时返回400和错误描述，交给调用方解析
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("%s %s: unexpected status %d", req.Method, req.URL, resp.StatusCode)
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+	}
+	return nil
+}
+
+// GenerateVerifier 生成随机字符串，用作state、nonce和PKCE校验码
+func GenerateVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// S256Challenge 计算PKCE校验码对应的S256质询
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func claimString(claims jwt.MapClaims, name string) string {
+	v, _ := claims[name].(string)
+	return v
+}
+
+// claimStrings 用户组声明可能是字符串数组，也可能是单个字符串
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"github.com/spf13/viper"
+	"strings"
+	"unicode"
+)
+
+// 常见弱密码列表，整理自公开的泄露密码排行，比较时忽略大小写
+//
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = loadCommonPasswords(commonPasswordsFile)
+
+// 校验规则名称，随错误详情返回给客户端
+const (
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+	RuleUpper     = "upper"
+	RuleLower     = "lower"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleCommon    = "common"
+	RuleHistory   = "history"
+)
+
+// Violation 未通过的规则
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectCommon  bool
+	History       int // 不能与最近History个密码相同，0表示不检查
+}
+
+// NewPolicy 读取security.password配置，未配置的项使用默认值
+func NewPolicy(conf *viper.Viper) *Policy {
+	intOr := func(key string, def int) int {
+		if conf.IsSet(key) {
+			return conf.GetInt(key)
+		}
+		return def
+	}
+	boolOr := func(key string, def bool) bool {
+		if conf.IsSet(key) {
+			return conf.GetBool(key)
+		}
+		return def
+	}
+	return &Policy{
+		MinLength:     intOr("security.password.min_length", 8),
+		MaxLength:     intOr("security.password.max_length", 72),
+		RequireUpper:  boolOr("security.password.require_upper", false),
+		RequireLower:  boolOr("security.password.require_lower", true),
+		RequireDigit:  boolOr("security.password.require_digit", true),
+		RequireSymbol: boolOr("security.password.require_symbol", false),
+		RejectCommon:  boolOr("security.password.reject_common", true),
+		History:       intOr("security.password.history", 5),
+	}
+}
+
+// Validate 返回所有未通过的规则，历史密码由调用方检查
+func (p *Policy) Validate(password string) []Violation {
+	var violations []Violation
+	length := len([]rune(password))
+	if length < p.MinLength {
+		violations = append(violations, Violation{RuleMinLength, fmt.Sprintf("密码长度不能少于%d个字符", p.MinLength)})
+	}
+	// bcrypt只使用前72字节，按字节计算
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		violations = append(violations, Violation{RuleMaxLength, fmt.Sprintf("密码长度不能超过%d个字节", p.MaxLength)})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{RuleUpper, "密码必须包含大写字母"})
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, Violation{RuleLower, "密码必须包含小写字母"})
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{RuleDigit, "密码必须包含数字"})
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{RuleSymbol, "密码必须包含特殊字符"})
+	}
+	if p.RejectCommon && IsCommon(password) {
+		violations = append(violations, Violation{RuleCommon, "密码过于常见，容易被猜到"})
+	}
+	return violations
+}
+
+// HistoryViolation 与历史密码相同时返回的规则
+func (p *Policy) HistoryViolation() Violation {
+	return Violation{RuleHistory, fmt.Sprintf("不能与最近%d次使用过的密码相同", p.History)}
+}
+
+func IsCommon(password string) bool {
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
+
+func loadCommonPasswords(content string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}
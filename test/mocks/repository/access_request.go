@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/access_request.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccessRequestRepository is a mock of AccessRequestRepository interface.
+type MockAccessRequestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessRequestRepositoryMockRecorder
+}
+
+// MockAccessRequestRepositoryMockRecorder is the mock recorder for MockAccessRequestRepository.
+type MockAccessRequestRepositoryMockRecorder struct {
+	mock *MockAccessRequestRepository
+}
+
+// NewMockAccessRequestRepository creates a new mock instance.
+func NewMockAccessRequestRepository(ctrl *gomock.Controller) *MockAccessRequestRepository {
+	mock := &MockAccessRequestRepository{ctrl: ctrl}
+	mock.recorder = &MockAccessRequestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessRequestRepository) EXPECT() *MockAccessRequestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddEvent mocks base method.
+func (m *MockAccessRequestRepository) AddEvent(ctx context.Context, event *model.AccessRequestEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent.
+func (mr *MockAccessRequestRepositoryMockRecorder) AddEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockAccessRequestRepository)(nil).AddEvent), ctx, event)
+}
+
+// Create mocks base method.
+func (m *MockAccessRequestRepository) Create(ctx context.Context, request *model.AccessRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessRequestRepositoryMockRecorder) Create(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessRequestRepository)(nil).Create), ctx, request)
+}
+
+// GetById mocks base method.
+func (m *MockAccessRequestRepository) GetById(ctx context.Context, id uint) (*model.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockAccessRequestRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAccessRequestRepository)(nil).GetById), ctx, id)
+}
+
+// GetEvents mocks base method.
+func (m *MockAccessRequestRepository) GetEvents(ctx context.Context, requestId uint) ([]model.AccessRequestEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx, requestId)
+	ret0, _ := ret[0].([]model.AccessRequestEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockAccessRequestRepositoryMockRecorder) GetEvents(ctx, requestId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockAccessRequestRepository)(nil).GetEvents), ctx, requestId)
+}
+
+// GetUserRoleIds mocks base method.
+func (m *MockAccessRequestRepository) GetUserRoleIds(ctx context.Context, userId string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoleIds", ctx, userId)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoleIds indicates an expected call of GetUserRoleIds.
+func (mr *MockAccessRequestRepositoryMockRecorder) GetUserRoleIds(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoleIds", reflect.TypeOf((*MockAccessRequestRepository)(nil).GetUserRoleIds), ctx, userId)
+}
+
+// HasPending mocks base method.
+func (m *MockAccessRequestRepository) HasPending(ctx context.Context, userId string, roleId uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPending", ctx, userId, roleId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPending indicates an expected call of HasPending.
+func (mr *MockAccessRequestRepositoryMockRecorder) HasPending(ctx, userId, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPending", reflect.TypeOf((*MockAccessRequestRepository)(nil).HasPending), ctx, userId, roleId)
+}
+
+// HasRole mocks base method.
+func (m *MockAccessRequestRepository) HasRole(ctx context.Context, userId string, roleId uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRole", ctx, userId, roleId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRole indicates an expected call of HasRole.
+func (mr *MockAccessRequestRepositoryMockRecorder) HasRole(ctx, userId, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRole", reflect.TypeOf((*MockAccessRequestRepository)(nil).HasRole), ctx, userId, roleId)
+}
+
+// List mocks base method.
+func (m *MockAccessRequestRepository) List(ctx context.Context, status, userId string, roleId uint, offset, limit int) ([]model.AccessRequest, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status, userId, roleId, offset, limit)
+	ret0, _ := ret[0].([]model.AccessRequest)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessRequestRepositoryMockRecorder) List(ctx, status, userId, roleId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessRequestRepository)(nil).List), ctx, status, userId, roleId, offset, limit)
+}
+
+// ListByUser mocks base method.
+func (m *MockAccessRequestRepository) ListByUser(ctx context.Context, userId string) ([]model.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userId)
+	ret0, _ := ret[0].([]model.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAccessRequestRepositoryMockRecorder) ListByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAccessRequestRepository)(nil).ListByUser), ctx, userId)
+}
+
+// ListPendingByApprover mocks base method.
+func (m *MockAccessRequestRepository) ListPendingByApprover(ctx context.Context, approverRoleIds []uint, excludeUserId string) ([]model.AccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingByApprover", ctx, approverRoleIds, excludeUserId)
+	ret0, _ := ret[0].([]model.AccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingByApprover indicates an expected call of ListPendingByApprover.
+func (mr *MockAccessRequestRepositoryMockRecorder) ListPendingByApprover(ctx, approverRoleIds, excludeUserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingByApprover", reflect.TypeOf((*MockAccessRequestRepository)(nil).ListPendingByApprover), ctx, approverRoleIds, excludeUserId)
+}
+
+// Transition mocks base method.
+func (m *MockAccessRequestRepository) Transition(ctx context.Context, request *model.AccessRequest, status, actorId string, decidedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, request, status, actorId, decidedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockAccessRequestRepositoryMockRecorder) Transition(ctx, request, status, actorId, decidedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockAccessRequestRepository)(nil).Transition), ctx, request, status, actorId, decidedAt)
+}
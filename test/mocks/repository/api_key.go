@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/api_key.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApiKeyRepository is a mock of ApiKeyRepository interface.
+type MockApiKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyRepositoryMockRecorder
+}
+
+// MockApiKeyRepositoryMockRecorder is the mock recorder for MockApiKeyRepository.
+type MockApiKeyRepositoryMockRecorder struct {
+	mock *MockApiKeyRepository
+}
+
+// NewMockApiKeyRepository creates a new mock instance.
+func NewMockApiKeyRepository(ctrl *gomock.Controller) *MockApiKeyRepository {
+	mock := &MockApiKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockApiKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyRepository) EXPECT() *MockApiKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockApiKeyRepository) Create(ctx context.Context, key *model.ApiKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockApiKeyRepositoryMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApiKeyRepository)(nil).Create), ctx, key)
+}
+
+// GetApiPermissions mocks base method.
+func (m *MockApiKeyRepository) GetApiPermissions(ctx context.Context, ids []uint) ([]model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiPermissions", ctx, ids)
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiPermissions indicates an expected call of GetApiPermissions.
+func (mr *MockApiKeyRepositoryMockRecorder) GetApiPermissions(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiPermissions", reflect.TypeOf((*MockApiKeyRepository)(nil).GetApiPermissions), ctx, ids)
+}
+
+// GetByHash mocks base method.
+func (m *MockApiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockApiKeyRepositoryMockRecorder) GetByHash(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockApiKeyRepository)(nil).GetByHash), ctx, keyHash)
+}
+
+// GetByKeyId mocks base method.
+func (m *MockApiKeyRepository) GetByKeyId(ctx context.Context, keyId string) (*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByKeyId", ctx, keyId)
+	ret0, _ := ret[0].(*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByKeyId indicates an expected call of GetByKeyId.
+func (mr *MockApiKeyRepositoryMockRecorder) GetByKeyId(ctx, keyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByKeyId", reflect.TypeOf((*MockApiKeyRepository)(nil).GetByKeyId), ctx, keyId)
+}
+
+// ListByUser mocks base method.
+func (m *MockApiKeyRepository) ListByUser(ctx context.Context, userId string) ([]*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userId)
+	ret0, _ := ret[0].([]*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockApiKeyRepositoryMockRecorder) ListByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockApiKeyRepository)(nil).ListByUser), ctx, userId)
+}
+
+// Revoke mocks base method.
+func (m *MockApiKeyRepository) Revoke(ctx context.Context, keyId string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, keyId, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockApiKeyRepositoryMockRecorder) Revoke(ctx, keyId, revokedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockApiKeyRepository)(nil).Revoke), ctx, keyId, revokedAt)
+}
+
+// Touch mocks base method.
+func (m *MockApiKeyRepository) Touch(ctx context.Context, keyId string, usedAt time.Time, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, keyId, usedAt, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockApiKeyRepositoryMockRecorder) Touch(ctx, keyId, usedAt, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockApiKeyRepository)(nil).Touch), ctx, keyId, usedAt, ip)
+}
@@ -0,0 +1,197 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/dept.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeptRepository is a mock of DeptRepository interface.
+type MockDeptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeptRepositoryMockRecorder
+}
+
+// MockDeptRepositoryMockRecorder is the mock recorder for MockDeptRepository.
+type MockDeptRepositoryMockRecorder struct {
+	mock *MockDeptRepository
+}
+
+// NewMockDeptRepository creates a new mock instance.
+func NewMockDeptRepository(ctrl *gomock.Controller) *MockDeptRepository {
+	mock := &MockDeptRepository{ctrl: ctrl}
+	mock.recorder = &MockDeptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeptRepository) EXPECT() *MockDeptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountChildren mocks base method.
+func (m *MockDeptRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountChildren", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountChildren indicates an expected call of CountChildren.
+func (mr *MockDeptRepositoryMockRecorder) CountChildren(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountChildren", reflect.TypeOf((*MockDeptRepository)(nil).CountChildren), ctx, id)
+}
+
+// CountUsers mocks base method.
+func (m *MockDeptRepository) CountUsers(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockDeptRepositoryMockRecorder) CountUsers(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockDeptRepository)(nil).CountUsers), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockDeptRepository) Create(ctx context.Context, dept *model.Dept) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, dept)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeptRepositoryMockRecorder) Create(ctx, dept interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeptRepository)(nil).Create), ctx, dept)
+}
+
+// Delete mocks base method.
+func (m *MockDeptRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeptRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeptRepository)(nil).Delete), ctx, id)
+}
+
+// GetById mocks base method.
+func (m *MockDeptRepository) GetById(ctx context.Context, id uint) (*model.Dept, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.Dept)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockDeptRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockDeptRepository)(nil).GetById), ctx, id)
+}
+
+// GetParents mocks base method.
+func (m *MockDeptRepository) GetParents(ctx context.Context) (map[uint]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParents", ctx)
+	ret0, _ := ret[0].(map[uint]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParents indicates an expected call of GetParents.
+func (mr *MockDeptRepositoryMockRecorder) GetParents(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParents", reflect.TypeOf((*MockDeptRepository)(nil).GetParents), ctx)
+}
+
+// List mocks base method.
+func (m *MockDeptRepository) List(ctx context.Context) ([]model.Dept, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.Dept)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeptRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeptRepository)(nil).List), ctx)
+}
+
+// ListUsers mocks base method.
+func (m *MockDeptRepository) ListUsers(ctx context.Context, deptIds []uint, offset, limit int) ([]model.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, deptIds, offset, limit)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockDeptRepositoryMockRecorder) ListUsers(ctx, deptIds, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockDeptRepository)(nil).ListUsers), ctx, deptIds, offset, limit)
+}
+
+// RemoveUsers mocks base method.
+func (m *MockDeptRepository) RemoveUsers(ctx context.Context, deptId uint, userIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUsers", ctx, deptId, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUsers indicates an expected call of RemoveUsers.
+func (mr *MockDeptRepositoryMockRecorder) RemoveUsers(ctx, deptId, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUsers", reflect.TypeOf((*MockDeptRepository)(nil).RemoveUsers), ctx, deptId, userIds)
+}
+
+// SetUsersDept mocks base method.
+func (m *MockDeptRepository) SetUsersDept(ctx context.Context, userIds []string, deptId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUsersDept", ctx, userIds, deptId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUsersDept indicates an expected call of SetUsersDept.
+func (mr *MockDeptRepositoryMockRecorder) SetUsersDept(ctx, userIds, deptId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUsersDept", reflect.TypeOf((*MockDeptRepository)(nil).SetUsersDept), ctx, userIds, deptId)
+}
+
+// Update mocks base method.
+func (m *MockDeptRepository) Update(ctx context.Context, dept *model.Dept) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, dept)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeptRepositoryMockRecorder) Update(ctx, dept interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeptRepository)(nil).Update), ctx, dept)
+}
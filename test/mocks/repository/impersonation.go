@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/impersonation.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockImpersonationRepository is a mock of ImpersonationRepository interface.
+type MockImpersonationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonationRepositoryMockRecorder
+}
+
+// MockImpersonationRepositoryMockRecorder is the mock recorder for MockImpersonationRepository.
+type MockImpersonationRepositoryMockRecorder struct {
+	mock *MockImpersonationRepository
+}
+
+// NewMockImpersonationRepository creates a new mock instance.
+func NewMockImpersonationRepository(ctrl *gomock.Controller) *MockImpersonationRepository {
+	mock := &MockImpersonationRepository{ctrl: ctrl}
+	mock.recorder = &MockImpersonationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImpersonationRepository) EXPECT() *MockImpersonationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockImpersonationRepository) Create(ctx context.Context, impersonation *model.Impersonation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, impersonation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockImpersonationRepositoryMockRecorder) Create(ctx, impersonation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImpersonationRepository)(nil).Create), ctx, impersonation)
+}
+
+// End mocks base method.
+func (m *MockImpersonationRepository) End(ctx context.Context, jti string, endedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "End", ctx, jti, endedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// End indicates an expected call of End.
+func (mr *MockImpersonationRepositoryMockRecorder) End(ctx, jti, endedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "End", reflect.TypeOf((*MockImpersonationRepository)(nil).End), ctx, jti, endedAt)
+}
+
+// List mocks base method.
+func (m *MockImpersonationRepository) List(ctx context.Context, userId string) ([]model.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId)
+	ret0, _ := ret[0].([]model.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockImpersonationRepositoryMockRecorder) List(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockImpersonationRepository)(nil).List), ctx, userId)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/oidc.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOIDCRepository is a mock of OIDCRepository interface.
+type MockOIDCRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCRepositoryMockRecorder
+}
+
+// MockOIDCRepositoryMockRecorder is the mock recorder for MockOIDCRepository.
+type MockOIDCRepositoryMockRecorder struct {
+	mock *MockOIDCRepository
+}
+
+// NewMockOIDCRepository creates a new mock instance.
+func NewMockOIDCRepository(ctrl *gomock.Controller) *MockOIDCRepository {
+	mock := &MockOIDCRepository{ctrl: ctrl}
+	mock.recorder = &MockOIDCRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCRepository) EXPECT() *MockOIDCRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateIdentity mocks base method.
+func (m *MockOIDCRepository) CreateIdentity(ctx context.Context, identity *model.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockOIDCRepositoryMockRecorder) CreateIdentity(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockOIDCRepository)(nil).CreateIdentity), ctx, identity)
+}
+
+// CreateState mocks base method.
+func (m *MockOIDCRepository) CreateState(ctx context.Context, state *model.OIDCState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateState indicates an expected call of CreateState.
+func (mr *MockOIDCRepositoryMockRecorder) CreateState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateState", reflect.TypeOf((*MockOIDCRepository)(nil).CreateState), ctx, state)
+}
+
+// GetIdentity mocks base method.
+func (m *MockOIDCRepository) GetIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*model.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentity indicates an expected call of GetIdentity.
+func (mr *MockOIDCRepositoryMockRecorder) GetIdentity(ctx, provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentity", reflect.TypeOf((*MockOIDCRepository)(nil).GetIdentity), ctx, provider, subject)
+}
+
+// SyncRoles mocks base method.
+func (m *MockOIDCRepository) SyncRoles(ctx context.Context, userId string, managed, granted []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncRoles", ctx, userId, managed, granted)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncRoles indicates an expected call of SyncRoles.
+func (mr *MockOIDCRepositoryMockRecorder) SyncRoles(ctx, userId, managed, granted interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncRoles", reflect.TypeOf((*MockOIDCRepository)(nil).SyncRoles), ctx, userId, managed, granted)
+}
+
+// TakeState mocks base method.
+func (m *MockOIDCRepository) TakeState(ctx context.Context, state string) (*model.OIDCState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeState", ctx, state)
+	ret0, _ := ret[0].(*model.OIDCState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeState indicates an expected call of TakeState.
+func (mr *MockOIDCRepositoryMockRecorder) TakeState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeState", reflect.TypeOf((*MockOIDCRepository)(nil).TakeState), ctx, state)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/password_history.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPasswordHistoryRepository is a mock of PasswordHistoryRepository interface.
+type MockPasswordHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordHistoryRepositoryMockRecorder
+}
+
+// MockPasswordHistoryRepositoryMockRecorder is the mock recorder for MockPasswordHistoryRepository.
+type MockPasswordHistoryRepositoryMockRecorder struct {
+	mock *MockPasswordHistoryRepository
+}
+
+// NewMockPasswordHistoryRepository creates a new mock instance.
+func NewMockPasswordHistoryRepository(ctrl *gomock.Controller) *MockPasswordHistoryRepository {
+	mock := &MockPasswordHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockPasswordHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordHistoryRepository) EXPECT() *MockPasswordHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPasswordHistoryRepository) Create(ctx context.Context, history *model.PasswordHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasswordHistoryRepositoryMockRecorder) Create(ctx, history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasswordHistoryRepository)(nil).Create), ctx, history)
+}
+
+// ListRecent mocks base method.
+func (m *MockPasswordHistoryRepository) ListRecent(ctx context.Context, userId string, limit int) ([]*model.PasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecent", ctx, userId, limit)
+	ret0, _ := ret[0].([]*model.PasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecent indicates an expected call of ListRecent.
+func (mr *MockPasswordHistoryRepositoryMockRecorder) ListRecent(ctx, userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecent", reflect.TypeOf((*MockPasswordHistoryRepository)(nil).ListRecent), ctx, userId, limit)
+}
+
+// Prune mocks base method.
+func (m *MockPasswordHistoryRepository) Prune(ctx context.Context, userId string, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx, userId, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockPasswordHistoryRepositoryMockRecorder) Prune(ctx, userId, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockPasswordHistoryRepository)(nil).Prune), ctx, userId, keep)
+}
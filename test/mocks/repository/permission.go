@@ -0,0 +1,165 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/permission.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPermissionRepository is a mock of PermissionRepository interface.
+type MockPermissionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionRepositoryMockRecorder
+}
+
+// MockPermissionRepositoryMockRecorder is the mock recorder for MockPermissionRepository.
+type MockPermissionRepositoryMockRecorder struct {
+	mock *MockPermissionRepository
+}
+
+// NewMockPermissionRepository creates a new mock instance.
+func NewMockPermissionRepository(ctrl *gomock.Controller) *MockPermissionRepository {
+	mock := &MockPermissionRepository{ctrl: ctrl}
+	mock.recorder = &MockPermissionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionRepository) EXPECT() *MockPermissionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountChildren mocks base method.
+func (m *MockPermissionRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountChildren", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountChildren indicates an expected call of CountChildren.
+func (mr *MockPermissionRepositoryMockRecorder) CountChildren(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountChildren", reflect.TypeOf((*MockPermissionRepository)(nil).CountChildren), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockPermissionRepository) Create(ctx context.Context, permission *model.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPermissionRepositoryMockRecorder) Create(ctx, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPermissionRepository)(nil).Create), ctx, permission)
+}
+
+// Delete mocks base method.
+func (m *MockPermissionRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPermissionRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPermissionRepository)(nil).Delete), ctx, id)
+}
+
+// GetById mocks base method.
+func (m *MockPermissionRepository) GetById(ctx context.Context, id uint) (*model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockPermissionRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockPermissionRepository)(nil).GetById), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockPermissionRepository) List(ctx context.Context) ([]model.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPermissionRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPermissionRepository)(nil).List), ctx)
+}
+
+// Move mocks base method.
+func (m *MockPermissionRepository) Move(ctx context.Context, id, parentId uint, sort string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, id, parentId, sort)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockPermissionRepositoryMockRecorder) Move(ctx, id, parentId, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockPermissionRepository)(nil).Move), ctx, id, parentId, sort)
+}
+
+// ShiftLevel mocks base method.
+func (m *MockPermissionRepository) ShiftLevel(ctx context.Context, ids []uint, delta int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShiftLevel", ctx, ids, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ShiftLevel indicates an expected call of ShiftLevel.
+func (mr *MockPermissionRepositoryMockRecorder) ShiftLevel(ctx, ids, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShiftLevel", reflect.TypeOf((*MockPermissionRepository)(nil).ShiftLevel), ctx, ids, delta)
+}
+
+// Update mocks base method.
+func (m *MockPermissionRepository) Update(ctx context.Context, permission *model.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPermissionRepositoryMockRecorder) Update(ctx, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPermissionRepository)(nil).Update), ctx, permission)
+}
+
+// UpdateSort mocks base method.
+func (m *MockPermissionRepository) UpdateSort(ctx context.Context, id uint, sort string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSort", ctx, id, sort)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSort indicates an expected call of UpdateSort.
+func (mr *MockPermissionRepositoryMockRecorder) UpdateSort(ctx, id, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSort", reflect.TypeOf((*MockPermissionRepository)(nil).UpdateSort), ctx, id, sort)
+}
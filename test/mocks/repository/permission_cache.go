@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/permission_cache.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	repository "admin-webrtc-go/internal/repository"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPermissionCacheRepository is a mock of PermissionCacheRepository interface.
+type MockPermissionCacheRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionCacheRepositoryMockRecorder
+}
+
+// MockPermissionCacheRepositoryMockRecorder is the mock recorder for MockPermissionCacheRepository.
+type MockPermissionCacheRepositoryMockRecorder struct {
+	mock *MockPermissionCacheRepository
+}
+
+// NewMockPermissionCacheRepository creates a new mock instance.
+func NewMockPermissionCacheRepository(ctrl *gomock.Controller) *MockPermissionCacheRepository {
+	mock := &MockPermissionCacheRepository{ctrl: ctrl}
+	mock.recorder = &MockPermissionCacheRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionCacheRepository) EXPECT() *MockPermissionCacheRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Invalidate mocks base method.
+func (m *MockPermissionCacheRepository) Invalidate(ctx context.Context, userIds ...string) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range userIds {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Invalidate", varargs...)
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockPermissionCacheRepositoryMockRecorder) Invalidate(ctx interface{}, userIds ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, userIds...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockPermissionCacheRepository)(nil).Invalidate), varargs...)
+}
+
+// InvalidateAll mocks base method.
+func (m *MockPermissionCacheRepository) InvalidateAll(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateAll", ctx)
+}
+
+// InvalidateAll indicates an expected call of InvalidateAll.
+func (mr *MockPermissionCacheRepositoryMockRecorder) InvalidateAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateAll", reflect.TypeOf((*MockPermissionCacheRepository)(nil).InvalidateAll), ctx)
+}
+
+// Load mocks base method.
+func (m *MockPermissionCacheRepository) Load(ctx context.Context, userId string, load repository.PermissionLoader) ([]repository.ApiPermission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, userId, load)
+	ret0, _ := ret[0].([]repository.ApiPermission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockPermissionCacheRepositoryMockRecorder) Load(ctx, userId, load interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockPermissionCacheRepository)(nil).Load), ctx, userId, load)
+}
+
+// Stats mocks base method.
+func (m *MockPermissionCacheRepository) Stats() repository.PermissionCacheStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(repository.PermissionCacheStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockPermissionCacheRepositoryMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockPermissionCacheRepository)(nil).Stats))
+}
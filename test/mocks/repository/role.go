@@ -0,0 +1,330 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/role.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	repository "admin-webrtc-go/internal/repository"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddUserRoles mocks base method.
+func (m *MockRoleRepository) AddUserRoles(ctx context.Context, userIds []string, roleIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserRoles", ctx, userIds, roleIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserRoles indicates an expected call of AddUserRoles.
+func (mr *MockRoleRepositoryMockRecorder) AddUserRoles(ctx, userIds, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserRoles", reflect.TypeOf((*MockRoleRepository)(nil).AddUserRoles), ctx, userIds, roleIds)
+}
+
+// AttachPermissions mocks base method.
+func (m *MockRoleRepository) AttachPermissions(ctx context.Context, roleId uint, permissionIds []uint, effect string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachPermissions", ctx, roleId, permissionIds, effect)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachPermissions indicates an expected call of AttachPermissions.
+func (mr *MockRoleRepositoryMockRecorder) AttachPermissions(ctx, roleId, permissionIds, effect interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPermissions", reflect.TypeOf((*MockRoleRepository)(nil).AttachPermissions), ctx, roleId, permissionIds, effect)
+}
+
+// ClearUserRoles mocks base method.
+func (m *MockRoleRepository) ClearUserRoles(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearUserRoles", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearUserRoles indicates an expected call of ClearUserRoles.
+func (mr *MockRoleRepositoryMockRecorder) ClearUserRoles(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearUserRoles", reflect.TypeOf((*MockRoleRepository)(nil).ClearUserRoles), ctx, userId)
+}
+
+// Create mocks base method.
+func (m *MockRoleRepository) Create(ctx context.Context, role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleRepositoryMockRecorder) Create(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleRepository)(nil).Create), ctx, role)
+}
+
+// Delete mocks base method.
+func (m *MockRoleRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoleRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoleRepository)(nil).Delete), ctx, id)
+}
+
+// DetachPermissions mocks base method.
+func (m *MockRoleRepository) DetachPermissions(ctx context.Context, roleId uint, permissionIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPermissions", ctx, roleId, permissionIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachPermissions indicates an expected call of DetachPermissions.
+func (mr *MockRoleRepositoryMockRecorder) DetachPermissions(ctx, roleId, permissionIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPermissions", reflect.TypeOf((*MockRoleRepository)(nil).DetachPermissions), ctx, roleId, permissionIds)
+}
+
+// GetApiPermissions mocks base method.
+func (m *MockRoleRepository) GetApiPermissions(ctx context.Context, roleIds []uint) ([]repository.RoleApiPermission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiPermissions", ctx, roleIds)
+	ret0, _ := ret[0].([]repository.RoleApiPermission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiPermissions indicates an expected call of GetApiPermissions.
+func (mr *MockRoleRepositoryMockRecorder) GetApiPermissions(ctx, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiPermissions", reflect.TypeOf((*MockRoleRepository)(nil).GetApiPermissions), ctx, roleIds)
+}
+
+// GetAssignedRoleIds mocks base method.
+func (m *MockRoleRepository) GetAssignedRoleIds(ctx context.Context, userId string) ([]uint, []uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignedRoleIds", ctx, userId)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].([]uint)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAssignedRoleIds indicates an expected call of GetAssignedRoleIds.
+func (mr *MockRoleRepositoryMockRecorder) GetAssignedRoleIds(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignedRoleIds", reflect.TypeOf((*MockRoleRepository)(nil).GetAssignedRoleIds), ctx, userId)
+}
+
+// GetById mocks base method.
+func (m *MockRoleRepository) GetById(ctx context.Context, id uint, withDeleted bool) (*model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id, withDeleted)
+	ret0, _ := ret[0].(*model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRoleRepositoryMockRecorder) GetById(ctx, id, withDeleted interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRoleRepository)(nil).GetById), ctx, id, withDeleted)
+}
+
+// GetByIds mocks base method.
+func (m *MockRoleRepository) GetByIds(ctx context.Context, ids []uint) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIds", ctx, ids)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIds indicates an expected call of GetByIds.
+func (mr *MockRoleRepositoryMockRecorder) GetByIds(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockRoleRepository)(nil).GetByIds), ctx, ids)
+}
+
+// GetDataScopeDepts mocks base method.
+func (m *MockRoleRepository) GetDataScopeDepts(ctx context.Context, roleId uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataScopeDepts", ctx, roleId)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataScopeDepts indicates an expected call of GetDataScopeDepts.
+func (mr *MockRoleRepositoryMockRecorder) GetDataScopeDepts(ctx, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataScopeDepts", reflect.TypeOf((*MockRoleRepository)(nil).GetDataScopeDepts), ctx, roleId)
+}
+
+// GetParents mocks base method.
+func (m *MockRoleRepository) GetParents(ctx context.Context) (map[uint]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParents", ctx)
+	ret0, _ := ret[0].(map[uint]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParents indicates an expected call of GetParents.
+func (mr *MockRoleRepositoryMockRecorder) GetParents(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParents", reflect.TypeOf((*MockRoleRepository)(nil).GetParents), ctx)
+}
+
+// GetPermissions mocks base method.
+func (m *MockRoleRepository) GetPermissions(ctx context.Context, roleId uint) ([]repository.RolePermissionDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions", ctx, roleId)
+	ret0, _ := ret[0].([]repository.RolePermissionDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockRoleRepositoryMockRecorder) GetPermissions(ctx, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockRoleRepository)(nil).GetPermissions), ctx, roleId)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockRoleRepository) GetUserRoles(ctx context.Context, userId string) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", ctx, userId)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockRoleRepositoryMockRecorder) GetUserRoles(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockRoleRepository)(nil).GetUserRoles), ctx, userId)
+}
+
+// List mocks base method.
+func (m *MockRoleRepository) List(ctx context.Context, keyword string, deleted bool, offset, limit int) ([]model.Role, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, keyword, deleted, offset, limit)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoleRepositoryMockRecorder) List(ctx, keyword, deleted, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoleRepository)(nil).List), ctx, keyword, deleted, offset, limit)
+}
+
+// ListUsers mocks base method.
+func (m *MockRoleRepository) ListUsers(ctx context.Context, roleId uint, offset, limit int) ([]model.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, roleId, offset, limit)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockRoleRepositoryMockRecorder) ListUsers(ctx, roleId, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockRoleRepository)(nil).ListUsers), ctx, roleId, offset, limit)
+}
+
+// RemoveUserRoles mocks base method.
+func (m *MockRoleRepository) RemoveUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserRoles", ctx, userId, roleIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUserRoles indicates an expected call of RemoveUserRoles.
+func (mr *MockRoleRepositoryMockRecorder) RemoveUserRoles(ctx, userId, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserRoles", reflect.TypeOf((*MockRoleRepository)(nil).RemoveUserRoles), ctx, userId, roleIds)
+}
+
+// Restore mocks base method.
+func (m *MockRoleRepository) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRoleRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRoleRepository)(nil).Restore), ctx, id)
+}
+
+// SetDataScopeDepts mocks base method.
+func (m *MockRoleRepository) SetDataScopeDepts(ctx context.Context, roleId uint, deptIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDataScopeDepts", ctx, roleId, deptIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDataScopeDepts indicates an expected call of SetDataScopeDepts.
+func (mr *MockRoleRepositoryMockRecorder) SetDataScopeDepts(ctx, roleId, deptIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDataScopeDepts", reflect.TypeOf((*MockRoleRepository)(nil).SetDataScopeDepts), ctx, roleId, deptIds)
+}
+
+// Update mocks base method.
+func (m *MockRoleRepository) Update(ctx context.Context, role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoleRepositoryMockRecorder) Update(ctx, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoleRepository)(nil).Update), ctx, role)
+}
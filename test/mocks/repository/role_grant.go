@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/role_grant.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoleGrantRepository is a mock of RoleGrantRepository interface.
+type MockRoleGrantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleGrantRepositoryMockRecorder
+}
+
+// MockRoleGrantRepositoryMockRecorder is the mock recorder for MockRoleGrantRepository.
+type MockRoleGrantRepositoryMockRecorder struct {
+	mock *MockRoleGrantRepository
+}
+
+// NewMockRoleGrantRepository creates a new mock instance.
+func NewMockRoleGrantRepository(ctrl *gomock.Controller) *MockRoleGrantRepository {
+	mock := &MockRoleGrantRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleGrantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleGrantRepository) EXPECT() *MockRoleGrantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRoleGrantRepository) Create(ctx context.Context, grant *model.RoleGrant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleGrantRepositoryMockRecorder) Create(ctx, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleGrantRepository)(nil).Create), ctx, grant)
+}
+
+// EndExpired mocks base method.
+func (m *MockRoleGrantRepository) EndExpired(ctx context.Context, now time.Time) ([]model.RoleGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndExpired", ctx, now)
+	ret0, _ := ret[0].([]model.RoleGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndExpired indicates an expected call of EndExpired.
+func (mr *MockRoleGrantRepositoryMockRecorder) EndExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndExpired", reflect.TypeOf((*MockRoleGrantRepository)(nil).EndExpired), ctx, now)
+}
+
+// GetById mocks base method.
+func (m *MockRoleGrantRepository) GetById(ctx context.Context, id uint) (*model.RoleGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.RoleGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRoleGrantRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRoleGrantRepository)(nil).GetById), ctx, id)
+}
+
+// ListByUser mocks base method.
+func (m *MockRoleGrantRepository) ListByUser(ctx context.Context, userId string, ended bool) ([]model.RoleGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userId, ended)
+	ret0, _ := ret[0].([]model.RoleGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockRoleGrantRepositoryMockRecorder) ListByUser(ctx, userId, ended interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockRoleGrantRepository)(nil).ListByUser), ctx, userId, ended)
+}
+
+// Revoke mocks base method.
+func (m *MockRoleGrantRepository) Revoke(ctx context.Context, id uint, revokedBy string, endedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, revokedBy, endedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRoleGrantRepositoryMockRecorder) Revoke(ctx, id, revokedBy, endedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoleGrantRepository)(nil).Revoke), ctx, id, revokedBy, endedAt)
+}
@@ -5,10 +5,12 @@
 package mock_repository
 
 import (
+	model "admin-webrtc-go/internal/model"
+	repository "admin-webrtc-go/internal/repository"
 	context "context"
 	reflect "reflect"
+	time "time"
 
-	model "admin-webrtc-go/internal/model"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -79,6 +81,95 @@ func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
 }
 
+// GetByIDs mocks base method.
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []string) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockUserRepositoryMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUserRepository)(nil).GetByIDs), ctx, ids)
+}
+
+// GetRolesByLabels mocks base method.
+func (m *MockUserRepository) GetRolesByLabels(ctx context.Context, labels []string) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesByLabels", ctx, labels)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolesByLabels indicates an expected call of GetRolesByLabels.
+func (mr *MockUserRepositoryMockRecorder) GetRolesByLabels(ctx, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesByLabels", reflect.TypeOf((*MockUserRepository)(nil).GetRolesByLabels), ctx, labels)
+}
+
+// GetUserDefaultSeed mocks base method.
+func (m *MockUserRepository) GetUserDefaultSeed(ctx context.Context, user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDefaultSeed", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetUserDefaultSeed indicates an expected call of GetUserDefaultSeed.
+func (mr *MockUserRepositoryMockRecorder) GetUserDefaultSeed(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDefaultSeed", reflect.TypeOf((*MockUserRepository)(nil).GetUserDefaultSeed), ctx, user)
+}
+
+// GetUserRoleIds mocks base method.
+func (m *MockUserRepository) GetUserRoleIds(ctx context.Context, userId string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoleIds", ctx, userId)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoleIds indicates an expected call of GetUserRoleIds.
+func (mr *MockUserRepositoryMockRecorder) GetUserRoleIds(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoleIds", reflect.TypeOf((*MockUserRepository)(nil).GetUserRoleIds), ctx, userId)
+}
+
+// GetUserWithRolesAndPermission mocks base method.
+func (m *MockUserRepository) GetUserWithRolesAndPermission(ctx context.Context, userId, permissionType, sort string) (*[]repository.LoginedUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWithRolesAndPermission", ctx, userId, permissionType, sort)
+	ret0, _ := ret[0].(*[]repository.LoginedUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWithRolesAndPermission indicates an expected call of GetUserWithRolesAndPermission.
+func (mr *MockUserRepositoryMockRecorder) GetUserWithRolesAndPermission(ctx, userId, permissionType, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWithRolesAndPermission", reflect.TypeOf((*MockUserRepository)(nil).GetUserWithRolesAndPermission), ctx, userId, permissionType, sort)
+}
+
+// NextRoleGrantChange mocks base method.
+func (m *MockUserRepository) NextRoleGrantChange(ctx context.Context, userId string, now time.Time) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextRoleGrantChange", ctx, userId, now)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextRoleGrantChange indicates an expected call of NextRoleGrantChange.
+func (mr *MockUserRepositoryMockRecorder) NextRoleGrantChange(ctx, userId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextRoleGrantChange", reflect.TypeOf((*MockUserRepository)(nil).NextRoleGrantChange), ctx, userId, now)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(ctx context.Context, user *model.User) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/access_request.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccessRequestService is a mock of AccessRequestService interface.
+type MockAccessRequestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessRequestServiceMockRecorder
+}
+
+// MockAccessRequestServiceMockRecorder is the mock recorder for MockAccessRequestService.
+type MockAccessRequestServiceMockRecorder struct {
+	mock *MockAccessRequestService
+}
+
+// NewMockAccessRequestService creates a new mock instance.
+func NewMockAccessRequestService(ctrl *gomock.Controller) *MockAccessRequestService {
+	mock := &MockAccessRequestService{ctrl: ctrl}
+	mock.recorder = &MockAccessRequestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessRequestService) EXPECT() *MockAccessRequestServiceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockAccessRequestService) Approve(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, approverId, id, comment)
+	ret0, _ := ret[0].(*v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockAccessRequestServiceMockRecorder) Approve(ctx, approverId, id, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockAccessRequestService)(nil).Approve), ctx, approverId, id, comment)
+}
+
+// Cancel mocks base method.
+func (m *MockAccessRequestService) Cancel(ctx context.Context, userId string, id uint) (*v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, userId, id)
+	ret0, _ := ret[0].(*v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAccessRequestServiceMockRecorder) Cancel(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAccessRequestService)(nil).Cancel), ctx, userId, id)
+}
+
+// Create mocks base method.
+func (m *MockAccessRequestService) Create(ctx context.Context, userId string, req *v1.CreateAccessRequestRequest) (*v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, req)
+	ret0, _ := ret[0].(*v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessRequestServiceMockRecorder) Create(ctx, userId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessRequestService)(nil).Create), ctx, userId, req)
+}
+
+// Get mocks base method.
+func (m *MockAccessRequestService) Get(ctx context.Context, userId string, id uint) (*v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userId, id)
+	ret0, _ := ret[0].(*v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAccessRequestServiceMockRecorder) Get(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccessRequestService)(nil).Get), ctx, userId, id)
+}
+
+// List mocks base method.
+func (m *MockAccessRequestService) List(ctx context.Context, req *v1.ListAccessRequestsRequest) (*v1.ListAccessRequestsResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*v1.ListAccessRequestsResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessRequestServiceMockRecorder) List(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessRequestService)(nil).List), ctx, req)
+}
+
+// ListMine mocks base method.
+func (m *MockAccessRequestService) ListMine(ctx context.Context, userId string) ([]v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMine", ctx, userId)
+	ret0, _ := ret[0].([]v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMine indicates an expected call of ListMine.
+func (mr *MockAccessRequestServiceMockRecorder) ListMine(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMine", reflect.TypeOf((*MockAccessRequestService)(nil).ListMine), ctx, userId)
+}
+
+// ListPending mocks base method.
+func (m *MockAccessRequestService) ListPending(ctx context.Context, approverId string) ([]v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, approverId)
+	ret0, _ := ret[0].([]v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockAccessRequestServiceMockRecorder) ListPending(ctx, approverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockAccessRequestService)(nil).ListPending), ctx, approverId)
+}
+
+// Reject mocks base method.
+func (m *MockAccessRequestService) Reject(ctx context.Context, approverId string, id uint, comment string) (*v1.AccessRequestData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, approverId, id, comment)
+	ret0, _ := ret[0].(*v1.AccessRequestData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockAccessRequestServiceMockRecorder) Reject(ctx, approverId, id, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockAccessRequestService)(nil).Reject), ctx, approverId, id, comment)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/api_key.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	model "admin-webrtc-go/internal/model"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApiKeyService is a mock of ApiKeyService interface.
+type MockApiKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyServiceMockRecorder
+}
+
+// MockApiKeyServiceMockRecorder is the mock recorder for MockApiKeyService.
+type MockApiKeyServiceMockRecorder struct {
+	mock *MockApiKeyService
+}
+
+// NewMockApiKeyService creates a new mock instance.
+func NewMockApiKeyService(ctrl *gomock.Controller) *MockApiKeyService {
+	mock := &MockApiKeyService{ctrl: ctrl}
+	mock.recorder = &MockApiKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyService) EXPECT() *MockApiKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockApiKeyService) Authenticate(ctx context.Context, rawKey, ip string) (*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, rawKey, ip)
+	ret0, _ := ret[0].(*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockApiKeyServiceMockRecorder) Authenticate(ctx, rawKey, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockApiKeyService)(nil).Authenticate), ctx, rawKey, ip)
+}
+
+// Create mocks base method.
+func (m *MockApiKeyService) Create(ctx context.Context, userId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, req)
+	ret0, _ := ret[0].(*v1.CreateApiKeyData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockApiKeyServiceMockRecorder) Create(ctx, userId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApiKeyService)(nil).Create), ctx, userId, req)
+}
+
+// CreateForServiceAccount mocks base method.
+func (m *MockApiKeyService) CreateForServiceAccount(ctx context.Context, creatorId, serviceAccountId string, req *v1.CreateApiKeyRequest) (*v1.CreateApiKeyData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateForServiceAccount", ctx, creatorId, serviceAccountId, req)
+	ret0, _ := ret[0].(*v1.CreateApiKeyData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateForServiceAccount indicates an expected call of CreateForServiceAccount.
+func (mr *MockApiKeyServiceMockRecorder) CreateForServiceAccount(ctx, creatorId, serviceAccountId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateForServiceAccount", reflect.TypeOf((*MockApiKeyService)(nil).CreateForServiceAccount), ctx, creatorId, serviceAccountId, req)
+}
+
+// CreateServiceAccount mocks base method.
+func (m *MockApiKeyService) CreateServiceAccount(ctx context.Context, creatorId string, req *v1.CreateServiceAccountRequest) (*v1.ServiceAccountData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, creatorId, req)
+	ret0, _ := ret[0].(*v1.ServiceAccountData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServiceAccount indicates an expected call of CreateServiceAccount.
+func (mr *MockApiKeyServiceMockRecorder) CreateServiceAccount(ctx, creatorId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockApiKeyService)(nil).CreateServiceAccount), ctx, creatorId, req)
+}
+
+// List mocks base method.
+func (m *MockApiKeyService) List(ctx context.Context, userId string) ([]v1.ApiKeyData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId)
+	ret0, _ := ret[0].([]v1.ApiKeyData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockApiKeyServiceMockRecorder) List(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockApiKeyService)(nil).List), ctx, userId)
+}
+
+// Revoke mocks base method.
+func (m *MockApiKeyService) Revoke(ctx context.Context, userId, keyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userId, keyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockApiKeyServiceMockRecorder) Revoke(ctx, userId, keyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockApiKeyService)(nil).Revoke), ctx, userId, keyId)
+}
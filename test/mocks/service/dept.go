@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/dept.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeptService is a mock of DeptService interface.
+type MockDeptService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeptServiceMockRecorder
+}
+
+// MockDeptServiceMockRecorder is the mock recorder for MockDeptService.
+type MockDeptServiceMockRecorder struct {
+	mock *MockDeptService
+}
+
+// NewMockDeptService creates a new mock instance.
+func NewMockDeptService(ctrl *gomock.Controller) *MockDeptService {
+	mock := &MockDeptService{ctrl: ctrl}
+	mock.recorder = &MockDeptServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeptService) EXPECT() *MockDeptServiceMockRecorder {
+	return m.recorder
+}
+
+// AddUsers mocks base method.
+func (m *MockDeptService) AddUsers(ctx context.Context, id uint, userIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUsers", ctx, id, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUsers indicates an expected call of AddUsers.
+func (mr *MockDeptServiceMockRecorder) AddUsers(ctx, id, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUsers", reflect.TypeOf((*MockDeptService)(nil).AddUsers), ctx, id, userIds)
+}
+
+// Create mocks base method.
+func (m *MockDeptService) Create(ctx context.Context, req *v1.CreateDeptRequest) (*v1.DeptData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*v1.DeptData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeptServiceMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeptService)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockDeptService) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeptServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeptService)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockDeptService) Get(ctx context.Context, id uint) (*v1.DeptData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*v1.DeptData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeptServiceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeptService)(nil).Get), ctx, id)
+}
+
+// ListUsers mocks base method.
+func (m *MockDeptService) ListUsers(ctx context.Context, id uint, req *v1.ListDeptUsersRequest) (*v1.ListDeptUsersResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, id, req)
+	ret0, _ := ret[0].(*v1.ListDeptUsersResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockDeptServiceMockRecorder) ListUsers(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockDeptService)(nil).ListUsers), ctx, id, req)
+}
+
+// RemoveUsers mocks base method.
+func (m *MockDeptService) RemoveUsers(ctx context.Context, id uint, userIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUsers", ctx, id, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUsers indicates an expected call of RemoveUsers.
+func (mr *MockDeptServiceMockRecorder) RemoveUsers(ctx, id, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUsers", reflect.TypeOf((*MockDeptService)(nil).RemoveUsers), ctx, id, userIds)
+}
+
+// Tree mocks base method.
+func (m *MockDeptService) Tree(ctx context.Context) ([]*v1.DeptData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tree", ctx)
+	ret0, _ := ret[0].([]*v1.DeptData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tree indicates an expected call of Tree.
+func (mr *MockDeptServiceMockRecorder) Tree(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tree", reflect.TypeOf((*MockDeptService)(nil).Tree), ctx)
+}
+
+// Update mocks base method.
+func (m *MockDeptService) Update(ctx context.Context, id uint, req *v1.UpdateDeptRequest) (*v1.DeptData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req)
+	ret0, _ := ret[0].(*v1.DeptData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeptServiceMockRecorder) Update(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeptService)(nil).Update), ctx, id, req)
+}
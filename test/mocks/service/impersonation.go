@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/impersonation.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	service "admin-webrtc-go/internal/service"
+	jwt "admin-webrtc-go/pkg/jwt"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockImpersonationService is a mock of ImpersonationService interface.
+type MockImpersonationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonationServiceMockRecorder
+}
+
+// MockImpersonationServiceMockRecorder is the mock recorder for MockImpersonationService.
+type MockImpersonationServiceMockRecorder struct {
+	mock *MockImpersonationService
+}
+
+// NewMockImpersonationService creates a new mock instance.
+func NewMockImpersonationService(ctrl *gomock.Controller) *MockImpersonationService {
+	mock := &MockImpersonationService{ctrl: ctrl}
+	mock.recorder = &MockImpersonationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImpersonationService) EXPECT() *MockImpersonationServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockImpersonationService) List(ctx context.Context, req *v1.ListImpersonationsRequest) ([]v1.ImpersonationData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].([]v1.ImpersonationData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockImpersonationServiceMockRecorder) List(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockImpersonationService)(nil).List), ctx, req)
+}
+
+// Start mocks base method.
+func (m *MockImpersonationService) Start(ctx context.Context, actor *jwt.MyCustomClaims, targetUserId string, req *v1.ImpersonateRequest, client service.ClientInfo) (*v1.ImpersonationTokenData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, actor, targetUserId, req, client)
+	ret0, _ := ret[0].(*v1.ImpersonationTokenData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockImpersonationServiceMockRecorder) Start(ctx, actor, targetUserId, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockImpersonationService)(nil).Start), ctx, actor, targetUserId, req, client)
+}
+
+// Stop mocks base method.
+func (m *MockImpersonationService) Stop(ctx context.Context, claims *jwt.MyCustomClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockImpersonationServiceMockRecorder) Stop(ctx, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockImpersonationService)(nil).Stop), ctx, claims)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/login_attempt.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoginAttemptService is a mock of LoginAttemptService interface.
+type MockLoginAttemptService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptServiceMockRecorder
+}
+
+// MockLoginAttemptServiceMockRecorder is the mock recorder for MockLoginAttemptService.
+type MockLoginAttemptServiceMockRecorder struct {
+	mock *MockLoginAttemptService
+}
+
+// NewMockLoginAttemptService creates a new mock instance.
+func NewMockLoginAttemptService(ctrl *gomock.Controller) *MockLoginAttemptService {
+	mock := &MockLoginAttemptService{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptService) EXPECT() *MockLoginAttemptServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginAttemptService) Check(ctx context.Context, userId, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, userId, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginAttemptServiceMockRecorder) Check(ctx, userId, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginAttemptService)(nil).Check), ctx, userId, ip)
+}
+
+// Fail mocks base method.
+func (m *MockLoginAttemptService) Fail(ctx context.Context, userId, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, userId, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginAttemptServiceMockRecorder) Fail(ctx, userId, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginAttemptService)(nil).Fail), ctx, userId, ip)
+}
+
+// Succeed mocks base method.
+func (m *MockLoginAttemptService) Succeed(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeed", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeed indicates an expected call of Succeed.
+func (mr *MockLoginAttemptServiceMockRecorder) Succeed(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeed", reflect.TypeOf((*MockLoginAttemptService)(nil).Succeed), ctx, userId)
+}
+
+// Unlock mocks base method.
+func (m *MockLoginAttemptService) Unlock(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLoginAttemptServiceMockRecorder) Unlock(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLoginAttemptService)(nil).Unlock), ctx, userId)
+}
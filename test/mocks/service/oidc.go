@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/oidc.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	service "admin-webrtc-go/internal/service"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOIDCService is a mock of OIDCService interface.
+type MockOIDCService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCServiceMockRecorder
+}
+
+// MockOIDCServiceMockRecorder is the mock recorder for MockOIDCService.
+type MockOIDCServiceMockRecorder struct {
+	mock *MockOIDCService
+}
+
+// NewMockOIDCService creates a new mock instance.
+func NewMockOIDCService(ctrl *gomock.Controller) *MockOIDCService {
+	mock := &MockOIDCService{ctrl: ctrl}
+	mock.recorder = &MockOIDCServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCService) EXPECT() *MockOIDCServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthorizationURL mocks base method.
+func (m *MockOIDCService) AuthorizationURL(ctx context.Context) (*v1.OIDCAuthorizationData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationURL", ctx)
+	ret0, _ := ret[0].(*v1.OIDCAuthorizationData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizationURL indicates an expected call of AuthorizationURL.
+func (mr *MockOIDCServiceMockRecorder) AuthorizationURL(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationURL", reflect.TypeOf((*MockOIDCService)(nil).AuthorizationURL), ctx)
+}
+
+// Callback mocks base method.
+func (m *MockOIDCService) Callback(ctx context.Context, req *v1.OIDCCallbackRequest, client service.ClientInfo) (*v1.LoginResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Callback", ctx, req, client)
+	ret0, _ := ret[0].(*v1.LoginResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Callback indicates an expected call of Callback.
+func (mr *MockOIDCServiceMockRecorder) Callback(ctx, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Callback", reflect.TypeOf((*MockOIDCService)(nil).Callback), ctx, req, client)
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/permission.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	service "admin-webrtc-go/internal/service"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPermissionService is a mock of PermissionService interface.
+type MockPermissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionServiceMockRecorder
+}
+
+// MockPermissionServiceMockRecorder is the mock recorder for MockPermissionService.
+type MockPermissionServiceMockRecorder struct {
+	mock *MockPermissionService
+}
+
+// NewMockPermissionService creates a new mock instance.
+func NewMockPermissionService(ctrl *gomock.Controller) *MockPermissionService {
+	mock := &MockPermissionService{ctrl: ctrl}
+	mock.recorder = &MockPermissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionService) EXPECT() *MockPermissionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPermissionService) Create(ctx context.Context, req *v1.CreatePermissionRequest) (*v1.GetMenuTreeResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*v1.GetMenuTreeResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPermissionServiceMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPermissionService)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockPermissionService) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPermissionServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPermissionService)(nil).Delete), ctx, id)
+}
+
+// Move mocks base method.
+func (m *MockPermissionService) Move(ctx context.Context, id uint, req *v1.MovePermissionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockPermissionServiceMockRecorder) Move(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockPermissionService)(nil).Move), ctx, id, req)
+}
+
+// Sort mocks base method.
+func (m *MockPermissionService) Sort(ctx context.Context, req *v1.SortPermissionsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sort", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sort indicates an expected call of Sort.
+func (mr *MockPermissionServiceMockRecorder) Sort(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sort", reflect.TypeOf((*MockPermissionService)(nil).Sort), ctx, req)
+}
+
+// SyncRoutes mocks base method.
+func (m *MockPermissionService) SyncRoutes(ctx context.Context, routes []service.RouteInfo, names map[service.RouteInfo]string) (*service.RouteSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncRoutes", ctx, routes, names)
+	ret0, _ := ret[0].(*service.RouteSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncRoutes indicates an expected call of SyncRoutes.
+func (mr *MockPermissionServiceMockRecorder) SyncRoutes(ctx, routes, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncRoutes", reflect.TypeOf((*MockPermissionService)(nil).SyncRoutes), ctx, routes, names)
+}
+
+// Tree mocks base method.
+func (m *MockPermissionService) Tree(ctx context.Context) ([]*v1.GetMenuTreeResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tree", ctx)
+	ret0, _ := ret[0].([]*v1.GetMenuTreeResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tree indicates an expected call of Tree.
+func (mr *MockPermissionServiceMockRecorder) Tree(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tree", reflect.TypeOf((*MockPermissionService)(nil).Tree), ctx)
+}
+
+// Update mocks base method.
+func (m *MockPermissionService) Update(ctx context.Context, id uint, req *v1.UpdatePermissionRequest) (*v1.GetMenuTreeResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req)
+	ret0, _ := ret[0].(*v1.GetMenuTreeResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPermissionServiceMockRecorder) Update(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPermissionService)(nil).Update), ctx, id, req)
+}
@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/rbac.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	service "admin-webrtc-go/internal/service"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRBACService is a mock of RBACService interface.
+type MockRBACService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRBACServiceMockRecorder
+}
+
+// MockRBACServiceMockRecorder is the mock recorder for MockRBACService.
+type MockRBACServiceMockRecorder struct {
+	mock *MockRBACService
+}
+
+// NewMockRBACService creates a new mock instance.
+func NewMockRBACService(ctrl *gomock.Controller) *MockRBACService {
+	mock := &MockRBACService{ctrl: ctrl}
+	mock.recorder = &MockRBACServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRBACService) EXPECT() *MockRBACServiceMockRecorder {
+	return m.recorder
+}
+
+// CacheStats mocks base method.
+func (m *MockRBACService) CacheStats(ctx context.Context) *v1.PermissionCacheStatsData {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheStats", ctx)
+	ret0, _ := ret[0].(*v1.PermissionCacheStatsData)
+	return ret0
+}
+
+// CacheStats indicates an expected call of CacheStats.
+func (mr *MockRBACServiceMockRecorder) CacheStats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheStats", reflect.TypeOf((*MockRBACService)(nil).CacheStats), ctx)
+}
+
+// Explain mocks base method.
+func (m *MockRBACService) Explain(ctx context.Context, req *v1.ExplainPermissionRequest) (*v1.ExplainPermissionData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", ctx, req)
+	ret0, _ := ret[0].(*v1.ExplainPermissionData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockRBACServiceMockRecorder) Explain(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockRBACService)(nil).Explain), ctx, req)
+}
+
+// FlushCache mocks base method.
+func (m *MockRBACService) FlushCache(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FlushCache", ctx)
+}
+
+// FlushCache indicates an expected call of FlushCache.
+func (mr *MockRBACServiceMockRecorder) FlushCache(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushCache", reflect.TypeOf((*MockRBACService)(nil).FlushCache), ctx)
+}
+
+// SetRoutes mocks base method.
+func (m *MockRBACService) SetRoutes(routes []service.RouteInfo) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRoutes", routes)
+}
+
+// SetRoutes indicates an expected call of SetRoutes.
+func (mr *MockRBACServiceMockRecorder) SetRoutes(routes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRoutes", reflect.TypeOf((*MockRBACService)(nil).SetRoutes), routes)
+}
@@ -0,0 +1,238 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/role.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoleService is a mock of RoleService interface.
+type MockRoleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleServiceMockRecorder
+}
+
+// MockRoleServiceMockRecorder is the mock recorder for MockRoleService.
+type MockRoleServiceMockRecorder struct {
+	mock *MockRoleService
+}
+
+// NewMockRoleService creates a new mock instance.
+func NewMockRoleService(ctrl *gomock.Controller) *MockRoleService {
+	mock := &MockRoleService{ctrl: ctrl}
+	mock.recorder = &MockRoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleService) EXPECT() *MockRoleServiceMockRecorder {
+	return m.recorder
+}
+
+// AddUserRoles mocks base method.
+func (m *MockRoleService) AddUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserRoles", ctx, userId, roleIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserRoles indicates an expected call of AddUserRoles.
+func (mr *MockRoleServiceMockRecorder) AddUserRoles(ctx, userId, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserRoles", reflect.TypeOf((*MockRoleService)(nil).AddUserRoles), ctx, userId, roleIds)
+}
+
+// AssignUsers mocks base method.
+func (m *MockRoleService) AssignUsers(ctx context.Context, id uint, userIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignUsers", ctx, id, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignUsers indicates an expected call of AssignUsers.
+func (mr *MockRoleServiceMockRecorder) AssignUsers(ctx, id, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignUsers", reflect.TypeOf((*MockRoleService)(nil).AssignUsers), ctx, id, userIds)
+}
+
+// AttachPermissions mocks base method.
+func (m *MockRoleService) AttachPermissions(ctx context.Context, id uint, permissionIds []uint, effect string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachPermissions", ctx, id, permissionIds, effect)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachPermissions indicates an expected call of AttachPermissions.
+func (mr *MockRoleServiceMockRecorder) AttachPermissions(ctx, id, permissionIds, effect interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPermissions", reflect.TypeOf((*MockRoleService)(nil).AttachPermissions), ctx, id, permissionIds, effect)
+}
+
+// Create mocks base method.
+func (m *MockRoleService) Create(ctx context.Context, req *v1.CreateRoleRequest) (*v1.RoleData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*v1.RoleData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleServiceMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleService)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockRoleService) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoleServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoleService)(nil).Delete), ctx, id)
+}
+
+// DetachPermissions mocks base method.
+func (m *MockRoleService) DetachPermissions(ctx context.Context, id uint, permissionIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPermissions", ctx, id, permissionIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachPermissions indicates an expected call of DetachPermissions.
+func (mr *MockRoleServiceMockRecorder) DetachPermissions(ctx, id, permissionIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPermissions", reflect.TypeOf((*MockRoleService)(nil).DetachPermissions), ctx, id, permissionIds)
+}
+
+// Get mocks base method.
+func (m *MockRoleService) Get(ctx context.Context, id uint) (*v1.RoleData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*v1.RoleData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRoleServiceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRoleService)(nil).Get), ctx, id)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockRoleService) GetUserRoles(ctx context.Context, userId string) ([]v1.RoleData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", ctx, userId)
+	ret0, _ := ret[0].([]v1.RoleData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockRoleServiceMockRecorder) GetUserRoles(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockRoleService)(nil).GetUserRoles), ctx, userId)
+}
+
+// List mocks base method.
+func (m *MockRoleService) List(ctx context.Context, req *v1.ListRolesRequest) (*v1.ListRolesResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*v1.ListRolesResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoleServiceMockRecorder) List(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoleService)(nil).List), ctx, req)
+}
+
+// ListUsers mocks base method.
+func (m *MockRoleService) ListUsers(ctx context.Context, id uint, req *v1.ListRoleUsersRequest) (*v1.ListRoleUsersResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, id, req)
+	ret0, _ := ret[0].(*v1.ListRoleUsersResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockRoleServiceMockRecorder) ListUsers(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockRoleService)(nil).ListUsers), ctx, id, req)
+}
+
+// RemoveUserRoles mocks base method.
+func (m *MockRoleService) RemoveUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserRoles", ctx, userId, roleIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUserRoles indicates an expected call of RemoveUserRoles.
+func (mr *MockRoleServiceMockRecorder) RemoveUserRoles(ctx, userId, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserRoles", reflect.TypeOf((*MockRoleService)(nil).RemoveUserRoles), ctx, userId, roleIds)
+}
+
+// Restore mocks base method.
+func (m *MockRoleService) Restore(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRoleServiceMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRoleService)(nil).Restore), ctx, id)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockRoleService) SetUserRoles(ctx context.Context, userId string, roleIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", ctx, userId, roleIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockRoleServiceMockRecorder) SetUserRoles(ctx, userId, roleIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockRoleService)(nil).SetUserRoles), ctx, userId, roleIds)
+}
+
+// Update mocks base method.
+func (m *MockRoleService) Update(ctx context.Context, id uint, req *v1.UpdateRoleRequest) (*v1.RoleData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req)
+	ret0, _ := ret[0].(*v1.RoleData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoleServiceMockRecorder) Update(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoleService)(nil).Update), ctx, id, req)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/role_grant.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoleGrantService is a mock of RoleGrantService interface.
+type MockRoleGrantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleGrantServiceMockRecorder
+}
+
+// MockRoleGrantServiceMockRecorder is the mock recorder for MockRoleGrantService.
+type MockRoleGrantServiceMockRecorder struct {
+	mock *MockRoleGrantService
+}
+
+// NewMockRoleGrantService creates a new mock instance.
+func NewMockRoleGrantService(ctrl *gomock.Controller) *MockRoleGrantService {
+	mock := &MockRoleGrantService{ctrl: ctrl}
+	mock.recorder = &MockRoleGrantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleGrantService) EXPECT() *MockRoleGrantServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRoleGrantService) Create(ctx context.Context, actorId, userId string, req *v1.CreateRoleGrantRequest) (*v1.RoleGrantData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, actorId, userId, req)
+	ret0, _ := ret[0].(*v1.RoleGrantData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleGrantServiceMockRecorder) Create(ctx, actorId, userId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleGrantService)(nil).Create), ctx, actorId, userId, req)
+}
+
+// EndExpired mocks base method.
+func (m *MockRoleGrantService) EndExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndExpired indicates an expected call of EndExpired.
+func (mr *MockRoleGrantServiceMockRecorder) EndExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndExpired", reflect.TypeOf((*MockRoleGrantService)(nil).EndExpired), ctx)
+}
+
+// List mocks base method.
+func (m *MockRoleGrantService) List(ctx context.Context, userId string, req *v1.ListRoleGrantsRequest) ([]v1.RoleGrantData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId, req)
+	ret0, _ := ret[0].([]v1.RoleGrantData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoleGrantServiceMockRecorder) List(ctx, userId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoleGrantService)(nil).List), ctx, userId, req)
+}
+
+// Revoke mocks base method.
+func (m *MockRoleGrantService) Revoke(ctx context.Context, actorId, userId string, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, actorId, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRoleGrantServiceMockRecorder) Revoke(ctx, actorId, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoleGrantService)(nil).Revoke), ctx, actorId, userId, id)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/session.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSessionService is a mock of SessionService interface.
+type MockSessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionServiceMockRecorder
+}
+
+// MockSessionServiceMockRecorder is the mock recorder for MockSessionService.
+type MockSessionServiceMockRecorder struct {
+	mock *MockSessionService
+}
+
+// NewMockSessionService creates a new mock instance.
+func NewMockSessionService(ctrl *gomock.Controller) *MockSessionService {
+	mock := &MockSessionService{ctrl: ctrl}
+	mock.recorder = &MockSessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionService) EXPECT() *MockSessionServiceMockRecorder {
+	return m.recorder
+}
+
+// ForceRevokeSession mocks base method.
+func (m *MockSessionService) ForceRevokeSession(ctx context.Context, userId, sessionId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceRevokeSession", ctx, userId, sessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceRevokeSession indicates an expected call of ForceRevokeSession.
+func (mr *MockSessionServiceMockRecorder) ForceRevokeSession(ctx, userId, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceRevokeSession", reflect.TypeOf((*MockSessionService)(nil).ForceRevokeSession), ctx, userId, sessionId)
+}
+
+// ForceRevokeUserSessions mocks base method.
+func (m *MockSessionService) ForceRevokeUserSessions(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceRevokeUserSessions", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceRevokeUserSessions indicates an expected call of ForceRevokeUserSessions.
+func (mr *MockSessionServiceMockRecorder) ForceRevokeUserSessions(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceRevokeUserSessions", reflect.TypeOf((*MockSessionService)(nil).ForceRevokeUserSessions), ctx, userId)
+}
+
+// ListSessions mocks base method.
+func (m *MockSessionService) ListSessions(ctx context.Context, userId, currentSessionId string) ([]*v1.SessionData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userId, currentSessionId)
+	ret0, _ := ret[0].([]*v1.SessionData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockSessionServiceMockRecorder) ListSessions(ctx, userId, currentSessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockSessionService)(nil).ListSessions), ctx, userId, currentSessionId)
+}
+
+// RevokeOwnSession mocks base method.
+func (m *MockSessionService) RevokeOwnSession(ctx context.Context, userId, sessionId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOwnSession", ctx, userId, sessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOwnSession indicates an expected call of RevokeOwnSession.
+func (mr *MockSessionServiceMockRecorder) RevokeOwnSession(ctx, userId, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOwnSession", reflect.TypeOf((*MockSessionService)(nil).RevokeOwnSession), ctx, userId, sessionId)
+}
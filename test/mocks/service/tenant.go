@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/tenant.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTenantService is a mock of TenantService interface.
+type MockTenantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantServiceMockRecorder
+}
+
+// MockTenantServiceMockRecorder is the mock recorder for MockTenantService.
+type MockTenantServiceMockRecorder struct {
+	mock *MockTenantService
+}
+
+// NewMockTenantService creates a new mock instance.
+func NewMockTenantService(ctrl *gomock.Controller) *MockTenantService {
+	mock := &MockTenantService{ctrl: ctrl}
+	mock.recorder = &MockTenantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantService) EXPECT() *MockTenantServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTenantService) Create(ctx context.Context, req *v1.CreateTenantRequest) (*v1.TenantData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*v1.TenantData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTenantServiceMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTenantService)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockTenantService) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTenantServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTenantService)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockTenantService) Get(ctx context.Context, id uint) (*v1.TenantData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*v1.TenantData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTenantServiceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTenantService)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTenantService) List(ctx context.Context, req *v1.ListTenantsRequest) (*v1.ListTenantsResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req)
+	ret0, _ := ret[0].(*v1.ListTenantsResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTenantServiceMockRecorder) List(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTenantService)(nil).List), ctx, req)
+}
+
+// MoveUsers mocks base method.
+func (m *MockTenantService) MoveUsers(ctx context.Context, id uint, userIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUsers", ctx, id, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUsers indicates an expected call of MoveUsers.
+func (mr *MockTenantServiceMockRecorder) MoveUsers(ctx, id, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUsers", reflect.TypeOf((*MockTenantService)(nil).MoveUsers), ctx, id, userIds)
+}
+
+// Update mocks base method.
+func (m *MockTenantService) Update(ctx context.Context, id uint, req *v1.UpdateTenantRequest) (*v1.TenantData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req)
+	ret0, _ := ret[0].(*v1.TenantData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTenantServiceMockRecorder) Update(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTenantService)(nil).Update), ctx, id, req)
+}
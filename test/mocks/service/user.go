@@ -5,10 +5,11 @@
 package mock_service
 
 import (
+	v1 "admin-webrtc-go/api/v1"
+	service "admin-webrtc-go/internal/service"
 	context "context"
 	reflect "reflect"
 
-	v1 "admin-webrtc-go/api/v1"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,6 +36,36 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// CheckAPIAuthPermission mocks base method.
+func (m *MockUserService) CheckAPIAuthPermission(ctx context.Context, userId string, req service.ApiRequest) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAPIAuthPermission", ctx, userId, req)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAPIAuthPermission indicates an expected call of CheckAPIAuthPermission.
+func (mr *MockUserServiceMockRecorder) CheckAPIAuthPermission(ctx, userId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAPIAuthPermission", reflect.TypeOf((*MockUserService)(nil).CheckAPIAuthPermission), ctx, userId, req)
+}
+
+// GetMenuTreeByUserAuth mocks base method.
+func (m *MockUserService) GetMenuTreeByUserAuth(ctx context.Context, userId, sort string) ([]*v1.GetMenuTreeResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMenuTreeByUserAuth", ctx, userId, sort)
+	ret0, _ := ret[0].([]*v1.GetMenuTreeResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMenuTreeByUserAuth indicates an expected call of GetMenuTreeByUserAuth.
+func (mr *MockUserServiceMockRecorder) GetMenuTreeByUserAuth(ctx, userId, sort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMenuTreeByUserAuth", reflect.TypeOf((*MockUserService)(nil).GetMenuTreeByUserAuth), ctx, userId, sort)
+}
+
 // GetProfile mocks base method.
 func (m *MockUserService) GetProfile(ctx context.Context, userId string) (*v1.GetProfileResponseData, error) {
 	m.ctrl.T.Helper()
@@ -51,18 +82,48 @@ func (mr *MockUserServiceMockRecorder) GetProfile(ctx, userId interface{}) *gomo
 }
 
 // Login mocks base method.
-func (m *MockUserService) Login(ctx context.Context, req *v1.LoginRequest) (string, error) {
+func (m *MockUserService) Login(ctx context.Context, req *v1.LoginRequest, client service.ClientInfo) (*v1.LoginResponseData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, req)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", ctx, req, client)
+	ret0, _ := ret[0].(*v1.LoginResponseData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserServiceMockRecorder) Login(ctx, req interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) Login(ctx, req, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, req, client)
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockUserService) LoginTwoFactor(ctx context.Context, req *v1.TwoFactorLoginRequest, client service.ClientInfo) (*v1.LoginResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", ctx, req, client)
+	ret0, _ := ret[0].(*v1.LoginResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockUserServiceMockRecorder) LoginTwoFactor(ctx, req, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockUserService)(nil).LoginTwoFactor), ctx, req, client)
+}
+
+// LoginTwoFactorSetup mocks base method.
+func (m *MockUserService) LoginTwoFactorSetup(ctx context.Context, req *v1.TwoFactorLoginSetupRequest) (*v1.TwoFactorSetupData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactorSetup", ctx, req)
+	ret0, _ := ret[0].(*v1.TwoFactorSetupData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginTwoFactorSetup indicates an expected call of LoginTwoFactorSetup.
+func (mr *MockUserServiceMockRecorder) LoginTwoFactorSetup(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactorSetup", reflect.TypeOf((*MockUserService)(nil).LoginTwoFactorSetup), ctx, req)
 }
 
 // Register mocks base method.
@@ -79,6 +140,20 @@ func (mr *MockUserServiceMockRecorder) Register(ctx, req interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), ctx, req)
 }
 
+// UnlockLogin mocks base method.
+func (m *MockUserService) UnlockLogin(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockLogin", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockLogin indicates an expected call of UnlockLogin.
+func (mr *MockUserServiceMockRecorder) UnlockLogin(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockLogin", reflect.TypeOf((*MockUserService)(nil).UnlockLogin), ctx, userId)
+}
+
 // UpdateProfile mocks base method.
 func (m *MockUserService) UpdateProfile(ctx context.Context, userId string, req *v1.UpdateProfileRequest) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/handler"
+	"admin-webrtc-go/internal/middleware"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/test/mocks/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBACAuth_ApiKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockApiKeyService := mock_service.NewMockApiKeyService(ctrl)
+
+	key := &model.ApiKey{KeyId: "k1", UserId: userId, Scopes: []model.Permission{{Path: "report"}}}
+	mockApiKeyService.EXPECT().Authenticate(gomock.Any(), "valid", gomock.Any()).Return(key, nil).AnyTimes()
+	mockApiKeyService.EXPECT().Authenticate(gomock.Any(), "revoked", gomock.Any()).Return(nil, v1.ErrApiKeyInvalid)
+	mockUserService.EXPECT().CheckAPIAuthPermission(gomock.Any(), userId, gomock.Any()).Return(true, nil).AnyTimes()
+
+	r := gin.New()
+	r.GET("/:api/resource",
+		middleware.StrictAuth(jwt, mockTokenService, mockApiKeyService, logger),
+		middleware.RBACAuth(jwt, mockUserService, mockTokenService, mockApiKeyService, logger),
+		func(ctx *gin.Context) {
+			claims := handler.GetClaimsFromCtx(ctx)
+			assert.Equal(t, "k1", claims.ApiKeyId)
+			ctx.Status(http.StatusOK)
+		})
+	request := func(api string, rawKey string) int {
+		req, _ := http.NewRequest("GET", "/"+api+"/resource", nil)
+		req.Header.Set("X-API-Key", rawKey)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	// 用户拥有权限且在密钥权限范围内
+	assert.Equal(t, http.StatusOK, request("report", "valid"))
+	// 用户拥有权限但超出密钥权限范围
+	assert.Equal(t, http.StatusForbidden, request("deploy", "valid"))
+	// 已吊销的密钥
+	assert.Equal(t, http.StatusUnauthorized, request("report", "revoked"))
+}
+
+func TestApiKeyHandler_CreateApiKey_RejectsApiKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApiKeyService := mock_service.NewMockApiKeyService(ctrl)
+	key := &model.ApiKey{KeyId: "k1", UserId: userId}
+	mockApiKeyService.EXPECT().Authenticate(gomock.Any(), "valid", gomock.Any()).Return(key, nil)
+
+	apiKeyHandler := handler.NewApiKeyHandler(hdl, mockApiKeyService)
+	r := gin.New()
+	r.POST("/api-keys", middleware.StrictAuth(jwt, mock_service.NewMockTokenService(ctrl), mockApiKeyService, logger), apiKeyHandler.CreateApiKey)
+
+	// 不能用API密钥派生新密钥
+	req, _ := http.NewRequest("POST", "/api-keys", strings.NewReader(`{"name":"ci","scopes":[1]}`))
+	req.Header.Set("X-API-Key", "valid")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func TestDenyApiKey_UpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockApiKeyService := mock_service.NewMockApiKeyService(ctrl)
+	key := &model.ApiKey{KeyId: "k1", UserId: userId, Scopes: []model.Permission{{Path: "/v1/user", Method: "PUT"}}}
+	mockApiKeyService.EXPECT().Authenticate(gomock.Any(), "valid", gomock.Any()).Return(key, nil)
+
+	userHandler := handler.NewUserHandler(hdl, mockUserService)
+	r := gin.New()
+	r.PUT("/v1/user",
+		middleware.StrictAuth(jwt, mock_service.NewMockTokenService(ctrl), mockApiKeyService, logger),
+		middleware.DenyApiKey(logger),
+		userHandler.UpdateProfile)
+
+	// 即使密钥的权限范围包括该接口，也不能修改账号资料
+	req, _ := http.NewRequest("PUT", "/v1/user", strings.NewReader(`{"email":"attacker@example.com"}`))
+	req.Header.Set("X-API-Key", "valid")
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), v1.ErrApiKeyNotAllowed.Error())
+}
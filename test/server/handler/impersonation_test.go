@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"admin-webrtc-go/internal/middleware"
+	jwt2 "admin-webrtc-go/pkg/jwt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDenyImpersonation(t *testing.T) {
+	r := gin.New()
+	r.POST("/password/change", func(ctx *gin.Context) {
+		claims := &jwt2.MyCustomClaims{UserId: userId}
+		claims.ActorId = ctx.GetHeader("X-Actor")
+		ctx.Set("claims", claims)
+	}, middleware.DenyImpersonation(logger), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	request := func(actorId string) int {
+		req, _ := http.NewRequest("POST", "/password/change", nil)
+		req.Header.Set("X-Actor", actorId)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request(""))
+	assert.Equal(t, http.StatusForbidden, request("admin"))
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"admin-webrtc-go/internal/handler"
+	"admin-webrtc-go/internal/middleware"
+	jwt2 "admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/test/mocks/service"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newRotatedJwt 生成EdDSA当前密钥和只有公钥的RS256退役密钥，同时返回退役私钥用于模拟旧令牌
+func newRotatedJwt(t *testing.T) (*jwt2.JWT, *rsa.PrivateKey) {
+	dir := t.TempDir()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	edDer, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "new.pem"), "PRIVATE KEY", edDer)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	rsaDer, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	assert.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "old.pub.pem"), "PUBLIC KEY", rsaDer)
+
+	conf := viper.New()
+	conf.Set("security.jwt.active_kid", "new")
+	conf.Set("security.jwt.keys", []map[string]interface{}{
+		{"kid": "new", "algorithm": "EdDSA", "private_key_file": filepath.Join(dir, "new.pem")},
+		{"kid": "old", "algorithm": "RS256", "public_key_file": filepath.Join(dir, "old.pub.pem")},
+	})
+	return jwt2.NewJwt(conf), rsaKey
+}
+
+func TestTokenHandler_JWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rotated, _ := newRotatedJwt(t)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().JWKS().Return(rotated.JWKS())
+
+	tokenHandler := handler.NewTokenHandler(hdl, mockTokenService)
+	router.GET("/.well-known/jwks.json", tokenHandler.JWKS)
+
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var jwks jwt2.JSONWebKeySet
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &jwks))
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "old", jwks.Keys[1].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+}
+
+func TestStrictAuth_KeyRotation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rotated, oldKey := newRotatedJwt(t)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+	r := gin.New()
+	r.GET("/rotation", middleware.StrictAuth(rotated, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	request := func(token string) int {
+		req, _ := http.NewRequest("GET", "/rotation", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp.Code
+	}
+	claims := jwt2.MyCustomClaims{
+		UserId: userId,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	// 当前密钥签发的令牌
+	token, err := rotated.GenToken(userId, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request(token))
+
+	// 退役密钥签发的旧令牌仍可验签
+	old := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+	old.Header["kid"] = "old"
+	token, err = old.SignedString(oldKey)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request(token))
+
+	// 用公开的RSA公钥作为HMAC密钥伪造令牌，算法与kid不符应被拒绝
+	pub, err := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	assert.NoError(t, err)
+	forged := gojwt.NewWithClaims(gojwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = "old"
+	token, err = forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, request(token))
+
+	// 旧的HS256令牌在切换到非对称密钥后失效
+	assert.Equal(t, http.StatusUnauthorized, request(genToken(t)))
+}
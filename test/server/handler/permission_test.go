@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"admin-webrtc-go/internal/handler"
+	"admin-webrtc-go/test/mocks/service"
+	jwt2 "admin-webrtc-go/pkg/jwt"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionHandler_PlatformOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 权限目录全局共享，租户内的用户即使拥有路由权限也不能修改
+	permissionHandler := handler.NewPermissionHandler(hdl, mock_service.NewMockPermissionService(ctrl))
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set("claims", &jwt2.MyCustomClaims{UserId: userId, TenantId: 2})
+	})
+	r.POST("/permissions", permissionHandler.CreatePermission)
+	r.PUT("/permissions/sort", permissionHandler.SortPermissions)
+	r.PUT("/permissions/:permissionId", permissionHandler.UpdatePermission)
+	r.DELETE("/permissions/:permissionId", permissionHandler.DeletePermission)
+	r.POST("/permissions/:permissionId/move", permissionHandler.MovePermission)
+
+	for _, tc := range []struct{ method, path string }{
+		{"POST", "/permissions"},
+		{"PUT", "/permissions/sort"},
+		{"PUT", "/permissions/1"},
+		{"DELETE", "/permissions/1"},
+		{"POST", "/permissions/1/move"},
+	} {
+		resp := performRequest(r, tc.method, tc.path, bytes.NewBufferString("{}"))
+		assert.Equal(t, http.StatusForbidden, resp.Code, tc.method+" "+tc.path)
+		assert.Contains(t, resp.Body.String(), `"code":2002`)
+	}
+}
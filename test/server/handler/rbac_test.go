@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"admin-webrtc-go/internal/handler"
+	"admin-webrtc-go/internal/middleware"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBACAuth_RouteAndMethod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+	// 中间件按注册的路由模板和请求方法校验，而不是实际请求路径
+	mockUserService.EXPECT().CheckAPIAuthPermission(gomock.Any(), userId, service.ApiRequest{
+		Method: "GET",
+		Route:  "/v1/users/:userId/sessions",
+	}).Return(true, nil)
+	mockUserService.EXPECT().CheckAPIAuthPermission(gomock.Any(), userId, service.ApiRequest{
+		Method: "DELETE",
+		Route:  "/v1/users/:userId/sessions",
+	}).Return(false, nil)
+	mockUserService.EXPECT().CheckAPIAuthPermission(gomock.Any(), userId, service.ApiRequest{
+		Method: "GET",
+		Route:  "/v1/:api/sessions",
+		Api:    "admin",
+	}).Return(true, nil)
+
+	r := gin.New()
+	group := r.Group("/v1").Use(
+		middleware.StrictAuth(jwt, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger),
+		middleware.RBACAuth(jwt, mockUserService, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger),
+	)
+	ok := func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	}
+	group.GET("/users/:userId/sessions", ok)
+	group.DELETE("/users/:userId/sessions", ok)
+	group.GET("/:api/sessions", ok)
+
+	token, err := jwt.GenToken(userId, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	request := func(method string, path string) int {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("GET", "/v1/users/456/sessions"))
+	assert.Equal(t, http.StatusForbidden, request("DELETE", "/v1/users/456/sessions"))
+	assert.Equal(t, http.StatusOK, request("GET", "/v1/admin/sessions"))
+}
+
+func TestRBACAuth_DefaultRoleForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockUserRepo.EXPECT().NextRoleGrantChange(gomock.Any(), userId, gomock.Any()).Return(time.Time{}, nil).AnyTimes()
+	// 普通用户只有默认角色的auth_0权限
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(gomock.Any(), userId, "api", "").Return(&[]repository.LoginedUser{
+		{RoleLabel: model.DefaultRoleLabel, Path: "auth_0", Method: "all"},
+	}, nil).AnyTimes()
+	mockCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockCacheRepo.EXPECT().Load(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userId string, load repository.PermissionLoader) ([]repository.ApiPermission, error) {
+			permissions, _, err := load(ctx, userId)
+			return permissions, err
+		}).AnyTimes()
+	userService := service.NewUserService(service.NewService(nil, logger, nil, jwt), mockUserRepo, mockCacheRepo, mockTokenService, nil, nil, nil, nil)
+	sessionHandler := handler.NewSessionHandler(hdl, mock_service.NewMockSessionService(ctrl))
+
+	r := gin.New()
+	group := r.Group("/v1").Use(
+		middleware.StrictAuth(jwt, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger),
+		middleware.RBACAuth(jwt, userService, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger),
+	)
+	group.GET("/users/:userId/sessions", sessionHandler.ListUserSessions)
+	group.DELETE("/users/:userId/sessions", sessionHandler.ForceRevokeUserSessions)
+	group.DELETE("/users/:userId/sessions/:sessionId", sessionHandler.ForceRevokeSession)
+
+	for _, tc := range []struct{ method, path string }{
+		{"GET", "/v1/users/456/sessions"},
+		{"DELETE", "/v1/users/456/sessions"},
+		{"DELETE", "/v1/users/456/sessions/a"},
+	} {
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+genToken(t))
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusForbidden, resp.Code, tc.method+" "+tc.path)
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/handler"
+	"admin-webrtc-go/internal/middleware"
+	"admin-webrtc-go/test/mocks/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionHandler_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockSessionService := mock_service.NewMockSessionService(ctrl)
+	mockSessionService.EXPECT().ListSessions(gomock.Any(), userId, gomock.Any()).Return([]*v1.SessionData{
+		{SessionId: "a", Current: true},
+	}, nil)
+
+	sessionHandler := handler.NewSessionHandler(hdl, mockSessionService)
+	router.GET("/sessions", middleware.StrictAuth(jwt, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger), sessionHandler.ListSessions)
+
+	req, _ := http.NewRequest("GET", "/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+genToken(t))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestSessionHandler_RevokeSession_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockSessionService := mock_service.NewMockSessionService(ctrl)
+	mockSessionService.EXPECT().RevokeOwnSession(gomock.Any(), userId, "other").Return(v1.ErrNotFound)
+
+	sessionHandler := handler.NewSessionHandler(hdl, mockSessionService)
+	// 共享router已处理过请求，池中的Context不能容纳新增路由的路径参数
+	r := gin.New()
+	r.DELETE("/sessions/:sessionId", middleware.StrictAuth(jwt, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger), sessionHandler.RevokeSession)
+
+	req, _ := http.NewRequest("DELETE", "/sessions/other", nil)
+	req.Header.Set("Authorization", "Bearer "+genToken(t))
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/handler"
+	"admin-webrtc-go/internal/middleware"
+	"admin-webrtc-go/test/mocks/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenHandler_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := v1.RefreshTokenRequest{
+		RefreshToken: "refresh",
+	}
+
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().Refresh(gomock.Any(), params.RefreshToken, gomock.Any()).Return(&v1.LoginResponseData{
+		AccessToken:  "access",
+		RefreshToken: "refresh2",
+	}, nil)
+
+	tokenHandler := handler.NewTokenHandler(hdl, mockTokenService)
+	router.POST("/token/refresh", tokenHandler.Refresh)
+	paramsJson, _ := json.Marshal(params)
+
+	resp := performRequest(router, "POST", "/token/refresh", bytes.NewBuffer(paramsJson))
+
+	assert.Equal(t, resp.Code, http.StatusOK)
+}
+
+func TestTokenHandler_Refresh_Reused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := v1.RefreshTokenRequest{
+		RefreshToken: "reused",
+	}
+
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().Refresh(gomock.Any(), params.RefreshToken, gomock.Any()).Return(nil, v1.ErrRefreshTokenReused)
+
+	tokenHandler := handler.NewTokenHandler(hdl, mockTokenService)
+	router.POST("/token/refresh/reused", tokenHandler.Refresh)
+	paramsJson, _ := json.Marshal(params)
+
+	resp := performRequest(router, "POST", "/token/refresh/reused", bytes.NewBuffer(paramsJson))
+
+	assert.Equal(t, resp.Code, http.StatusUnauthorized)
+}
+
+func TestTokenHandler_Logout_Revoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	gomock.InOrder(
+		mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil),
+		mockTokenService.EXPECT().Logout(gomock.Any(), gomock.Any()).Return(nil),
+		mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(true, nil),
+	)
+
+	tokenHandler := handler.NewTokenHandler(hdl, mockTokenService)
+	router.POST("/logout", middleware.StrictAuth(jwt, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger), tokenHandler.Logout)
+	token := genToken(t)
+
+	req, _ := http.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// 吊销后的令牌不能再通过认证
+	req, _ = http.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
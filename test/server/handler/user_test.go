@@ -8,7 +8,9 @@ import (
 	v1 "admin-webrtc-go/api/v1"
 	"admin-webrtc-go/internal/handler"
 	"admin-webrtc-go/internal/middleware"
+	"admin-webrtc-go/internal/service"
 	jwt2 "admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/pkg/password"
 	"admin-webrtc-go/test/mocks/service"
 	"time"
 
@@ -94,7 +96,7 @@ func TestUserHandler_Login(t *testing.T) {
 	}
 
 	mockUserService := mock_service.NewMockUserService(ctrl)
-	mockUserService.EXPECT().Login(gomock.Any(), &params).Return("", nil)
+	mockUserService.EXPECT().Login(gomock.Any(), &params, gomock.Any()).Return(&v1.LoginResponseData{}, nil)
 
 	userHandler := handler.NewUserHandler(hdl, mockUserService)
 	router.POST("/login", userHandler.Login)
@@ -117,7 +119,9 @@ func TestUserHandler_GetProfile(t *testing.T) {
 	}, nil)
 
 	userHandler := handler.NewUserHandler(hdl, mockUserService)
-	router.Use(middleware.NoStrictAuth(jwt, logger))
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	router.Use(middleware.NoStrictAuth(jwt, mockTokenService, logger))
 	router.GET("/user", userHandler.GetProfile)
 	req, _ := http.NewRequest("GET", "/user", nil)
 	req.Header.Set("Authorization", "Bearer "+genToken(t))
@@ -142,7 +146,9 @@ func TestUserHandler_UpdateProfile(t *testing.T) {
 	mockUserService.EXPECT().UpdateProfile(gomock.Any(), userId, &params).Return(nil)
 
 	userHandler := handler.NewUserHandler(hdl, mockUserService)
-	router.Use(middleware.StrictAuth(jwt, logger))
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTokenService.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	router.Use(middleware.StrictAuth(jwt, mockTokenService, mock_service.NewMockApiKeyService(ctrl), logger))
 	router.PUT("/user", userHandler.UpdateProfile)
 	paramsJson, _ := json.Marshal(params)
 
@@ -171,3 +177,80 @@ func genToken(t *testing.T) string {
 	}
 	return token
 }
+
+func TestUserHandler_LoginTwoFactor_InvalidCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := v1.TwoFactorLoginRequest{
+		ChallengeToken: "challenge",
+		Code:           "000000",
+	}
+
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockUserService.EXPECT().LoginTwoFactor(gomock.Any(), &params, gomock.Any()).Return(nil, v1.ErrTwoFactorCodeInvalid)
+
+	userHandler := handler.NewUserHandler(hdl, mockUserService)
+	// 共享router在前面的用例中挂载了鉴权中间件，免登录接口使用独立的router
+	r := gin.New()
+	r.POST("/login/2fa", userHandler.LoginTwoFactor)
+	paramsJson, _ := json.Marshal(params)
+
+	resp := performRequest(r, "POST", "/login/2fa", bytes.NewBuffer(paramsJson))
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"code":1201`)
+}
+
+func TestUserHandler_Login_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := v1.LoginRequest{
+		Email:    "xxx@gmail.com",
+		Password: "123456",
+	}
+
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockUserService.EXPECT().Login(gomock.Any(), &params, gomock.Any()).Return(nil, &service.RetryAfterError{
+		Err:        v1.ErrAccountLocked,
+		RetryAfter: 90 * time.Second,
+	})
+
+	userHandler := handler.NewUserHandler(hdl, mockUserService)
+	r := gin.New()
+	r.POST("/login", userHandler.Login)
+	paramsJson, _ := json.Marshal(params)
+
+	resp := performRequest(r, "POST", "/login", bytes.NewBuffer(paramsJson))
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "90", resp.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Body.String(), `"code":1401`)
+}
+
+func TestUserHandler_Register_WeakPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := v1.RegisterRequest{
+		Password: "1",
+		Email:    "xxx@gmail.com",
+	}
+
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockUserService.EXPECT().Register(gomock.Any(), &params).Return(&service.PasswordPolicyError{
+		Violations: []password.Violation{{Rule: password.RuleMinLength, Message: "密码长度不能少于8个字符"}},
+	})
+
+	userHandler := handler.NewUserHandler(hdl, mockUserService)
+	r := gin.New()
+	r.POST("/register", userHandler.Register)
+	paramsJson, _ := json.Marshal(params)
+
+	resp := performRequest(r, "POST", "/register", bytes.NewBuffer(paramsJson))
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"code":1501`)
+	assert.Contains(t, resp.Body.String(), `"rule":"min_length"`)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"admin-webrtc-go/internal/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPermissionCache(size int, ttl time.Duration) repository.PermissionCacheRepository {
+	conf := viper.New()
+	conf.Set("security.rbac.cache.size", size)
+	conf.Set("security.rbac.cache.ttl", ttl)
+	return repository.NewPermissionCacheRepository(repository.NewRepository(logger, nil), conf)
+}
+
+// countingLoader 返回按用户区分的权限并记录查库次数
+func countingLoader(calls map[string]int) repository.PermissionLoader {
+	return func(ctx context.Context, userId string) ([]repository.ApiPermission, time.Time, error) {
+		calls[userId]++
+		return []repository.ApiPermission{{Path: "/v1/" + userId, Method: "GET"}}, time.Time{}, nil
+	}
+}
+
+func TestPermissionCache_HitAndMiss(t *testing.T) {
+	cache := newPermissionCache(10, time.Minute)
+	ctx := context.Background()
+	calls := map[string]int{}
+
+	for i := 0; i < 3; i++ {
+		permissions, err := cache.Load(ctx, "u1", countingLoader(calls))
+		assert.NoError(t, err)
+		assert.Equal(t, []repository.ApiPermission{{Path: "/v1/u1", Method: "GET"}}, permissions)
+	}
+	assert.Equal(t, 1, calls["u1"])
+
+	stats := cache.Stats()
+	assert.True(t, stats.Enabled)
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Size)
+
+	// 查询出错时不缓存
+	_, err := cache.Load(ctx, "u2", func(ctx context.Context, userId string) ([]repository.ApiPermission, time.Time, error) {
+		return nil, time.Time{}, errors.New("db down")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, cache.Stats().Size)
+}
+
+func TestPermissionCache_EvictAndExpire(t *testing.T) {
+	cache := newPermissionCache(2, 50*time.Millisecond)
+	ctx := context.Background()
+	calls := map[string]int{}
+	load := countingLoader(calls)
+
+	_, _ = cache.Load(ctx, "u1", load)
+	_, _ = cache.Load(ctx, "u2", load)
+	// 访问u1使u2成为最久未使用的条目
+	_, _ = cache.Load(ctx, "u1", load)
+	_, _ = cache.Load(ctx, "u3", load)
+	assert.Equal(t, uint64(1), cache.Stats().Evictions)
+	assert.Equal(t, 2, cache.Stats().Size)
+
+	_, _ = cache.Load(ctx, "u1", load)
+	assert.Equal(t, 1, calls["u1"])
+	_, _ = cache.Load(ctx, "u2", load)
+	assert.Equal(t, 2, calls["u2"])
+
+	// 过期后重新查库
+	time.Sleep(60 * time.Millisecond)
+	_, _ = cache.Load(ctx, "u1", load)
+	assert.Equal(t, 2, calls["u1"])
+}
+
+func TestPermissionCache_Invalidate(t *testing.T) {
+	cache := newPermissionCache(10, time.Minute)
+	ctx := context.Background()
+	calls := map[string]int{}
+	load := countingLoader(calls)
+
+	_, _ = cache.Load(ctx, "u1", load)
+	_, _ = cache.Load(ctx, "u2", load)
+	cache.Invalidate(ctx, "u1")
+	_, _ = cache.Load(ctx, "u1", load)
+	_, _ = cache.Load(ctx, "u2", load)
+	assert.Equal(t, 2, calls["u1"])
+	assert.Equal(t, 1, calls["u2"])
+
+	cache.InvalidateAll(ctx)
+	assert.Equal(t, 0, cache.Stats().Size)
+	_, _ = cache.Load(ctx, "u2", load)
+	assert.Equal(t, 2, calls["u2"])
+	assert.Equal(t, uint64(2), cache.Stats().Invalidations)
+}
+
+func TestPermissionCache_InvalidateDuringLoad(t *testing.T) {
+	cache := newPermissionCache(10, time.Minute)
+	ctx := context.Background()
+
+	// 查库期间角色发生变化，查到的旧权限不能写入缓存
+	_, err := cache.Load(ctx, "u1", func(ctx context.Context, userId string) ([]repository.ApiPermission, time.Time, error) {
+		cache.Invalidate(ctx, "u1")
+		return []repository.ApiPermission{{Path: "/v1/stale"}}, time.Time{}, nil
+	})
+	assert.NoError(t, err)
+
+	calls := map[string]int{}
+	permissions, _ := cache.Load(ctx, "u1", countingLoader(calls))
+	assert.Equal(t, 1, calls["u1"])
+	assert.Equal(t, "/v1/u1", permissions[0].Path)
+}
+
+func TestPermissionCache_Disabled(t *testing.T) {
+	conf := viper.New()
+	conf.Set("security.rbac.cache.enabled", false)
+	cache := repository.NewPermissionCacheRepository(repository.NewRepository(logger, nil), conf)
+	ctx := context.Background()
+	calls := map[string]int{}
+
+	_, _ = cache.Load(ctx, "u1", countingLoader(calls))
+	_, _ = cache.Load(ctx, "u1", countingLoader(calls))
+	assert.Equal(t, 2, calls["u1"])
+	assert.False(t, cache.Stats().Enabled)
+}
+
+func TestPermissionCache_ValidUntil(t *testing.T) {
+	cache := newPermissionCache(10, time.Minute)
+	ctx := context.Background()
+	calls := map[string]int{}
+	loader := func(validUntil time.Time) repository.PermissionLoader {
+		return func(ctx context.Context, userId string) ([]repository.ApiPermission, time.Time, error) {
+			calls[userId]++
+			return []repository.ApiPermission{{Path: "/v1/" + userId}}, validUntil, nil
+		}
+	}
+
+	// 临时授权在ttl内结束时，缓存在结束时间过期
+	load := loader(time.Now().Add(50 * time.Millisecond))
+	_, _ = cache.Load(ctx, "u1", load)
+	_, _ = cache.Load(ctx, "u1", load)
+	assert.Equal(t, 1, calls["u1"])
+	time.Sleep(80 * time.Millisecond)
+	_, _ = cache.Load(ctx, "u1", load)
+	assert.Equal(t, 2, calls["u1"])
+
+	// 已经过了有效时间的结果不缓存
+	load = loader(time.Now().Add(-time.Second))
+	_, _ = cache.Load(ctx, "u2", load)
+	_, _ = cache.Load(ctx, "u2", load)
+	assert.Equal(t, 2, calls["u2"])
+}
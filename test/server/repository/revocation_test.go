@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"admin-webrtc-go/internal/repository"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationRepository_RevokeSession_AfterCommit(t *testing.T) {
+	repo, mock := setupTenantRepositories(t)
+	revocationRepo := repository.NewRevocationRepository(repo, viper.New())
+	tm := repository.NewTransaction(repo)
+	ctx := context.Background()
+	now := time.Now()
+
+	// 事务回滚后吊销不能留在缓存中
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `session` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+	err := tm.Transaction(ctx, func(ctx context.Context) error {
+		if err := revocationRepo.RevokeSession(ctx, "s1", now); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+
+	mock.ExpectQuery("SELECT \\* FROM `session` WHERE session_id = \\?").
+		WithArgs("s1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id"}).AddRow(1, "s1"))
+	mock.ExpectQuery("SELECT \\* FROM `user_token_revocation` WHERE user_id = \\?").
+		WithArgs("u1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	revoked, err := revocationRepo.IsRevoked(ctx, "", "s1", "u1", now)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	// 提交后立即生效
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `session` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, tm.Transaction(ctx, func(ctx context.Context) error {
+		return revocationRepo.RevokeSession(ctx, "s2", now)
+	}))
+	revoked, err = revocationRepo.IsRevoked(ctx, "", "s2", "u1", now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func setupRoleRepository(t *testing.T) (repository.RoleRepository, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      mockDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm connection: %v", err)
+	}
+
+	return repository.NewRoleRepository(repository.NewRepository(logger, db)), mock
+}
+
+func TestRoleRepository_AttachPermissions(t *testing.T) {
+	roleRepo, mock := setupRoleRepository(t)
+	ctx := context.Background()
+
+	// 部分权限不存在时不写入
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `permission`").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	assert.ErrorIs(t, roleRepo.AttachPermissions(ctx, 3, []uint{1, 2}, "allow"), v1.ErrPermissionNotFound)
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `permission`").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectBegin()
+	// 已关联的权限更新effect
+	mock.ExpectExec("INSERT INTO `role_permissions`.*ON DUPLICATE KEY UPDATE `effect`=VALUES\\(`effect`\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	assert.NoError(t, roleRepo.AttachPermissions(ctx, 3, []uint{1, 2}, "deny"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoleRepository_List(t *testing.T) {
+	roleRepo, mock := setupRoleRepository(t)
+	ctx := context.Background()
+
+	// 只查询已删除的角色
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `role` WHERE deleted_at IS NOT NULL AND \\(role_label LIKE \\? OR role_name LIKE \\?\\)").
+		WithArgs("%ops%", "%ops%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+	mock.ExpectQuery("SELECT \\* FROM `role` WHERE deleted_at IS NOT NULL .* ORDER BY id ASC LIMIT \\? OFFSET \\?").
+		WithArgs("%ops%", "%ops%", 20, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role_label"}).AddRow(21, "ops-old"))
+
+	roles, total, err := roleRepo.List(ctx, "ops", true, 20, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21), total)
+	assert.Len(t, roles, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoleRepository_ListUsers_DataScope(t *testing.T) {
+	roleRepo, mock := setupRoleRepository(t)
+
+	// 没有调用者时不返回任何记录
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users` .* WHERE user_role.role_id = \\? AND 1 = 0").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT `users`.`id`.* WHERE user_role.role_id = \\? AND 1 = 0").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, total, err := roleRepo.ListUsers(context.Background(), 5, 0, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	// 本部门及下级部门与仅本人取并集，数据范围只解析一次
+	ctx := context.WithValue(context.Background(), "claims", &jwt.MyCustomClaims{UserId: "u1", TenantId: 3})
+	mock.ExpectQuery("SELECT `role`.`id` FROM `user_role`").
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery("SELECT `role`.`id` FROM `role_grant` .* AND `role_grant`.`tenant_id` = \\?").
+		WithArgs("u1", sqlmock.AnyArg(), sqlmock.AnyArg(), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT `id`,`parent_id` FROM `role` WHERE `role`.`tenant_id` = \\?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow(1, 0).AddRow(2, 0))
+	mock.ExpectQuery("SELECT `id`,`data_scope` FROM `role` WHERE id IN \\(\\?,\\?\\) AND `role`.`tenant_id` = \\?").
+		WithArgs(1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "data_scope"}).AddRow(1, "dept_and_children").AddRow(2, "self"))
+	mock.ExpectQuery("SELECT `dept_id` FROM `users` WHERE user_id = \\? AND `users`.`tenant_id` = \\?").
+		WithArgs("u1", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"dept_id"}).AddRow(10))
+	mock.ExpectQuery("SELECT `id`,`parent_id` FROM `dept` WHERE `dept`.`tenant_id` = \\?").
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow(10, 0).AddRow(11, 10).AddRow(12, 11).AddRow(20, 0))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `users` .* AND `users`.`tenant_id` = \\? AND \\(users.dept_id IN \\(\\?,\\?,\\?\\) OR users.user_id = \\?\\)").
+		WithArgs(5, 3, 10, 11, 12, "u1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT `users`.`id`.* AND \\(users.dept_id IN \\(\\?,\\?,\\?\\) OR users.user_id = \\?\\)").
+		WithArgs(5, 3, 10, 11, 12, "u1", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, "u2"))
+	users, total, err := roleRepo.ListUsers(ctx, 5, 0, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, users, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDescendantDeptIds(t *testing.T) {
+	parents := map[uint]uint{1: 0, 2: 1, 3: 2, 4: 1, 5: 0}
+	assert.Equal(t, []uint{2, 4, 3}, repository.DescendantDeptIds(parents, []uint{1}))
+	// 已包含的部门不重复返回
+	assert.Equal(t, []uint{2, 4}, repository.DescendantDeptIds(parents, []uint{1, 3}))
+	assert.Empty(t, repository.DescendantDeptIds(parents, []uint{5}))
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/pkg/jwt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func setupTenantRepositories(t *testing.T) (*repository.Repository, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      mockDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm connection: %v", err)
+	}
+
+	return repository.NewRepository(logger, db), mock
+}
+
+func tenantCtx(tenantId uint) context.Context {
+	return context.WithValue(context.Background(), "claims", &jwt.MyCustomClaims{UserId: "u1", TenantId: tenantId})
+}
+
+func TestTenantScope_Query(t *testing.T) {
+	repo, mock := setupTenantRepositories(t)
+	userRepo := repository.NewUserRepository(repo)
+	deptRepo := repository.NewDeptRepository(repo)
+	ctx := tenantCtx(2)
+
+	// 其他租户的用户查不到
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE user_id = \\? AND `users`.`tenant_id` = \\? AND `users`.`deleted_at` IS NULL").
+		WithArgs("123", 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err := userRepo.GetByID(ctx, "123")
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	mock.ExpectQuery("SELECT \\* FROM `dept` WHERE id = \\? AND `dept`.`tenant_id` = \\? AND `dept`.`deleted_at` IS NULL").
+		WithArgs(9, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = deptRepo.GetById(ctx, 9)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	// 列表同样只包含本租户的记录
+	mock.ExpectQuery("SELECT \\* FROM `dept` WHERE `dept`.`tenant_id` = \\? AND `dept`.`deleted_at` IS NULL ORDER BY sort ASC, id ASC").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id"}).AddRow(1, 2))
+	depts, err := deptRepo.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, depts, 1)
+
+	// 平台租户同样只能看到自己的记录
+	mock.ExpectQuery("SELECT \\* FROM `users` WHERE user_id = \\? AND `users`.`tenant_id` = \\?").
+		WithArgs("123", 0, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, "123"))
+	_, err = userRepo.GetByID(tenantCtx(model.PlatformTenantId), "123")
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantScope_SessionAndImpersonation(t *testing.T) {
+	repo, mock := setupTenantRepositories(t)
+	sessionRepo := repository.NewSessionRepository(repo)
+	impersonationRepo := repository.NewImpersonationRepository(repo)
+	ctx := tenantCtx(2)
+
+	// 管理员查不到其他租户用户的会话
+	mock.ExpectQuery("SELECT \\* FROM `session` WHERE \\(user_id = \\? AND revoked_at IS NULL AND expires_at > \\?\\) AND `session`.`tenant_id` = \\? ORDER BY last_seen_at desc").
+		WithArgs("123", sqlmock.AnyArg(), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	sessions, err := sessionRepo.ListActiveByUser(ctx, "123")
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	mock.ExpectQuery("SELECT \\* FROM `session` WHERE session_id = \\? AND `session`.`tenant_id` = \\?").
+		WithArgs("a", 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = sessionRepo.GetBySessionId(ctx, "a")
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	// 模拟登录审计只包含本租户的记录
+	mock.ExpectQuery("SELECT \\* FROM `impersonation` WHERE `impersonation`.`tenant_id` = \\? ORDER BY id desc LIMIT \\?").
+		WithArgs(2, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id"}).AddRow(1, 2))
+	list, err := impersonationRepo.List(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantScope_Write(t *testing.T) {
+	repo, mock := setupTenantRepositories(t)
+	userRepo := repository.NewUserRepository(repo)
+	deptRepo := repository.NewDeptRepository(repo)
+	ctx := tenantCtx(2)
+
+	// 创建时写入调用者的租户
+	user := &model.User{Id: 1, UserId: "123", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `users`").
+		WithArgs(user.UserId, user.Nickname, user.Password, user.Email, user.EmailVerifiedAt, user.ServiceAccount, user.DeptId, 2, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Id).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, userRepo.Create(ctx, user))
+	assert.Equal(t, uint(2), user.TenantId)
+
+	// 不能删除其他租户的记录，也不清理其关联
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `dept` SET `deleted_at`=\\? WHERE `dept`.`id` = \\? AND `dept`.`tenant_id` = \\?").
+		WithArgs(sqlmock.AnyArg(), 9, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	assert.NoError(t, deptRepo.Delete(ctx, 9))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantScope_Without(t *testing.T) {
+	repo, mock := setupTenantRepositories(t)
+	userRepo := repository.NewUserRepository(repo)
+
+	// 没有登录信息或显式跨租户时不过滤
+	for _, ctx := range []context.Context{context.Background(), repository.WithoutTenant(tenantCtx(2))} {
+		mock.ExpectQuery("SELECT \\* FROM `users` WHERE user_id = \\? AND `users`.`deleted_at` IS NULL ORDER BY").
+			WithArgs("123", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tenant_id"}).AddRow(1, "123", 3))
+		user, err := userRepo.GetByID(ctx, "123")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), user.TenantId)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	v1 "admin-webrtc-go/api/v1"
 	"admin-webrtc-go/pkg/log"
 	"testing"
 	"time"
@@ -54,7 +55,7 @@ func TestUserRepository_Create(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO `users`").
-		WithArgs(user.UserId, user.Nickname, user.Password, user.Email, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Id).
+		WithArgs(user.UserId, user.Nickname, user.Password, user.Email, user.EmailVerifiedAt, user.ServiceAccount, user.DeptId, user.TenantId, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Id).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
@@ -123,3 +124,82 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserRepository_GetUserWithRolesAndPermission_Inherited(t *testing.T) {
+	userRepo, mock := setupRepository(t)
+
+	ctx := context.Background()
+	// 用户直接拥有admin(3)，admin -> ops(2) -> auditor(1)，角色4已删除
+	mock.ExpectQuery("SELECT `role`.`id` FROM `user_role` join role on user_role.role_id = role.id AND role.deleted_at IS NULL WHERE user_role.user_user_id = \\?").
+		WithArgs("123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery("SELECT `role`.`id` FROM `role_grant`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT `id`,`parent_id` FROM `role`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow(1, 0).AddRow(2, 1).AddRow(3, 2))
+	mock.ExpectQuery("SELECT users.user_id, .* FROM `users` join role on role.id IN \\(\\?,\\?,\\?\\)").
+		WithArgs(3, 2, 1, "123", "api").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role_id", "path"}).AddRow("123", 1, "/v1/audit/*").AddRow("123", 3, "/v1/roles"))
+
+	users, err := userRepo.GetUserWithRolesAndPermission(ctx, "123", "api", "")
+	assert.NoError(t, err)
+	assert.Len(t, *users, 2)
+	assert.Equal(t, uint(1), (*users)[0].RoleId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetUserWithRolesAndPermission_Grant(t *testing.T) {
+	userRepo, mock := setupRepository(t)
+
+	ctx := context.Background()
+	// 只有当前生效的临时授权计入用户的角色，同样沿父角色继承
+	mock.ExpectQuery("SELECT `role`.`id` FROM `user_role`").
+		WithArgs("123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT `role`.`id` FROM `role_grant` join role on role_grant.role_id = role.id AND role.deleted_at IS NULL " +
+		"WHERE role_grant.user_id = \\? AND role_grant.ended_at IS NULL AND role_grant.starts_at <= \\? AND role_grant.ends_at > \\?").
+		WithArgs("123", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery("SELECT `id`,`parent_id` FROM `role`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow(1, 0).AddRow(4, 1))
+	mock.ExpectQuery("SELECT users.user_id, .* FROM `users` join role on role.id IN \\(\\?,\\?\\)").
+		WithArgs(4, 1, "123", "menu").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role_id", "path"}).AddRow("123", 4, "/oncall"))
+
+	users, err := userRepo.GetUserWithRolesAndPermission(ctx, "123", "menu", "")
+	assert.NoError(t, err)
+	assert.Len(t, *users, 1)
+
+	// 没有角色也没有生效的授权
+	mock.ExpectQuery("SELECT `role`.`id` FROM `user_role`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT `role`.`id` FROM `role_grant`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = userRepo.GetUserWithRolesAndPermission(ctx, "123", "menu", "")
+	assert.ErrorIs(t, err, v1.ErrEmptyRecord)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_NextRoleGrantChange(t *testing.T) {
+	userRepo, mock := setupRepository(t)
+
+	ctx := context.Background()
+	now := time.Now()
+	// 已开始的授权取结束时间，未开始的取开始时间
+	mock.ExpectQuery("SELECT `starts_at`,`ends_at` FROM `role_grant` WHERE user_id = \\? AND ended_at IS NULL AND ends_at > \\?").
+		WithArgs("123", now).
+		WillReturnRows(sqlmock.NewRows([]string{"starts_at", "ends_at"}).
+			AddRow(now.Add(-time.Hour), now.Add(2*time.Hour)).
+			AddRow(now.Add(30*time.Minute), now.Add(3*time.Hour)))
+
+	next, err := userRepo.NextRoleGrantChange(ctx, "123", now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(30*time.Minute), next)
+
+	mock.ExpectQuery("SELECT `starts_at`,`ends_at` FROM `role_grant`").
+		WillReturnRows(sqlmock.NewRows([]string{"starts_at", "ends_at"}))
+	next, err = userRepo.NextRoleGrantChange(ctx, "123", now)
+	assert.NoError(t, err)
+	assert.True(t, next.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
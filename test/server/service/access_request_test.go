@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type accessRequestMocks struct {
+	requestRepo *mock_repository.MockAccessRequestRepository
+	roleRepo    *mock_repository.MockRoleRepository
+	grantRepo   *mock_repository.MockRoleGrantRepository
+	cacheRepo   *mock_repository.MockPermissionCacheRepository
+}
+
+func setupAccessRequestService(t *testing.T) (service.AccessRequestService, *accessRequestMocks) {
+	ctrl := gomock.NewController(t)
+	m := &accessRequestMocks{
+		requestRepo: mock_repository.NewMockAccessRequestRepository(ctrl),
+		roleRepo:    mock_repository.NewMockRoleRepository(ctrl),
+		grantRepo:   mock_repository.NewMockRoleGrantRepository(ctrl),
+		cacheRepo:   mock_repository.NewMockPermissionCacheRepository(ctrl),
+	}
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	mockTm.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	srv := service.NewService(mockTm, logger, sf, j)
+	return service.NewAccessRequestService(srv, m.requestRepo, m.roleRepo, m.grantRepo, m.cacheRepo), m
+}
+
+func TestAccessRequestService_Create(t *testing.T) {
+	accessRequestService, m := setupAccessRequestService(t)
+	ctx := context.Background()
+	req := &v1.CreateAccessRequestRequest{RoleId: 2, Reason: "oncall", DurationHours: 8}
+
+	// 没有设置审批人角色的角色不能申请
+	m.roleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2}, nil)
+	_, err := accessRequestService.Create(ctx, "u1", req)
+	assert.ErrorIs(t, err, v1.ErrRoleNotRequestable)
+
+	m.roleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2, RoleLabel: "ops", ApproverRoleId: 1}, nil).AnyTimes()
+	m.requestRepo.EXPECT().HasRole(ctx, "u1", uint(2)).Return(true, nil)
+	_, err = accessRequestService.Create(ctx, "u1", req)
+	assert.ErrorIs(t, err, v1.ErrRoleAlreadyAssigned)
+
+	m.requestRepo.EXPECT().HasRole(ctx, "u1", uint(2)).Return(false, nil).AnyTimes()
+	m.requestRepo.EXPECT().HasPending(ctx, "u1", uint(2)).Return(true, nil)
+	_, err = accessRequestService.Create(ctx, "u1", req)
+	assert.ErrorIs(t, err, v1.ErrAccessRequestPending)
+
+	// 创建申请时记录第一次状态变化
+	m.requestRepo.EXPECT().HasPending(ctx, "u1", uint(2)).Return(false, nil)
+	m.requestRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, request *model.AccessRequest) error {
+		request.Id = 7
+		return nil
+	})
+	m.requestRepo.EXPECT().AddEvent(ctx, &model.AccessRequestEvent{RequestId: 7, ToStatus: model.AccessRequestPending, ActorId: "u1", Comment: "oncall"}).Return(nil)
+	data, err := accessRequestService.Create(ctx, "u1", req)
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccessRequestPending, data.Status)
+	assert.Equal(t, "ops", data.RoleLabel)
+}
+
+func TestAccessRequestService_Approve(t *testing.T) {
+	accessRequestService, m := setupAccessRequestService(t)
+	ctx := context.Background()
+	pending := func(durationHours int) *model.AccessRequest {
+		return &model.AccessRequest{Id: 7, TenantId: 3, UserId: "u1", RoleId: 2, Reason: "oncall", DurationHours: durationHours, Status: model.AccessRequestPending}
+	}
+	m.roleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2, ApproverRoleId: 1}, nil).AnyTimes()
+
+	// 不能审批自己的申请
+	m.requestRepo.EXPECT().GetById(ctx, uint(7)).Return(pending(0), nil)
+	_, err := accessRequestService.Approve(ctx, "u1", 7, "")
+	assert.ErrorIs(t, err, v1.ErrAccessRequestSelfApproval)
+
+	// 没有审批人角色
+	m.requestRepo.EXPECT().GetById(ctx, uint(7)).Return(pending(0), nil)
+	m.requestRepo.EXPECT().GetUserRoleIds(ctx, "u2").Return([]uint{5}, nil)
+	_, err = accessRequestService.Approve(ctx, "u2", 7, "")
+	assert.ErrorIs(t, err, v1.ErrAccessRequestForbidden)
+
+	// 已处理的申请不能再审批
+	m.requestRepo.EXPECT().GetById(ctx, uint(7)).Return(&model.AccessRequest{Id: 7, UserId: "u1", RoleId: 2, Status: model.AccessRequestRejected}, nil)
+	_, err = accessRequestService.Reject(ctx, "admin", 7, "")
+	assert.ErrorIs(t, err, v1.ErrAccessRequestDecided)
+
+	m.requestRepo.EXPECT().GetUserRoleIds(ctx, "admin").Return([]uint{1, 4}, nil).AnyTimes()
+
+	// 长期申请直接分配角色，与状态变化在同一事务中
+	m.requestRepo.EXPECT().GetById(ctx, uint(7)).Return(pending(0), nil)
+	m.roleRepo.EXPECT().AddUserRoles(ctx, []string{"u1"}, []uint{2}).Return(nil)
+	m.requestRepo.EXPECT().Transition(ctx, gomock.Any(), model.AccessRequestApproved, "admin", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *model.AccessRequest, status string, actorId string, decidedAt time.Time) (bool, error) {
+			request.Status = status
+			return true, nil
+		})
+	m.requestRepo.EXPECT().AddEvent(ctx, &model.AccessRequestEvent{TenantId: 3, RequestId: 7, FromStatus: model.AccessRequestPending,
+		ToStatus: model.AccessRequestApproved, ActorId: "admin", Comment: "ok"}).Return(nil)
+	m.cacheRepo.EXPECT().Invalidate(ctx, "u1")
+	data, err := accessRequestService.Approve(ctx, "admin", 7, "ok")
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccessRequestApproved, data.Status)
+
+	// 限时申请创建临时授权；并发审批时已被他人处理则整体回滚
+	m.requestRepo.EXPECT().GetById(ctx, uint(7)).Return(pending(8), nil)
+	m.grantRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, grant *model.RoleGrant) error {
+		assert.Equal(t, uint(3), grant.TenantId)
+		assert.Equal(t, 8*time.Hour, grant.EndsAt.Sub(grant.StartsAt))
+		grant.Id = 11
+		return nil
+	})
+	m.requestRepo.EXPECT().Transition(ctx, gomock.Any(), model.AccessRequestApproved, "admin", gomock.Any()).Return(false, nil)
+	_, err = accessRequestService.Approve(ctx, "admin", 7, "")
+	assert.ErrorIs(t, err, v1.ErrAccessRequestDecided)
+}
+
+func TestAccessRequestService_Get(t *testing.T) {
+	accessRequestService, m := setupAccessRequestService(t)
+	ctx := context.Background()
+	m.requestRepo.EXPECT().GetById(ctx, uint(7)).Return(&model.AccessRequest{Id: 7, UserId: "u1", RoleId: 2, Status: model.AccessRequestApproved}, nil).AnyTimes()
+	m.roleRepo.EXPECT().GetById(ctx, uint(2), true).Return(&model.Role{Id: 2, ApproverRoleId: 1}, nil).AnyTimes()
+
+	// 既不是申请人也不是审批人
+	m.requestRepo.EXPECT().GetUserRoleIds(ctx, "u3").Return(nil, nil)
+	_, err := accessRequestService.Get(ctx, "u3", 7)
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	m.requestRepo.EXPECT().GetEvents(ctx, uint(7)).Return([]model.AccessRequestEvent{
+		{ToStatus: model.AccessRequestPending, ActorId: "u1"},
+		{FromStatus: model.AccessRequestPending, ToStatus: model.AccessRequestApproved, ActorId: "admin"},
+	}, nil)
+	data, err := accessRequestService.Get(ctx, "u1", 7)
+	assert.NoError(t, err)
+	assert.Len(t, data.Events, 2)
+	assert.Equal(t, "admin", data.Events[1].ActorId)
+}
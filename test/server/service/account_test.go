@@ -0,0 +1,203 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/mailer"
+	"admin-webrtc-go/pkg/password"
+	"admin-webrtc-go/test/mocks/mailer"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var linkTokenRe = regexp.MustCompile(`\?token=(\S+)`)
+
+// tokenFromMail 从邮件正文的链接中取出token
+func tokenFromMail(t *testing.T, msg *mailer.Message) string {
+	m := linkTokenRe.FindStringSubmatch(msg.Body)
+	if !assert.Len(t, m, 2) {
+		return ""
+	}
+	token, err := url.QueryUnescape(m[1])
+	assert.NoError(t, err)
+	return token
+}
+
+func TestAccountService_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockMailer := mock_mailer.NewMockMailer(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	accountService := service.NewAccountService(srv, viper.New(), mockUserRepo, mockTokenService, mock_service.NewMockPasswordService(ctrl), mockMailer)
+
+	ctx := context.Background()
+	user := &model.User{UserId: "123", Email: "xxx@gmail.com"}
+	var sent *mailer.Message
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(user, nil).Times(2)
+	mockMailer.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg *mailer.Message) error {
+		sent = msg
+		return nil
+	})
+	mockTokenService.EXPECT().IsRevoked(ctx, gomock.Any()).Return(false, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockUserRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, user *model.User) error {
+		assert.NotNil(t, user.EmailVerifiedAt)
+		return nil
+	})
+	mockTokenService.EXPECT().Logout(ctx, gomock.Any()).Return(nil)
+
+	assert.NoError(t, accountService.SendVerifyEmail(ctx, "123"))
+	assert.Equal(t, []string{"xxx@gmail.com"}, sent.To)
+	assert.NoError(t, accountService.VerifyEmail(ctx, tokenFromMail(t, sent)))
+}
+
+func TestAccountService_VerifyEmail_EmailChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockMailer := mock_mailer.NewMockMailer(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	accountService := service.NewAccountService(srv, viper.New(), mockUserRepo, mockTokenService, mock_service.NewMockPasswordService(ctrl), mockMailer)
+
+	ctx := context.Background()
+	var sent *mailer.Message
+	gomock.InOrder(
+		mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123", Email: "old@gmail.com"}, nil),
+		mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123", Email: "new@gmail.com"}, nil),
+	)
+	mockMailer.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg *mailer.Message) error {
+		sent = msg
+		return nil
+	})
+	mockTokenService.EXPECT().IsRevoked(ctx, gomock.Any()).Return(false, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+
+	assert.NoError(t, accountService.SendVerifyEmail(ctx, "123"))
+	assert.ErrorIs(t, accountService.VerifyEmail(ctx, tokenFromMail(t, sent)), v1.ErrLinkInvalid)
+}
+
+func TestAccountService_ForgotPassword_UnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockMailer := mock_mailer.NewMockMailer(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	accountService := service.NewAccountService(srv, viper.New(), mockUserRepo, mock_service.NewMockTokenService(ctrl), mock_service.NewMockPasswordService(ctrl), mockMailer)
+
+	ctx := context.Background()
+	mockUserRepo.EXPECT().GetByEmail(ctx, "nobody@gmail.com").Return(nil, nil)
+
+	assert.NoError(t, accountService.ForgotPassword(ctx, "nobody@gmail.com"))
+}
+
+func TestAccountService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockPasswordService := mock_service.NewMockPasswordService(ctrl)
+	mockMailer := mock_mailer.NewMockMailer(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	accountService := service.NewAccountService(srv, viper.New(), mockUserRepo, mockTokenService, mockPasswordService, mockMailer)
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old"), bcrypt.MinCost)
+	user := &model.User{UserId: "123", Email: "xxx@gmail.com", Password: string(hashedPassword)}
+	var sent *mailer.Message
+	mockUserRepo.EXPECT().GetByEmail(ctx, "xxx@gmail.com").Return(user, nil)
+	mockMailer.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg *mailer.Message) error {
+		sent = msg
+		return nil
+	})
+	mockTokenService.EXPECT().IsRevoked(ctx, gomock.Any()).Return(false, nil).Times(2)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(2)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(user, nil).Times(2)
+	mockPasswordService.EXPECT().SetPassword(ctx, user, "N3w-passw0rd").DoAndReturn(setPassword)
+	mockUserRepo.EXPECT().Update(ctx, user).Return(nil)
+	mockTokenService.EXPECT().Logout(ctx, gomock.Any()).Return(nil)
+	mockTokenService.EXPECT().LogoutAll(ctx, "123").Return(nil)
+
+	assert.NoError(t, accountService.ForgotPassword(ctx, "xxx@gmail.com"))
+	token := tokenFromMail(t, sent)
+	req := &v1.ResetPasswordRequest{Token: token, Password: "N3w-passw0rd"}
+	assert.NoError(t, accountService.ResetPassword(ctx, req))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("N3w-passw0rd")))
+	// 密码改过之后同一链接不能再次使用
+	assert.ErrorIs(t, accountService.ResetPassword(ctx, req), v1.ErrLinkInvalid)
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockPasswordService := mock_service.NewMockPasswordService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	accountService := service.NewAccountService(srv, viper.New(), mockUserRepo, mockTokenService, mockPasswordService, mock_mailer.NewMockMailer(ctrl))
+
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old"), bcrypt.MinCost)
+	user := &model.User{UserId: "123", Password: string(hashedPassword)}
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(3)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(user, nil).Times(3)
+
+	// 旧密码错误
+	err := accountService.ChangePassword(ctx, "123", &v1.ChangePasswordRequest{OldPassword: "wrong", NewPassword: "N3w-passw0rd"})
+	assert.ErrorIs(t, err, v1.ErrPasswordIncorrect)
+
+	// 新密码不符合策略时不保存
+	policyErr := &service.PasswordPolicyError{Violations: []password.Violation{{Rule: password.RuleMinLength}}}
+	mockPasswordService.EXPECT().SetPassword(ctx, user, "1").Return(policyErr)
+	err = accountService.ChangePassword(ctx, "123", &v1.ChangePasswordRequest{OldPassword: "old", NewPassword: "1"})
+	assert.ErrorIs(t, err, v1.ErrPasswordPolicy)
+
+	mockPasswordService.EXPECT().SetPassword(ctx, user, "N3w-passw0rd").DoAndReturn(setPassword)
+	mockUserRepo.EXPECT().Update(ctx, user).Return(nil)
+	mockTokenService.EXPECT().LogoutAll(ctx, "123").Return(nil)
+	err = accountService.ChangePassword(ctx, "123", &v1.ChangePasswordRequest{OldPassword: "old", NewPassword: "N3w-passw0rd"})
+	assert.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("N3w-passw0rd")))
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := t.TempDir()
+	m := mailer.NewFileMailer(dir, "noreply@example.com", logger)
+
+	err := m.Send(context.Background(), &mailer.Message{
+		To:      []string{"xxx@gmail.com"},
+		Subject: "重置密码",
+		Body:    "hello",
+	})
+
+	assert.NoError(t, err)
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.Len(t, files, 1)
+	content, _ := os.ReadFile(files[0])
+	assert.Contains(t, string(content), "To: xxx@gmail.com\r\n")
+	assert.Contains(t, string(content), "Subject: =?UTF-8?q?")
+}
@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/test/mocks/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKeyService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApiKeyRepo := mock_repository.NewMockApiKeyRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	conf := viper.New()
+	conf.Set("security.api_key.max_expire", 365*24*time.Hour)
+	apiKeyService := service.NewApiKeyService(srv, conf, mockApiKeyRepo, mockUserRepo)
+
+	ctx := context.Background()
+	owned := &[]repository.LoginedUser{{PermissionId: 1, Path: "report"}, {PermissionId: 2, Path: "deploy"}}
+
+	// 权限不存在或不是api类型
+	mockApiKeyRepo.EXPECT().GetApiPermissions(ctx, []uint{1, 9}).Return([]model.Permission{{Id: 1}}, nil)
+	_, err := apiKeyService.Create(ctx, "123", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{1, 9, 1}})
+	assert.ErrorIs(t, err, v1.ErrApiKeyScopeInvalid)
+
+	// 超出用户自身拥有的权限
+	mockApiKeyRepo.EXPECT().GetApiPermissions(ctx, []uint{3}).Return([]model.Permission{{Id: 3}}, nil)
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(owned, nil)
+	_, err = apiKeyService.Create(ctx, "123", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{3}})
+	assert.ErrorIs(t, err, v1.ErrApiKeyScopeInvalid)
+
+	// 被用户其他角色拒绝的权限不能作为密钥权限
+	mockApiKeyRepo.EXPECT().GetApiPermissions(ctx, []uint{2}).Return([]model.Permission{{Id: 2}}, nil)
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(&[]repository.LoginedUser{
+		{PermissionId: 2, Path: "deploy", Effect: "allow"}, {PermissionId: 2, Path: "deploy", Effect: "deny"},
+	}, nil)
+	_, err = apiKeyService.Create(ctx, "123", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{2}})
+	assert.ErrorIs(t, err, v1.ErrApiKeyScopeInvalid)
+
+	// 有效期超过上限
+	mockApiKeyRepo.EXPECT().GetApiPermissions(ctx, []uint{1}).Return([]model.Permission{{Id: 1, Path: "report"}}, nil).Times(2)
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(owned, nil).Times(2)
+	_, err = apiKeyService.Create(ctx, "123", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{1}, ExpiresInDays: 400})
+	assert.ErrorIs(t, err, v1.ErrBadRequest)
+
+	var saved *model.ApiKey
+	mockApiKeyRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, key *model.ApiKey) error {
+		saved = key
+		return nil
+	})
+	data, err := apiKeyService.Create(ctx, "123", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{1}})
+	assert.NoError(t, err)
+	// 只保存哈希，完整密钥以展示前缀开头
+	assert.True(t, strings.HasPrefix(data.Key, saved.Prefix+"_"))
+	assert.Equal(t, jwt.HashToken(data.Key), saved.KeyHash)
+	assert.NotContains(t, saved.KeyHash, data.Key)
+	assert.Equal(t, "123", saved.UserId)
+	assert.Equal(t, "123", saved.CreatedBy)
+	// 默认有效期90天
+	assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), *saved.ExpiresAt, time.Minute)
+	assert.Equal(t, []v1.ApiKeyScope{{PermissionId: 1, Path: "report"}}, data.Scopes)
+}
+
+func TestApiKeyService_CreateForServiceAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApiKeyRepo := mock_repository.NewMockApiKeyRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	apiKeyService := service.NewApiKeyService(srv, viper.New(), mockApiKeyRepo, mockUserRepo)
+
+	ctx := context.Background()
+	// 不能为普通用户创建密钥
+	mockUserRepo.EXPECT().GetByID(ctx, "human").Return(&model.User{UserId: "human"}, nil)
+	_, err := apiKeyService.CreateForServiceAccount(ctx, "admin", "human", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{1}})
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "bot").Return(&model.User{UserId: "bot", ServiceAccount: true}, nil)
+	mockApiKeyRepo.EXPECT().GetApiPermissions(ctx, []uint{1}).Return([]model.Permission{{Id: 1}}, nil)
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "bot", "api", "").Return(&[]repository.LoginedUser{{PermissionId: 1}}, nil)
+	mockApiKeyRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, key *model.ApiKey) error {
+		assert.Equal(t, "bot", key.UserId)
+		assert.Equal(t, "admin", key.CreatedBy)
+		return nil
+	})
+	_, err = apiKeyService.CreateForServiceAccount(ctx, "admin", "bot", &v1.CreateApiKeyRequest{Name: "ci", Scopes: []uint{1}})
+	assert.NoError(t, err)
+}
+
+func TestApiKeyService_CreateServiceAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	apiKeyService := service.NewApiKeyService(srv, viper.New(), mock_repository.NewMockApiKeyRepository(ctrl), mockUserRepo)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(2)
+	mockUserRepo.EXPECT().GetRolesByLabels(ctx, []string{"ops", "admin"}).Return([]model.Role{{Id: 2, RoleLabel: "ops"}, {Id: 1, RoleLabel: "admin"}}, nil)
+	mockUserRepo.EXPECT().GetUserRoleIds(ctx, "operator").Return([]uint{2, 3}, nil)
+
+	// 不能授予自己没有的角色
+	_, err := apiKeyService.CreateServiceAccount(ctx, "operator", &v1.CreateServiceAccountRequest{Nickname: "bot", Roles: []string{"ops", "admin"}})
+	assert.ErrorIs(t, err, v1.ErrRoleNotHeld)
+
+	mockUserRepo.EXPECT().GetRolesByLabels(ctx, []string{"ops"}).Return([]model.Role{{Id: 2, RoleLabel: "ops"}}, nil)
+	mockUserRepo.EXPECT().GetUserRoleIds(ctx, "operator").Return([]uint{2, 3}, nil)
+	mockUserRepo.EXPECT().GetUserDefaultSeed(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, user *model.User) error {
+		assert.True(t, user.ServiceAccount)
+		assert.Equal(t, []model.Role{{Id: 2, RoleLabel: "ops"}}, user.Roles)
+		return nil
+	})
+	data, err := apiKeyService.CreateServiceAccount(ctx, "operator", &v1.CreateServiceAccountRequest{Nickname: "bot", Roles: []string{"ops"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "bot", data.Nickname)
+}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApiKeyRepo := mock_repository.NewMockApiKeyRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	apiKeyService := service.NewApiKeyService(srv, viper.New(), mockApiKeyRepo, mockUserRepo)
+
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-time.Second)
+
+	mockApiKeyRepo.EXPECT().GetByHash(ctx, jwt.HashToken("unknown")).Return(nil, v1.ErrNotFound)
+	mockApiKeyRepo.EXPECT().GetByHash(ctx, jwt.HashToken("revoked")).Return(&model.ApiKey{RevokedAt: &past}, nil)
+	mockApiKeyRepo.EXPECT().GetByHash(ctx, jwt.HashToken("expired")).Return(&model.ApiKey{ExpiresAt: &past}, nil)
+	for _, rawKey := range []string{"unknown", "revoked", "expired"} {
+		_, err := apiKeyService.Authenticate(ctx, rawKey, "10.0.0.1")
+		assert.ErrorIs(t, err, v1.ErrApiKeyInvalid, rawKey)
+	}
+
+	// 首次使用记录时间和IP，短时间内再次使用不重复记录
+	mockApiKeyRepo.EXPECT().GetByHash(ctx, jwt.HashToken("valid")).Return(&model.ApiKey{KeyId: "k1", UserId: "123"}, nil)
+	mockApiKeyRepo.EXPECT().GetByHash(ctx, jwt.HashToken("valid")).Return(&model.ApiKey{KeyId: "k1", UserId: "123", LastUsedAt: &recent}, nil)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil).Times(2)
+	mockApiKeyRepo.EXPECT().Touch(ctx, "k1", gomock.Any(), "10.0.0.1").Return(nil)
+	for i := 0; i < 2; i++ {
+		key, err := apiKeyService.Authenticate(ctx, "valid", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, "k1", key.KeyId)
+	}
+
+	// 所属用户已删除
+	mockApiKeyRepo.EXPECT().GetByHash(ctx, jwt.HashToken("orphan")).Return(&model.ApiKey{KeyId: "k2", UserId: "gone"}, nil)
+	mockUserRepo.EXPECT().GetByID(ctx, "gone").Return(nil, v1.ErrNotFound)
+	_, err := apiKeyService.Authenticate(ctx, "orphan", "10.0.0.1")
+	assert.ErrorIs(t, err, v1.ErrApiKeyInvalid)
+}
+
+func TestApiKeyService_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApiKeyRepo := mock_repository.NewMockApiKeyRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	apiKeyService := service.NewApiKeyService(srv, viper.New(), mockApiKeyRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	mockApiKeyRepo.EXPECT().GetByKeyId(ctx, "k1").Return(&model.ApiKey{KeyId: "k1", UserId: "123"}, nil).Times(2)
+
+	// 不能吊销其他用户的密钥
+	assert.ErrorIs(t, apiKeyService.Revoke(ctx, "456", "k1"), v1.ErrNotFound)
+
+	mockApiKeyRepo.EXPECT().Revoke(ctx, "k1", gomock.Any()).Return(nil)
+	assert.NoError(t, apiKeyService.Revoke(ctx, "123", "k1"))
+}
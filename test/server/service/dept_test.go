@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeptService_Tree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeptRepo := mock_repository.NewMockDeptRepository(ctrl)
+	srv := service.NewService(mock_repository.NewMockTransaction(ctrl), logger, sf, j)
+	deptService := service.NewDeptService(srv, mockDeptRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	mockDeptRepo.EXPECT().List(ctx).Return(nil, nil)
+	tree, err := deptService.Tree(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, tree)
+
+	// 保持查询结果中的顺序，上级部门不存在的部门作为根节点
+	mockDeptRepo.EXPECT().List(ctx).Return([]model.Dept{
+		{Id: 3, ParentId: 1, DeptName: "后端"},
+		{Id: 1, DeptName: "研发部"},
+		{Id: 2, ParentId: 1, DeptName: "前端"},
+		{Id: 4, ParentId: 9, DeptName: "孤立部门"},
+	}, nil)
+	tree, err = deptService.Tree(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, uint(1), tree[0].Id)
+	assert.Equal(t, uint(4), tree[1].Id)
+	assert.Equal(t, uint(3), tree[0].Children[0].Id)
+	assert.Equal(t, uint(2), tree[0].Children[1].Id)
+}
+
+func TestDeptService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeptRepo := mock_repository.NewMockDeptRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	deptService := service.NewDeptService(srv, mockDeptRepo, mockUserRepo)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	mockDeptRepo.EXPECT().GetParents(ctx).Return(map[uint]uint{1: 0}, nil).AnyTimes()
+
+	_, err := deptService.Create(ctx, &v1.CreateDeptRequest{ParentId: 9, DeptName: "研发部"})
+	assert.ErrorIs(t, err, v1.ErrDeptNotFound)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "gone").Return(nil, v1.ErrNotFound)
+	_, err = deptService.Create(ctx, &v1.CreateDeptRequest{ParentId: 1, DeptName: "前端", LeaderId: "gone"})
+	assert.ErrorIs(t, err, v1.ErrUserNotFound)
+
+	// 未指定状态时为启用
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil)
+	mockDeptRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	data, err := deptService.Create(ctx, &v1.CreateDeptRequest{ParentId: 1, DeptName: "前端", LeaderId: "123"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.DeptStatusEnabled, data.Status)
+	assert.Equal(t, "123", data.LeaderId)
+}
+
+func TestDeptService_UpdateParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeptRepo := mock_repository.NewMockDeptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	deptService := service.NewDeptService(srv, mockDeptRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	// 1 -> 2 -> 3
+	mockDeptRepo.EXPECT().GetParents(ctx).Return(map[uint]uint{1: 0, 2: 1, 3: 2, 4: 0}, nil).AnyTimes()
+	mockDeptRepo.EXPECT().GetById(ctx, uint(2)).Return(&model.Dept{Id: 2, ParentId: 1}, nil).AnyTimes()
+
+	// 不能移动到自身或下级部门下
+	for _, parentId := range []uint{2, 3} {
+		_, err := deptService.Update(ctx, 2, &v1.UpdateDeptRequest{ParentId: parentId, DeptName: "研发部"})
+		assert.ErrorIs(t, err, v1.ErrDeptCycle, parentId)
+	}
+
+	mockDeptRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	data, err := deptService.Update(ctx, 2, &v1.UpdateDeptRequest{ParentId: 4, DeptName: "研发部", Status: model.DeptStatusDisabled})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), data.ParentId)
+	assert.Equal(t, model.DeptStatusDisabled, data.Status)
+}
+
+func TestDeptService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeptRepo := mock_repository.NewMockDeptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	deptService := service.NewDeptService(srv, mockDeptRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	mockDeptRepo.EXPECT().GetById(ctx, uint(1)).Return(&model.Dept{Id: 1}, nil).Times(3)
+
+	// 还有下级部门或成员时不能删除
+	mockDeptRepo.EXPECT().CountChildren(ctx, uint(1)).Return(int64(1), nil)
+	mockDeptRepo.EXPECT().CountUsers(ctx, uint(1)).Return(int64(0), nil)
+	assert.ErrorIs(t, deptService.Delete(ctx, 1), v1.ErrDeptNotEmpty)
+	mockDeptRepo.EXPECT().CountChildren(ctx, uint(1)).Return(int64(0), nil).Times(2)
+	mockDeptRepo.EXPECT().CountUsers(ctx, uint(1)).Return(int64(2), nil)
+	assert.ErrorIs(t, deptService.Delete(ctx, 1), v1.ErrDeptNotEmpty)
+
+	mockDeptRepo.EXPECT().CountUsers(ctx, uint(1)).Return(int64(0), nil)
+	mockDeptRepo.EXPECT().Delete(ctx, uint(1)).Return(nil)
+	assert.NoError(t, deptService.Delete(ctx, 1))
+}
+
+func TestDeptService_Users(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeptRepo := mock_repository.NewMockDeptRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	deptService := service.NewDeptService(srv, mockDeptRepo, mockUserRepo)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+
+	// 停用的部门不能添加成员
+	mockDeptRepo.EXPECT().GetById(ctx, uint(2)).Return(&model.Dept{Id: 2, Status: model.DeptStatusDisabled}, nil)
+	assert.ErrorIs(t, deptService.AddUsers(ctx, 2, []string{"a"}), v1.ErrDeptDisabled)
+
+	// 任一用户不存在时全部不加入
+	mockDeptRepo.EXPECT().GetById(ctx, uint(1)).Return(&model.Dept{Id: 1, Status: model.DeptStatusEnabled}, nil).AnyTimes()
+	mockUserRepo.EXPECT().GetByIDs(ctx, []string{"a", "b"}).Return([]model.User{{UserId: "a"}}, nil)
+	assert.ErrorIs(t, deptService.AddUsers(ctx, 1, []string{"a", "b", "a"}), v1.ErrUserNotFound)
+
+	mockUserRepo.EXPECT().GetByIDs(ctx, []string{"a", "b"}).Return([]model.User{{UserId: "a"}, {UserId: "b"}}, nil)
+	mockDeptRepo.EXPECT().SetUsersDept(ctx, []string{"a", "b"}, uint(1)).Return(nil)
+	assert.NoError(t, deptService.AddUsers(ctx, 1, []string{"a", "b"}))
+
+	mockDeptRepo.EXPECT().RemoveUsers(ctx, uint(1), []string{"a"}).Return(nil)
+	assert.NoError(t, deptService.RemoveUsers(ctx, 1, []string{"a", "a"}))
+
+	// 包括下级部门的成员
+	mockDeptRepo.EXPECT().GetParents(ctx).Return(map[uint]uint{1: 0, 2: 1, 3: 2, 4: 0}, nil)
+	mockDeptRepo.EXPECT().ListUsers(ctx, []uint{1, 2, 3}, 0, 20).Return([]model.User{{UserId: "a", DeptId: 3}}, int64(1), nil)
+	data, err := deptService.ListUsers(ctx, 1, &v1.ListDeptUsersRequest{IncludeChildren: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), data.Total)
+	assert.Equal(t, uint(3), data.List[0].DeptId)
+}
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	jwt2 "admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonationService_Start(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockImpersonationRepo := mock_repository.NewMockImpersonationRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockUserService := mock_service.NewMockUserService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	impersonationService := service.NewImpersonationService(srv, viper.New(), mockImpersonationRepo, mockUserRepo, mockUserService, mock_service.NewMockTokenService(ctrl))
+
+	ctx := context.Background()
+	req := &v1.ImpersonateRequest{Reason: "ticket #1024"}
+	client := service.ClientInfo{Ip: "10.0.0.1", UserAgent: "curl"}
+	admin := &jwt2.MyCustomClaims{UserId: "admin"}
+
+	// 不能模拟自己，也不能在模拟中再次模拟
+	_, err := impersonationService.Start(ctx, admin, "admin", req, client)
+	assert.ErrorIs(t, err, v1.ErrImpersonationTarget)
+	_, err = impersonationService.Start(ctx, &jwt2.MyCustomClaims{UserId: "123", ActorId: "admin"}, "456", req, client)
+	assert.ErrorIs(t, err, v1.ErrImpersonationNotAllowed)
+
+	// 同样拥有模拟权限的用户不能被模拟
+	mockUserRepo.EXPECT().GetByID(ctx, "other-admin").Return(&model.User{UserId: "other-admin"}, nil)
+	impersonateRequest := service.ApiRequest{Method: "POST", Route: service.ImpersonateRoute}
+	mockUserService.EXPECT().CheckAPIAuthPermission(ctx, "other-admin", impersonateRequest).Return(true, nil)
+	_, err = impersonationService.Start(ctx, admin, "other-admin", req, client)
+	assert.ErrorIs(t, err, v1.ErrImpersonationTarget)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123", TenantId: 5}, nil)
+	mockUserService.EXPECT().CheckAPIAuthPermission(ctx, "123", impersonateRequest).Return(false, v1.ErrEmptyRecord)
+	var audit *model.Impersonation
+	mockImpersonationRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, impersonation *model.Impersonation) error {
+		audit = impersonation
+		return nil
+	})
+	data, err := impersonationService.Start(ctx, admin, "123", req, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", data.UserId)
+	assert.Equal(t, int64(30*60), data.ExpiresIn)
+
+	// 令牌以被模拟用户的身份访问，同时保留发起人，审计记录对应令牌的jti
+	claims, err := j.ParseToken(data.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", claims.UserId)
+	assert.Equal(t, "admin", claims.ActorId)
+	assert.Empty(t, claims.SessionId)
+	assert.Equal(t, claims.ID, audit.Jti)
+	assert.Equal(t, "admin", audit.ActorId)
+	assert.Equal(t, "123", audit.TargetUserId)
+	assert.Equal(t, uint(5), audit.TenantId)
+	assert.Equal(t, "ticket #1024", audit.Reason)
+	assert.Equal(t, "10.0.0.1", audit.Ip)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), audit.ExpiresAt, time.Minute)
+}
+
+func TestImpersonationService_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockImpersonationRepo := mock_repository.NewMockImpersonationRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	impersonationService := service.NewImpersonationService(srv, viper.New(), mockImpersonationRepo, mock_repository.NewMockUserRepository(ctrl), mock_service.NewMockUserService(ctrl), mockTokenService)
+
+	ctx := context.Background()
+	assert.ErrorIs(t, impersonationService.Stop(ctx, &jwt2.MyCustomClaims{UserId: "123"}), v1.ErrNotImpersonating)
+
+	claims := &jwt2.MyCustomClaims{UserId: "123", ActorId: "admin"}
+	claims.ID = "jti"
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockTokenService.EXPECT().Logout(ctx, claims).Return(nil)
+	mockImpersonationRepo.EXPECT().End(ctx, "jti", gomock.Any()).Return(nil)
+	assert.NoError(t, impersonationService.Stop(ctx, claims))
+}
+
+func TestTokenService_IsRevoked_Impersonation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tokenService := service.NewTokenService(srv, mock_repository.NewMockTokenRepository(ctrl), mockRevocationRepo, mock_repository.NewMockSessionRepository(ctrl), mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	claims := &jwt2.MyCustomClaims{UserId: "123", ActorId: "admin"}
+	claims.ID = "jti"
+
+	// 发起人注销全部会话后模拟令牌随之失效
+	mockRevocationRepo.EXPECT().IsRevoked(ctx, "jti", "", "123", gomock.Any()).Return(false, nil)
+	mockRevocationRepo.EXPECT().IsRevoked(ctx, "", "", "admin", gomock.Any()).Return(true, nil)
+	revoked, err := tokenService.IsRevoked(ctx, claims)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLoginConf() *viper.Viper {
+	conf := viper.New()
+	conf.Set("security.login.window", "15m")
+	conf.Set("security.login.account.max_failures", 3)
+	conf.Set("security.login.account.lock_duration", "10m")
+	conf.Set("security.login.account.backoff_base", "1s")
+	conf.Set("security.login.account.backoff_max", "4s")
+	return conf
+}
+
+func TestLoginAttemptService_Fail_Lock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoginAttemptRepo := mock_repository.NewMockLoginAttemptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	loginAttemptService := service.NewLoginAttemptService(srv, newLoginConf(), mockLoginAttemptRepo)
+
+	ctx := context.Background()
+	mockLoginAttemptRepo.EXPECT().IncrFailures(ctx, "ip:127.0.0.1", gomock.Any()).Return(3, nil)
+	mockLoginAttemptRepo.EXPECT().IncrFailures(ctx, "account:123", gomock.Any()).Return(3, nil)
+	mockLoginAttemptRepo.EXPECT().Lock(ctx, "account:123", gomock.Any()).DoAndReturn(func(ctx context.Context, subject string, until time.Time) error {
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), until, time.Second)
+		return nil
+	})
+
+	err := loginAttemptService.Fail(ctx, "123", "127.0.0.1")
+
+	assert.NoError(t, err)
+}
+
+func TestLoginAttemptService_Check_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoginAttemptRepo := mock_repository.NewMockLoginAttemptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	loginAttemptService := service.NewLoginAttemptService(srv, newLoginConf(), mockLoginAttemptRepo)
+
+	ctx := context.Background()
+	lockedUntil := time.Now().Add(5 * time.Minute)
+	mockLoginAttemptRepo.EXPECT().Get(ctx, "account:123").Return(&model.LoginAttempt{
+		Subject:      "account:123",
+		Failures:     3,
+		LastFailedAt: time.Now(),
+		LockedUntil:  &lockedUntil,
+	}, nil)
+
+	err := loginAttemptService.Check(ctx, "123", "")
+
+	assert.ErrorIs(t, err, v1.ErrAccountLocked)
+	var retryErr *service.RetryAfterError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.InDelta(t, (5 * time.Minute).Seconds(), retryErr.RetryAfter.Seconds(), 1)
+}
+
+func TestLoginAttemptService_Check_Backoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoginAttemptRepo := mock_repository.NewMockLoginAttemptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	loginAttemptService := service.NewLoginAttemptService(srv, newLoginConf(), mockLoginAttemptRepo)
+
+	ctx := context.Background()
+	gomock.InOrder(
+		// 失败2次后需等待2s
+		mockLoginAttemptRepo.EXPECT().Get(ctx, "account:123").Return(&model.LoginAttempt{
+			Failures:     2,
+			LastFailedAt: time.Now().Add(-time.Second),
+		}, nil),
+		mockLoginAttemptRepo.EXPECT().Get(ctx, "account:123").Return(&model.LoginAttempt{
+			Failures:     2,
+			LastFailedAt: time.Now().Add(-3 * time.Second),
+		}, nil),
+	)
+
+	assert.ErrorIs(t, loginAttemptService.Check(ctx, "123", ""), v1.ErrTooManyLoginAttempts)
+	assert.NoError(t, loginAttemptService.Check(ctx, "123", ""))
+}
+
+func TestLoginAttemptService_Check_LockExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoginAttemptRepo := mock_repository.NewMockLoginAttemptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	loginAttemptService := service.NewLoginAttemptService(srv, newLoginConf(), mockLoginAttemptRepo)
+
+	ctx := context.Background()
+	lockedUntil := time.Now().Add(-time.Second)
+	mockLoginAttemptRepo.EXPECT().Get(ctx, "account:123").Return(&model.LoginAttempt{
+		Failures:     3,
+		LastFailedAt: time.Now().Add(-10 * time.Minute),
+		LockedUntil:  &lockedUntil,
+	}, nil)
+	mockLoginAttemptRepo.EXPECT().Delete(ctx, "account:123").Return(nil)
+
+	assert.NoError(t, loginAttemptService.Check(ctx, "123", ""))
+}
@@ -0,0 +1,327 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	jwt2 "admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/pkg/oidc"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIdP 本地模拟的身份提供方，实现发现、公钥和令牌端点
+type mockIdP struct {
+	*httptest.Server
+	t         *testing.T
+	key       *rsa.PrivateKey
+	challenge string                 // 授权请求中的PKCE质询
+	claims    map[string]interface{} // 令牌端点签发的ID令牌声明，nonce和iss等由测试填写
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	idp := &mockIdP{t: t, key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "idp-key",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, _ := r.BasicAuth()
+		if clientId != "admin" || clientSecret != "secret" || r.PostFormValue("code") != "good-code" ||
+			oidc.S256Challenge(r.PostFormValue("code_verifier")) != idp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims(idp.claims))
+		token.Header["kid"] = "idp-key"
+		idToken, err := token.SignedString(key)
+		assert.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "token_type": "Bearer", "id_token": idToken})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func newOIDCConf(issuer string) *viper.Viper {
+	conf := viper.New()
+	conf.Set("security.oidc.enabled", true)
+	conf.Set("security.oidc.issuer", issuer)
+	conf.Set("security.oidc.client_id", "admin")
+	conf.Set("security.oidc.client_secret", "secret")
+	conf.Set("security.oidc.redirect_url", "http://127.0.0.1:3000/oidc/callback")
+	conf.Set("security.oidc.link_by_email", true)
+	conf.Set("security.oidc.role_mapping", []map[string]string{
+		{"group": "admins", "role": "admin"},
+		{"group": "ops", "role": "ops"},
+	})
+	return conf
+}
+
+// authorize 发起登录并模拟浏览器跳转到身份提供方，返回保存的state
+func authorize(t *testing.T, ctx context.Context, oidcService service.OIDCService, mockOIDCRepo *mock_repository.MockOIDCRepository, idp *mockIdP) *model.OIDCState {
+	var saved *model.OIDCState
+	mockOIDCRepo.EXPECT().CreateState(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, state *model.OIDCState) error {
+		saved = state
+		return nil
+	})
+	data, err := oidcService.AuthorizationURL(ctx)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	u, err := url.Parse(data.AuthorizationUrl)
+	assert.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	query := u.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, saved.State, query.Get("state"))
+	assert.Equal(t, saved.Nonce, query.Get("nonce"))
+	// 校验码本身不能出现在授权地址中
+	assert.Equal(t, oidc.S256Challenge(saved.CodeVerifier), query.Get("code_challenge"))
+	assert.NotContains(t, data.AuthorizationUrl, saved.CodeVerifier)
+	idp.challenge = query.Get("code_challenge")
+	return saved
+}
+
+func (idp *mockIdP) idTokenClaims(nonce string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":            idp.URL,
+		"aud":            "admin",
+		"sub":            "ext-1",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "sso@example.com",
+		"email_verified": true,
+		"name":           "SSO User",
+		"groups":         []string{"admins", "staff"},
+	}
+}
+
+func TestOIDCService_Callback_NewUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idp := newMockIdP(t)
+	mockOIDCRepo := mock_repository.NewMockOIDCRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	conf := newOIDCConf(idp.URL)
+	mockPermissionCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTwoFactorService := mock_service.NewMockTwoFactorService(ctrl)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	oidcService := service.NewOIDCService(srv, conf, oidc.NewProvider(conf), mockOIDCRepo, mockUserRepo, mockPermissionCacheRepo, mockTokenService,
+		mockTwoFactorService, mockLoginAttemptService)
+
+	ctx := context.Background()
+	state := authorize(t, ctx, oidcService, mockOIDCRepo, idp)
+	idp.claims = idp.idTokenClaims(state.Nonce)
+
+	var created *model.User
+	mockOIDCRepo.EXPECT().TakeState(ctx, state.State).Return(state, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockOIDCRepo.EXPECT().GetIdentity(ctx, "oidc", "ext-1").Return(nil, v1.ErrNotFound)
+	mockUserRepo.EXPECT().GetByEmail(ctx, "sso@example.com").Return(nil, nil)
+	mockUserRepo.EXPECT().GetUserDefaultSeed(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, user *model.User) error {
+		created = user
+		assert.Equal(t, "SSO User", user.Nickname)
+		assert.NotNil(t, user.EmailVerifiedAt)
+		return nil
+	})
+	mockOIDCRepo.EXPECT().CreateIdentity(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, identity *model.UserIdentity) error {
+		assert.Equal(t, created.UserId, identity.UserId)
+		assert.Equal(t, "ext-1", identity.Subject)
+		return nil
+	})
+	mockOIDCRepo.EXPECT().SyncRoles(ctx, gomock.Any(), []string{"admin", "ops"}, []string{"admin"}).Return(nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "").Return(nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, gomock.Any(), "").Return(nil)
+	mockPermissionCacheRepo.EXPECT().Invalidate(ctx, gomock.Any())
+	mockTwoFactorService.EXPECT().Status(ctx, gomock.Any()).Return(false, false, nil)
+	mockLoginAttemptService.EXPECT().Succeed(ctx, gomock.Any()).Return(nil)
+	mockTokenService.EXPECT().IssueTokens(ctx, gomock.Any(), gomock.Any()).Return(&v1.LoginResponseData{AccessToken: "access"}, nil)
+
+	data, err := oidcService.Callback(ctx, &v1.OIDCCallbackRequest{Code: "good-code", State: state.State}, service.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, "access", data.AccessToken)
+}
+
+func TestOIDCService_Callback_LinkedUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idp := newMockIdP(t)
+	mockOIDCRepo := mock_repository.NewMockOIDCRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	conf := newOIDCConf(idp.URL)
+	mockPermissionCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTwoFactorService := mock_service.NewMockTwoFactorService(ctrl)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	oidcService := service.NewOIDCService(srv, conf, oidc.NewProvider(conf), mockOIDCRepo, mockUserRepo, mockPermissionCacheRepo, mockTokenService,
+		mockTwoFactorService, mockLoginAttemptService)
+
+	ctx := context.Background()
+	state := authorize(t, ctx, oidcService, mockOIDCRepo, idp)
+	idp.claims = idp.idTokenClaims(state.Nonce)
+	idp.claims["groups"] = "ops"
+
+	mockOIDCRepo.EXPECT().TakeState(ctx, state.State).Return(state, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockOIDCRepo.EXPECT().GetIdentity(ctx, "oidc", "ext-1").Return(&model.UserIdentity{UserId: "123"}, nil)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil)
+	mockOIDCRepo.EXPECT().SyncRoles(ctx, "123", []string{"admin", "ops"}, []string{"ops"}).Return(nil)
+	// 同步角色后清除该用户的权限缓存
+	mockPermissionCacheRepo.EXPECT().Invalidate(ctx, "123")
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "").Return(nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, "123", "").Return(nil)
+	mockTwoFactorService.EXPECT().Status(ctx, "123").Return(false, false, nil)
+	mockLoginAttemptService.EXPECT().Succeed(ctx, "123").Return(nil)
+	mockTokenService.EXPECT().IssueTokens(ctx, "123", gomock.Any()).Return(&v1.LoginResponseData{}, nil)
+
+	_, err := oidcService.Callback(ctx, &v1.OIDCCallbackRequest{Code: "good-code", State: state.State}, service.ClientInfo{})
+	assert.NoError(t, err)
+}
+
+func TestOIDCService_Callback_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idp := newMockIdP(t)
+	mockOIDCRepo := mock_repository.NewMockOIDCRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	conf := newOIDCConf(idp.URL)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	oidcService := service.NewOIDCService(srv, conf, oidc.NewProvider(conf), mockOIDCRepo,
+		mock_repository.NewMockUserRepository(ctrl), mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl),
+		mock_service.NewMockTwoFactorService(ctrl), mockLoginAttemptService)
+
+	ctx := context.Background()
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "").Return(nil).AnyTimes()
+	state := authorize(t, ctx, oidcService, mockOIDCRepo, idp)
+
+	// state不存在或已使用
+	mockOIDCRepo.EXPECT().TakeState(ctx, "unknown").Return(nil, v1.ErrNotFound)
+	_, err := oidcService.Callback(ctx, &v1.OIDCCallbackRequest{Code: "good-code", State: "unknown"}, service.ClientInfo{})
+	assert.ErrorIs(t, err, v1.ErrOIDCStateInvalid)
+
+	tests := []struct {
+		name   string
+		code   string
+		modify func(claims map[string]interface{})
+	}{
+		{"wrong code", "bad-code", func(claims map[string]interface{}) {}},
+		{"nonce mismatch", "good-code", func(claims map[string]interface{}) { claims["nonce"] = "other" }},
+		{"wrong audience", "good-code", func(claims map[string]interface{}) { claims["aud"] = "someone-else" }},
+		{"wrong issuer", "good-code", func(claims map[string]interface{}) { claims["iss"] = "https://evil.example.com" }},
+		{"expired", "good-code", func(claims map[string]interface{}) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+	}
+	for _, tt := range tests {
+		idp.claims = idp.idTokenClaims(state.Nonce)
+		tt.modify(idp.claims)
+		mockOIDCRepo.EXPECT().TakeState(ctx, state.State).Return(state, nil)
+		_, err = oidcService.Callback(ctx, &v1.OIDCCallbackRequest{Code: tt.code, State: state.State}, service.ClientInfo{})
+		assert.ErrorIs(t, err, v1.ErrOIDCLoginFailed, tt.name)
+	}
+}
+
+func TestOIDCService_Callback_TwoFactorRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idp := newMockIdP(t)
+	mockOIDCRepo := mock_repository.NewMockOIDCRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	conf := newOIDCConf(idp.URL)
+	mockPermissionCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTwoFactorService := mock_service.NewMockTwoFactorService(ctrl)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	// 需要两步验证或账号被锁定时不能签发令牌
+	oidcService := service.NewOIDCService(srv, conf, oidc.NewProvider(conf), mockOIDCRepo, mockUserRepo, mockPermissionCacheRepo,
+		mock_service.NewMockTokenService(ctrl), mockTwoFactorService, mockLoginAttemptService)
+
+	ctx := context.Background()
+	client := service.ClientInfo{Ip: "10.0.0.1"}
+	login := func() (*v1.LoginResponseData, error) {
+		state := authorize(t, ctx, oidcService, mockOIDCRepo, idp)
+		idp.claims = idp.idTokenClaims(state.Nonce)
+		mockOIDCRepo.EXPECT().TakeState(ctx, state.State).Return(state, nil)
+		return oidcService.Callback(ctx, &v1.OIDCCallbackRequest{Code: "good-code", State: state.State}, client)
+	}
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "10.0.0.1").Return(nil).Times(3)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(3)
+	mockOIDCRepo.EXPECT().GetIdentity(ctx, "oidc", "ext-1").Return(&model.UserIdentity{UserId: "123"}, nil).Times(3)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil).Times(3)
+
+	// 角色要求两步验证但尚未绑定
+	mockLoginAttemptService.EXPECT().Check(ctx, "123", "").Return(nil).Times(2)
+	mockOIDCRepo.EXPECT().SyncRoles(ctx, "123", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockPermissionCacheRepo.EXPECT().Invalidate(ctx, "123").Times(2)
+	mockTwoFactorService.EXPECT().Status(ctx, "123").Return(false, true, nil)
+	data, err := login()
+	assert.NoError(t, err)
+	assert.True(t, data.TwoFactorSetupRequired)
+	assert.Empty(t, data.AccessToken)
+	claims, err := j.ParsePurposeToken(data.ChallengeToken, jwt2.PurposeTwoFactorSetup)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", claims.UserId)
+
+	// 已启用两步验证
+	mockTwoFactorService.EXPECT().Status(ctx, "123").Return(true, true, nil)
+	data, err = login()
+	assert.NoError(t, err)
+	assert.True(t, data.TwoFactorRequired)
+	assert.NotEmpty(t, data.ChallengeToken)
+	assert.Empty(t, data.AccessToken)
+
+	// 账号被锁定
+	mockLoginAttemptService.EXPECT().Check(ctx, "123", "").Return(&service.RetryAfterError{Err: v1.ErrAccountLocked, RetryAfter: time.Minute})
+	_, err = login()
+	assert.ErrorIs(t, err, v1.ErrAccountLocked)
+}
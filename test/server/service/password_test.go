@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/password"
+	"admin-webrtc-go/test/mocks/repository"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// setPassword 作为PasswordService.SetPassword的mock实现，只写入哈希
+func setPassword(ctx context.Context, user *model.User, plain string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.MinCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+	return nil
+}
+
+func violatedRules(t *testing.T, err error) []string {
+	var policyErr *service.PasswordPolicyError
+	if !assert.True(t, errors.As(err, &policyErr)) {
+		return nil
+	}
+	rules := make([]string, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	conf := viper.New()
+	conf.Set("security.password.require_upper", true)
+	conf.Set("security.password.require_symbol", true)
+	policy := password.NewPolicy(conf)
+
+	tests := []struct {
+		password string
+		rules    []string
+	}{
+		{"1", []string{password.RuleMinLength, password.RuleUpper, password.RuleLower, password.RuleSymbol}},
+		{"abcdefgh", []string{password.RuleUpper, password.RuleDigit, password.RuleSymbol}},
+		{"P@ssw0rd", []string{password.RuleCommon}},
+		{"Correct-h0rse", nil},
+		{"Correct-h0rse" + strings.Repeat("x", 72), []string{password.RuleMaxLength}},
+	}
+	for _, tt := range tests {
+		var rules []string
+		for _, v := range policy.Validate(tt.password) {
+			rules = append(rules, v.Rule)
+		}
+		assert.Equal(t, tt.rules, rules, tt.password)
+	}
+}
+
+func TestPasswordService_Validate_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_repository.NewMockPasswordHistoryRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	passwordService := service.NewPasswordService(srv, viper.New(), mockHistoryRepo)
+
+	ctx := context.Background()
+	current, _ := bcrypt.GenerateFromPassword([]byte("Current-pass1"), bcrypt.MinCost)
+	previous, _ := bcrypt.GenerateFromPassword([]byte("Previous-pass1"), bcrypt.MinCost)
+	user := &model.User{UserId: "123", Password: string(current)}
+
+	// 与当前密码相同时不需要查询历史
+	err := passwordService.Validate(ctx, user, "Current-pass1")
+	assert.ErrorIs(t, err, v1.ErrPasswordPolicy)
+	assert.Equal(t, []string{password.RuleHistory}, violatedRules(t, err))
+
+	mockHistoryRepo.EXPECT().ListRecent(ctx, "123", 5).Return([]*model.PasswordHistory{{PasswordHash: string(previous)}}, nil).Times(2)
+	err = passwordService.Validate(ctx, user, "Previous-pass1")
+	assert.Equal(t, []string{password.RuleHistory}, violatedRules(t, err))
+	assert.NoError(t, passwordService.Validate(ctx, user, "Brand-new-pass1"))
+}
+
+func TestPasswordService_SetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistoryRepo := mock_repository.NewMockPasswordHistoryRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	conf := viper.New()
+	conf.Set("security.password.history", 3)
+	passwordService := service.NewPasswordService(srv, conf, mockHistoryRepo)
+
+	ctx := context.Background()
+	user := &model.User{UserId: "123"}
+
+	// 不符合策略时不修改密码
+	assert.ErrorIs(t, passwordService.SetPassword(ctx, user, "123456"), v1.ErrPasswordPolicy)
+	assert.Empty(t, user.Password)
+
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockHistoryRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, history *model.PasswordHistory) error {
+		assert.Equal(t, "123", history.UserId)
+		assert.Equal(t, user.Password, history.PasswordHash)
+		return nil
+	})
+	mockHistoryRepo.EXPECT().Prune(ctx, "123", 3).Return(nil)
+
+	assert.NoError(t, passwordService.SetPassword(ctx, user, "Brand-new-pass1"))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("Brand-new-pass1")))
+}
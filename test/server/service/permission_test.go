@@ -0,0 +1,253 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// 1 系统管理(menu)
+// ├── 2 用户管理(menu)
+// │   └── 4 新增用户(button)
+// └── 3 角色管理(menu)
+// 5 用户接口(api)
+func permissionFixture() []model.Permission {
+	return []model.Permission{
+		{Id: 1, PermissionName: "系统管理", PermissionType: "menu", Level: 1, Sort: "0001"},
+		{Id: 2, PermissionName: "用户管理", PermissionType: "menu", ParentId: 1, Level: 2, Sort: "0001"},
+		{Id: 3, PermissionName: "角色管理", PermissionType: "menu", ParentId: 1, Level: 2, Sort: "0002"},
+		{Id: 4, PermissionName: "新增用户", PermissionType: "button", ParentId: 2, Level: 3, Sort: "0001"},
+		{Id: 5, PermissionName: "用户接口", PermissionType: "api", Level: 1, Sort: "0002", Path: "/v1/users/*"},
+	}
+}
+
+func newPermissionService(ctrl *gomock.Controller) (service.PermissionService, *mock_repository.MockPermissionRepository, *mock_repository.MockPermissionCacheRepository) {
+	mockPermissionRepo := mock_repository.NewMockPermissionRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	mockTm.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	srv := service.NewService(mockTm, logger, sf, j)
+	return service.NewPermissionService(srv, mockPermissionRepo, mockCache), mockPermissionRepo, mockCache
+}
+
+func TestPermissionService_Tree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, _ := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	permissions := permissionFixture()
+	// 倒序返回，结果仍按sort排列
+	for i, k := 0, len(permissions)-1; i < k; i, k = i+1, k-1 {
+		permissions[i], permissions[k] = permissions[k], permissions[i]
+	}
+	mockPermissionRepo.EXPECT().List(ctx).Return(permissions, nil)
+
+	tree, err := permissionService.Tree(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "1", tree[0].Key)
+	assert.Equal(t, "api", tree[1].PermissionType)
+	assert.Equal(t, "/v1/users/*", tree[1].Path)
+	assert.Equal(t, []string{"2", "3"}, []string{tree[0].Children[0].Key, tree[0].Children[1].Key})
+	assert.Equal(t, "4", tree[0].Children[0].Children[0].Key)
+}
+
+func TestPermissionService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, _ := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	// api类型必须有path，method必须是HTTP方法
+	_, err := permissionService.Create(ctx, &v1.CreatePermissionRequest{PermissionName: "x", PermissionType: "api"})
+	assert.ErrorIs(t, err, v1.ErrPermissionInvalid)
+	_, err = permissionService.Create(ctx, &v1.CreatePermissionRequest{PermissionName: "x", PermissionType: "api", Path: "/v1/roles", Method: "GET,FETCH"})
+	assert.ErrorIs(t, err, v1.ErrPermissionInvalid)
+
+	// 父节点不存在或不是菜单
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(9)).Return(nil, v1.ErrNotFound)
+	_, err = permissionService.Create(ctx, &v1.CreatePermissionRequest{PermissionName: "x", PermissionType: "button", ParentId: 9})
+	assert.ErrorIs(t, err, v1.ErrPermissionParent)
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(4)).Return(&permissionFixture()[3], nil)
+	_, err = permissionService.Create(ctx, &v1.CreatePermissionRequest{PermissionName: "x", PermissionType: "button", ParentId: 4})
+	assert.ErrorIs(t, err, v1.ErrPermissionParent)
+
+	// 层级根据父节点计算，未指定sort时排在最后
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(2)).Return(&permissionFixture()[1], nil)
+	mockPermissionRepo.EXPECT().CountChildren(ctx, uint(2)).Return(int64(1), nil)
+	mockPermissionRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, permission *model.Permission) error {
+		assert.Equal(t, 3, permission.Level)
+		assert.Equal(t, "0002", permission.Sort)
+		assert.NotEmpty(t, permission.CreatedAt)
+		permission.Id = 6
+		return nil
+	})
+	data, err := permissionService.Create(ctx, &v1.CreatePermissionRequest{PermissionName: "删除用户", PermissionType: "api", ParentId: 2, Path: "/v1/users/:userId", Method: "delete"})
+	assert.NoError(t, err)
+	assert.Equal(t, "6", data.Key)
+	assert.Equal(t, "2", data.ParentId)
+}
+
+func TestPermissionService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, mockCache := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	// 有子节点时不能删除
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(2)).Return(&permissionFixture()[1], nil)
+	mockPermissionRepo.EXPECT().CountChildren(ctx, uint(2)).Return(int64(1), nil)
+	assert.ErrorIs(t, permissionService.Delete(ctx, 2), v1.ErrPermissionChildren)
+
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(4)).Return(&permissionFixture()[3], nil)
+	mockPermissionRepo.EXPECT().CountChildren(ctx, uint(4)).Return(int64(0), nil)
+	mockPermissionRepo.EXPECT().Delete(ctx, uint(4)).Return(nil)
+	mockCache.EXPECT().InvalidateAll(ctx)
+	assert.NoError(t, permissionService.Delete(ctx, 4))
+}
+
+func TestPermissionService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, mockCache := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	// 有子节点的菜单不能改为其他类型
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(2)).Return(&permissionFixture()[1], nil)
+	mockPermissionRepo.EXPECT().CountChildren(ctx, uint(2)).Return(int64(1), nil)
+	_, err := permissionService.Update(ctx, 2, &v1.UpdatePermissionRequest{PermissionName: "用户管理", PermissionType: "button"})
+	assert.ErrorIs(t, err, v1.ErrPermissionChildren)
+
+	mockPermissionRepo.EXPECT().GetById(ctx, uint(5)).Return(&permissionFixture()[4], nil)
+	mockPermissionRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, permission *model.Permission) error {
+		assert.Equal(t, "GET", permission.Method)
+		// 未指定sort时保持原值
+		assert.Equal(t, "0002", permission.Sort)
+		return nil
+	})
+	mockCache.EXPECT().InvalidateAll(ctx)
+	_, err = permissionService.Update(ctx, 5, &v1.UpdatePermissionRequest{PermissionName: "用户接口", PermissionType: "api", Path: "/v1/users/*", Method: "GET"})
+	assert.NoError(t, err)
+}
+
+func TestPermissionService_Move(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, _ := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	mockPermissionRepo.EXPECT().List(ctx).DoAndReturn(func(ctx context.Context) ([]model.Permission, error) {
+		return permissionFixture(), nil
+	}).AnyTimes()
+
+	// 不能移动到自身或子孙节点下
+	assert.ErrorIs(t, permissionService.Move(ctx, 1, &v1.MovePermissionRequest{ParentId: 1}), v1.ErrPermissionCycle)
+	assert.ErrorIs(t, permissionService.Move(ctx, 1, &v1.MovePermissionRequest{ParentId: 2}), v1.ErrPermissionCycle)
+	// 父节点不存在或不是菜单
+	assert.ErrorIs(t, permissionService.Move(ctx, 3, &v1.MovePermissionRequest{ParentId: 9}), v1.ErrPermissionParent)
+	assert.ErrorIs(t, permissionService.Move(ctx, 3, &v1.MovePermissionRequest{ParentId: 4}), v1.ErrPermissionParent)
+	assert.ErrorIs(t, permissionService.Move(ctx, 9, &v1.MovePermissionRequest{}), v1.ErrNotFound)
+
+	// 子孙节点的层级随之调整
+	mockPermissionRepo.EXPECT().Move(ctx, uint(2), uint(3), "0001").Return(nil)
+	mockPermissionRepo.EXPECT().ShiftLevel(ctx, []uint{2, 4}, 1).Return(nil)
+	assert.NoError(t, permissionService.Move(ctx, 2, &v1.MovePermissionRequest{ParentId: 3}))
+
+	mockPermissionRepo.EXPECT().Move(ctx, uint(2), uint(0), "0003").Return(nil)
+	mockPermissionRepo.EXPECT().ShiftLevel(ctx, []uint{2, 4}, -1).Return(nil)
+	assert.NoError(t, permissionService.Move(ctx, 2, &v1.MovePermissionRequest{}))
+}
+
+func TestPermissionService_Sort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, _ := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	mockPermissionRepo.EXPECT().List(ctx).Return(permissionFixture(), nil).AnyTimes()
+
+	// 必须正好是全部同级节点
+	for _, ids := range [][]uint{{3}, {3, 4}, {3, 3}, {3, 2, 4}} {
+		assert.ErrorIs(t, permissionService.Sort(ctx, &v1.SortPermissionsRequest{ParentId: 1, Ids: ids}), v1.ErrBadRequest, ids)
+	}
+
+	mockPermissionRepo.EXPECT().UpdateSort(ctx, uint(3), "0001").Return(nil)
+	mockPermissionRepo.EXPECT().UpdateSort(ctx, uint(2), "0002").Return(nil)
+	assert.NoError(t, permissionService.Sort(ctx, &v1.SortPermissionsRequest{ParentId: 1, Ids: []uint{3, 2}}))
+}
+
+func TestPermissionService_SyncRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionService, mockPermissionRepo, _ := newPermissionService(ctrl)
+
+	ctx := context.Background()
+	permissions := append(permissionFixture(),
+		model.Permission{Id: 6, PermissionName: "GET /v1/roles", PermissionType: "api", Level: 1, Sort: "0003", Path: "/v1/roles", Method: "GET"},
+		model.Permission{Id: 7, PermissionName: "部门接口", PermissionType: "api", Level: 1, Sort: "0004", Path: "/v1/depts/*", Method: "all"},
+		model.Permission{Id: 8, PermissionName: "旧版接口", PermissionType: "api", Level: 1, Sort: "0005", Path: "admin"},
+	)
+	routes := []service.RouteInfo{
+		{Method: "GET", Path: "/v1/roles"},
+		{Method: "POST", Path: "/v1/roles"},
+		{Method: "GET", Path: "/v1/users/:userId"},
+	}
+	names := map[service.RouteInfo]string{
+		{Method: "GET", Path: "/v1/roles"}:  "分页查询角色",
+		{Method: "POST", Path: "/v1/roles"}: "新增角色",
+	}
+
+	// 已有的权限只更新名称，没有注释的路由按方法和路由命名，部门接口不匹配任何路由
+	mockPermissionRepo.EXPECT().List(ctx).Return(permissions, nil)
+	mockPermissionRepo.EXPECT().CountChildren(ctx, uint(0)).Return(int64(5), nil)
+	mockPermissionRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, permission *model.Permission) error {
+		assert.Equal(t, uint(6), permission.Id)
+		assert.Equal(t, "分页查询角色", permission.PermissionName)
+		return nil
+	})
+	var created []model.Permission
+	mockPermissionRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, permission *model.Permission) error {
+		created = append(created, *permission)
+		return nil
+	}).Times(2)
+	result, err := permissionService.SyncRoutes(ctx, routes, names)
+	assert.NoError(t, err)
+	assert.Len(t, result.Renamed, 1)
+	assert.Equal(t, created, result.Created)
+	assert.Equal(t, "新增角色", created[0].PermissionName)
+	assert.Equal(t, "0006", created[0].Sort)
+	assert.Equal(t, "GET /v1/users/:userId", created[1].PermissionName)
+	assert.Equal(t, service.PermissionTypeApi, created[1].PermissionType)
+	assert.Len(t, result.Stale, 1)
+	assert.Equal(t, uint(7), result.Stale[0].Id)
+}
+
+func TestSwaggerRouteNames(t *testing.T) {
+	doc := `{"basePath": "/v1", "paths": {
+		"/users/{userId}": {"get": {"summary": "获取用户"}, "delete": {}},
+		"/admin/rbac/cache": {"get": {"summary": "查看权限缓存"}},
+		"/user/rbac/cache": {"get": {"summary": "重复的旧版路由"}},
+		"/removed": {"get": {"summary": "已删除的路由"}}
+	}}`
+	routes := []service.RouteInfo{
+		{Method: "GET", Path: "/v1/users/:userId"},
+		{Method: "DELETE", Path: "/v1/users/:userId"},
+		{Method: "GET", Path: "/v1/:api/rbac/cache"},
+	}
+	names, err := service.SwaggerRouteNames(doc, routes)
+	assert.NoError(t, err)
+	assert.Equal(t, map[service.RouteInfo]string{
+		{Method: "GET", Path: "/v1/users/:userId"}:   "获取用户",
+		{Method: "GET", Path: "/v1/:api/rbac/cache"}: "查看权限缓存",
+	}, names)
+
+	_, err = service.SwaggerRouteNames("{", routes)
+	assert.Error(t, err)
+}
@@ -0,0 +1,313 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/repository"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchApiPermission(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		method string
+		req    service.ApiRequest
+		want   bool
+	}{
+		{"exact route", "/v1/users/:userId", "GET", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId"}, true},
+		{"method mismatch", "/v1/users/:userId", "GET", service.ApiRequest{Method: "DELETE", Route: "/v1/users/:userId"}, false},
+		{"method case insensitive", "/v1/users/:userId", "get", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId"}, true},
+		{"method list", "/v1/users/:userId", "GET, PUT", service.ApiRequest{Method: "PUT", Route: "/v1/users/:userId"}, true},
+		{"method all", "/v1/users/:userId", "all", service.ApiRequest{Method: "DELETE", Route: "/v1/users/:userId"}, true},
+		{"empty method", "/v1/users/:userId", "", service.ApiRequest{Method: "PATCH", Route: "/v1/users/:userId"}, true},
+		{"trailing wildcard", "/v1/users/*", "all", service.ApiRequest{Method: "POST", Route: "/v1/users/:userId/impersonate"}, true},
+		{"trailing wildcard needs a segment", "/v1/users/*", "all", service.ApiRequest{Method: "GET", Route: "/v1/users"}, false},
+		{"middle wildcard", "/v1/*/:userId", "all", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId"}, true},
+		{"middle wildcard single segment", "/v1/*/:userId", "all", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId/sessions"}, false},
+		{"longer route", "/v1/users/:userId", "all", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId/sessions"}, false},
+		{"other prefix", "/v1/users/*", "all", service.ApiRequest{Method: "GET", Route: "/v1/roles/1"}, false},
+		{"legacy api", "admin", "", service.ApiRequest{Method: "GET", Route: "/v1/:api/sessions/:sessionId", Api: "admin"}, true},
+		{"legacy api mismatch", "admin", "", service.ApiRequest{Method: "GET", Route: "/v1/:api/sessions/:sessionId", Api: "report"}, false},
+		{"legacy api on plain route", "admin", "", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId"}, false},
+		{"legacy api with method", "admin", "GET", service.ApiRequest{Method: "DELETE", Route: "/v1/:api/sessions/:sessionId", Api: "admin"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, service.MatchApiPermission(tt.path, tt.method, tt.req))
+		})
+	}
+}
+
+func TestUserService_CheckAPIAuthPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockPermissionCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	userService := service.NewUserService(srv, mockUserRepo, mockPermissionCacheRepo, mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	// 缓存未命中时查库，同一权限来自多个角色时只保留一条
+	mockPermissionCacheRepo.EXPECT().Load(ctx, "123", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userId string, load repository.PermissionLoader) ([]repository.ApiPermission, error) {
+			permissions, _, err := load(ctx, userId)
+			assert.Len(t, permissions, 2)
+			return permissions, err
+		}).Times(3)
+	mockUserRepo.EXPECT().NextRoleGrantChange(ctx, "123", gomock.Any()).Return(time.Time{}, nil).Times(3)
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(&[]repository.LoginedUser{
+		{RoleId: 1, Path: "/v1/users/*", Method: "GET"},
+		{RoleId: 2, Path: "/v1/users/*", Method: "GET"},
+		{RoleId: 2, Path: "/v1/users/:userId/impersonate", Method: "POST"},
+	}, nil).Times(3)
+
+	ok, err := userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId/sessions"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "POST", Route: "/v1/users/:userId/impersonate"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "DELETE", Route: "/v1/users/:userId/sessions"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestUserService_CheckAPIAuthPermission_NoPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockPermissionCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	userService := service.NewUserService(srv, mockUserRepo, mockPermissionCacheRepo, mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	// 没有任何api权限的用户同样缓存空集合
+	mockPermissionCacheRepo.EXPECT().Load(ctx, "123", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userId string, load repository.PermissionLoader) ([]repository.ApiPermission, error) {
+			permissions, _, err := load(ctx, userId)
+			assert.NoError(t, err)
+			assert.NotNil(t, permissions)
+			assert.Empty(t, permissions)
+			return permissions, err
+		})
+	mockUserRepo.EXPECT().NextRoleGrantChange(ctx, "123", gomock.Any()).Return(time.Time{}, nil)
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(nil, v1.ErrEmptyRecord)
+
+	ok, err := userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "GET", Route: "/v1/users/:userId"})
+	assert.ErrorIs(t, err, v1.ErrEmptyRecord)
+	assert.False(t, ok)
+}
+
+func TestUserService_CheckAPIAuthPermission_Deny(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockPermissionCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	userService := service.NewUserService(srv, mockUserRepo, mockPermissionCacheRepo, mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	mockPermissionCacheRepo.EXPECT().Load(ctx, "123", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userId string, load repository.PermissionLoader) ([]repository.ApiPermission, error) {
+			permissions, _, err := load(ctx, userId)
+			return permissions, err
+		}).AnyTimes()
+	mockUserRepo.EXPECT().NextRoleGrantChange(ctx, "123", gomock.Any()).Return(time.Time{}, nil).AnyTimes()
+	// 角色1允许全部用户接口，角色2拒绝导出
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(&[]repository.LoginedUser{
+		{RoleId: 1, Path: "/v1/users/*", Method: "all", Effect: "allow"},
+		{RoleId: 2, Path: "/v1/users/export", Method: "GET", Effect: "deny"},
+		{RoleId: 2, Path: "/v1/roles", Method: "all", Effect: "deny"},
+	}, nil).AnyTimes()
+
+	// 拒绝优先于允许
+	ok, err := userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "GET", Route: "/v1/users/export"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// 拒绝只影响匹配的方法
+	ok, err = userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "POST", Route: "/v1/users/export"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 只有拒绝规则匹配时同样拒绝
+	ok, err = userService.CheckAPIAuthPermission(ctx, "123", service.ApiRequest{Method: "GET", Route: "/v1/roles"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestUserService_GetMenuTreeByUserAuth_Deny(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	// 系统管理(1) -> 用户管理(2) -> 用户导出(3)，角色2拒绝用户管理
+	mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "menu", "asc").Return(&[]repository.LoginedUser{
+		{RoleId: 1, PermissionId: 1, PermissionName: "系统管理", Sort: "0001", Effect: "allow"},
+		{RoleId: 1, PermissionId: 2, PermissionName: "用户管理", ParentId: 1, Sort: "0001", Effect: "allow"},
+		{RoleId: 1, PermissionId: 3, PermissionName: "用户导出", ParentId: 2, Sort: "0001", Effect: "allow"},
+		{RoleId: 1, PermissionId: 4, PermissionName: "角色管理", ParentId: 1, Sort: "0002", Effect: "allow"},
+		{RoleId: 2, PermissionId: 2, PermissionName: "用户管理", ParentId: 1, Sort: "0001", Effect: "deny"},
+	}, nil)
+
+	tree, err := userService.GetMenuTreeByUserAuth(ctx, "123", "asc")
+	assert.NoError(t, err)
+	assert.Len(t, tree, 1)
+	// 被拒绝的菜单及其子菜单都不返回
+	assert.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "4", tree[0].Children[0].Key)
+}
+
+func TestResolveRoute(t *testing.T) {
+	routes := []service.RouteInfo{
+		{Method: "GET", Path: "/v1/users/:userId"},
+		{Method: "GET", Path: "/v1/users/me"},
+		{Method: "GET", Path: "/v1/:api/rbac/cache"},
+		{Method: "GET", Path: "/swagger/*any"},
+	}
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		route  string
+		params map[string]string
+		ok     bool
+	}{
+		{"param", "GET", "/v1/users/123", "/v1/users/:userId", map[string]string{"userId": "123"}, true},
+		{"static first", "GET", "/v1/users/me", "/v1/users/me", map[string]string{}, true},
+		{"template", "GET", "/v1/users/:userId", "/v1/users/:userId", map[string]string{}, true},
+		{"legacy api", "GET", "/v1/admin/rbac/cache?x=1", "/v1/:api/rbac/cache", map[string]string{"api": "admin"}, true},
+		{"catch all", "GET", "/swagger/index.html", "/swagger/*any", map[string]string{"any": "/index.html"}, true},
+		{"method mismatch", "DELETE", "/v1/users/123", "", nil, false},
+		{"too long", "GET", "/v1/users/123/roles", "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params, ok := service.ResolveRoute(routes, tt.method, tt.path)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.route, route)
+			assert.Equal(t, tt.params, params)
+		})
+	}
+}
+
+func TestRBACService_Explain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	srv := service.NewService(mock_repository.NewMockTransaction(ctrl), logger, sf, j)
+	rbacService := service.NewRBACService(srv, mock_repository.NewMockPermissionCacheRepository(ctrl), mockUserRepo, mockRoleRepo)
+	rbacService.SetRoutes([]service.RouteInfo{{Method: "DELETE", Path: "/v1/roles/:roleId"}})
+
+	ctx := context.Background()
+	_, err := rbacService.Explain(ctx, &v1.ExplainPermissionRequest{UserId: "123", Method: "delete", Path: "/v1/depts/3"})
+	assert.ErrorIs(t, err, v1.ErrRouteNotFound)
+
+	// 用户直接拥有角色1，临时授权角色2，角色1继承角色3；角色3允许，角色2拒绝，角色4允许
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil).AnyTimes()
+	mockRoleRepo.EXPECT().GetAssignedRoleIds(ctx, "123").Return([]uint{1}, []uint{2}, nil).AnyTimes()
+	mockRoleRepo.EXPECT().GetParents(ctx).Return(map[uint]uint{1: 3, 2: 0, 3: 0, 4: 0}, nil).AnyTimes()
+	roles := []model.Role{{Id: 1, RoleLabel: "ops"}, {Id: 2, RoleLabel: "auditor"}, {Id: 3, RoleLabel: "base"}, {Id: 4, RoleLabel: "admin"}}
+	permissions := []repository.RoleApiPermission{
+		{RoleId: 3, Permission: model.Permission{Id: 10, Path: "/v1/roles/*", Method: "all"}, Effect: model.EffectAllow},
+		{RoleId: 2, Permission: model.Permission{Id: 11, Path: "/v1/roles/:roleId", Method: "DELETE"}, Effect: model.EffectDeny},
+		{RoleId: 4, Permission: model.Permission{Id: 10, Path: "/v1/roles/*", Method: "all"}, Effect: model.EffectAllow},
+		{RoleId: 1, Permission: model.Permission{Id: 12, Path: "/v1/users/*", Method: "all"}, Effect: model.EffectAllow},
+	}
+
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{1, 3, 2}).Return(roles[:3], nil)
+	mockRoleRepo.EXPECT().GetApiPermissions(ctx, []uint{1, 3, 2}).Return(permissions[:2], nil)
+	data, err := rbacService.Explain(ctx, &v1.ExplainPermissionRequest{UserId: "123", Method: "delete", Path: "/v1/roles/5"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/roles/:roleId", data.Route)
+	assert.Nil(t, data.Current)
+	assert.False(t, data.Decision.Allowed)
+	assert.Equal(t, service.ExplainDenyRule, data.Decision.Reason)
+	assert.Equal(t, []v1.ExplainRoleData{
+		{Id: 1, RoleLabel: "ops", Source: service.RoleSourceDirect},
+		{Id: 2, RoleLabel: "auditor", Source: service.RoleSourceGrant},
+		{Id: 3, RoleLabel: "base", Source: service.RoleSourceInherited},
+	}, data.Decision.Roles)
+	assert.Len(t, data.Decision.Permissions, 1)
+	assert.Equal(t, []uint{2}, data.Decision.DenyRules[0].RoleIds)
+
+	// 演练移除临时授权并新增角色4，不存在的角色返回错误
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{9}).Return(nil, nil)
+	_, err = rbacService.Explain(ctx, &v1.ExplainPermissionRequest{UserId: "123", Method: "DELETE", Path: "/v1/roles/5",
+		DryRun: &v1.ExplainDryRunRequest{AddRoleIds: []uint{9}}})
+	assert.ErrorIs(t, err, v1.ErrRoleNotFound)
+
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{4}).Return(roles[3:], nil)
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{1, 3, 2, 4}).Return(roles, nil)
+	mockRoleRepo.EXPECT().GetApiPermissions(ctx, []uint{1, 3, 2, 4}).Return(permissions, nil)
+	data, err = rbacService.Explain(ctx, &v1.ExplainPermissionRequest{UserId: "123", Method: "DELETE", Path: "/v1/roles/5",
+		DryRun: &v1.ExplainDryRunRequest{AddRoleIds: []uint{4}, RemoveRoleIds: []uint{2}}})
+	assert.NoError(t, err)
+	assert.True(t, data.DryRun)
+	assert.True(t, data.Decision.Allowed)
+	assert.Equal(t, service.ExplainAllowRule, data.Decision.Reason)
+	assert.Empty(t, data.Decision.DenyRules)
+	assert.Equal(t, []uint{3, 4}, data.Decision.Permissions[0].RoleIds)
+	assert.Equal(t, service.RoleSourceProposed, data.Decision.Roles[len(data.Decision.Roles)-1].Source)
+	assert.False(t, data.Current.Allowed)
+}
+
+func TestUserService_CheckAPIAuthPermission_GrantExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	conf := viper.New()
+	conf.Set("security.rbac.cache.ttl", time.Minute)
+	cacheRepo := repository.NewPermissionCacheRepository(repository.NewRepository(logger, nil), conf)
+	srv := service.NewService(mock_repository.NewMockTransaction(ctrl), logger, sf, j)
+	userService := service.NewUserService(srv, mockUserRepo, cacheRepo, mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	req := service.ApiRequest{Method: "GET", Route: "/v1/users/:userId"}
+	// 临时授权在缓存ttl内结束，结束后不再使用缓存的权限
+	gomock.InOrder(
+		mockUserRepo.EXPECT().NextRoleGrantChange(ctx, "123", gomock.Any()).Return(time.Now().Add(50*time.Millisecond), nil),
+		mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(&[]repository.LoginedUser{
+			{RoleId: 2, Path: "/v1/users/*", Method: "GET", Effect: "allow"},
+		}, nil),
+		mockUserRepo.EXPECT().NextRoleGrantChange(ctx, "123", gomock.Any()).Return(time.Time{}, nil),
+		mockUserRepo.EXPECT().GetUserWithRolesAndPermission(ctx, "123", "api", "").Return(nil, v1.ErrEmptyRecord),
+	)
+
+	ok, err := userService.CheckAPIAuthPermission(ctx, "123", req)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = userService.CheckAPIAuthPermission(ctx, "123", req)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	time.Sleep(80 * time.Millisecond)
+	ok, _ = userService.CheckAPIAuthPermission(ctx, "123", req)
+	assert.False(t, ok)
+}
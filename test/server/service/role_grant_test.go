@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleGrantService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGrantRepo := mock_repository.NewMockRoleGrantRepository(ctrl)
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	grantService := service.NewRoleGrantService(srv, mockGrantRepo, mockRoleRepo, mockUserRepo, mockCacheRepo)
+
+	ctx := context.Background()
+	now := time.Now()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+
+	// 结束时间必须晚于开始时间和当前时间
+	_, err := grantService.Create(ctx, "admin", "123", &v1.CreateRoleGrantRequest{RoleId: 2, EndsAt: now.Add(-time.Minute), Reason: "oncall"})
+	assert.ErrorIs(t, err, v1.ErrRoleGrantPeriod)
+	startsAt := now.Add(2 * time.Hour)
+	_, err = grantService.Create(ctx, "admin", "123", &v1.CreateRoleGrantRequest{RoleId: 2, StartsAt: &startsAt, EndsAt: now.Add(time.Hour), Reason: "oncall"})
+	assert.ErrorIs(t, err, v1.ErrRoleGrantPeriod)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123", TenantId: 3}, nil).Times(2)
+	mockRoleRepo.EXPECT().GetById(ctx, uint(9), false).Return(nil, v1.ErrNotFound)
+	_, err = grantService.Create(ctx, "admin", "123", &v1.CreateRoleGrantRequest{RoleId: 9, EndsAt: now.Add(time.Hour), Reason: "oncall"})
+	assert.ErrorIs(t, err, v1.ErrRoleNotFound)
+
+	// 未指定开始时间时立即生效，授权跟随用户的租户
+	mockRoleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2, RoleLabel: "ops"}, nil)
+	mockGrantRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, grant *model.RoleGrant) error {
+		assert.Equal(t, uint(3), grant.TenantId)
+		assert.Equal(t, "admin", grant.GrantedBy)
+		assert.False(t, grant.StartsAt.Before(now))
+		return nil
+	})
+	mockCacheRepo.EXPECT().Invalidate(ctx, "123")
+	data, err := grantService.Create(ctx, "admin", "123", &v1.CreateRoleGrantRequest{RoleId: 2, EndsAt: now.Add(time.Hour), Reason: "oncall"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ops", data.RoleLabel)
+	assert.Nil(t, data.EndedAt)
+}
+
+func TestRoleGrantService_RevokeAndEndExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGrantRepo := mock_repository.NewMockRoleGrantRepository(ctrl)
+	mockCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	srv := service.NewService(mock_repository.NewMockTransaction(ctrl), logger, sf, j)
+	grantService := service.NewRoleGrantService(srv, mockGrantRepo, mock_repository.NewMockRoleRepository(ctrl),
+		mock_repository.NewMockUserRepository(ctrl), mockCacheRepo)
+
+	ctx := context.Background()
+	mockGrantRepo.EXPECT().GetById(ctx, uint(1)).Return(&model.RoleGrant{Id: 1, UserId: "123"}, nil).AnyTimes()
+
+	// 授权不属于该用户
+	assert.ErrorIs(t, grantService.Revoke(ctx, "admin", "456", 1), v1.ErrNotFound)
+
+	mockGrantRepo.EXPECT().Revoke(ctx, uint(1), "admin", gomock.Any()).Return(false, nil)
+	assert.ErrorIs(t, grantService.Revoke(ctx, "admin", "123", 1), v1.ErrRoleGrantEnded)
+
+	mockGrantRepo.EXPECT().Revoke(ctx, uint(1), "admin", gomock.Any()).Return(true, nil)
+	mockCacheRepo.EXPECT().Invalidate(ctx, "123")
+	assert.NoError(t, grantService.Revoke(ctx, "admin", "123", 1))
+
+	// 到期的授权结束后清除相关用户的缓存
+	mockGrantRepo.EXPECT().EndExpired(ctx, gomock.Any()).Return([]model.RoleGrant{
+		{Id: 2, UserId: "123", RoleId: 5, EndsAt: time.Now().Add(-time.Minute)},
+		{Id: 3, UserId: "456", RoleId: 5, EndsAt: time.Now().Add(-time.Second)},
+	}, nil)
+	mockCacheRepo.EXPECT().Invalidate(ctx, "123", "456")
+	count, err := grantService.EndExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,312 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mock_repository.NewMockPermissionCacheRepository(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	mockRoleRepo.EXPECT().Create(ctx, gomock.Any()).Return(v1.ErrRoleLabelExists)
+	_, err := roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "ops", RoleName: "运维"})
+	assert.ErrorIs(t, err, v1.ErrRoleLabelExists)
+
+	mockRoleRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, role *model.Role) error {
+		role.Id = 2
+		return nil
+	})
+	data, err := roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "ops", RoleName: "运维", TwoFactor: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), data.Id)
+	assert.True(t, data.TwoFactor)
+
+	// 父角色必须存在且未删除
+	mockRoleRepo.EXPECT().GetParents(ctx).Return(map[uint]uint{1: 0, 2: 0}, nil).Times(2)
+	_, err = roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "auditor", RoleName: "审计", ParentId: 9})
+	assert.ErrorIs(t, err, v1.ErrRoleNotFound)
+
+	mockRoleRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	data, err = roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "auditor", RoleName: "审计", ParentId: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), data.ParentId)
+}
+
+func TestRoleService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	srv := service.NewService(mock_repository.NewMockTransaction(ctrl), logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mock_repository.NewMockPermissionCacheRepository(ctrl))
+
+	ctx := context.Background()
+	// 未传分页参数时使用默认值，超出上限时取上限
+	mockRoleRepo.EXPECT().List(ctx, "", false, 0, 20).Return([]model.Role{{Id: 1, RoleLabel: "normal"}}, int64(1), nil)
+	mockRoleRepo.EXPECT().List(ctx, "ops", true, 200, 100).Return(nil, int64(0), nil)
+
+	data, err := roleService.List(ctx, &v1.ListRolesRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), data.Total)
+	assert.Equal(t, "normal", data.List[0].RoleLabel)
+
+	data, err = roleService.List(ctx, &v1.ListRolesRequest{PageRequest: v1.PageRequest{Page: 3, PageSize: 500}, Keyword: "ops", Deleted: true})
+	assert.NoError(t, err)
+	assert.Empty(t, data.List)
+}
+
+func TestRoleService_DefaultRoleProtected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mock_repository.NewMockPermissionCacheRepository(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	mockRoleRepo.EXPECT().GetById(ctx, uint(1), false).Return(&model.Role{Id: 1, RoleLabel: model.DefaultRoleLabel}, nil).AnyTimes()
+	mockRoleRepo.EXPECT().SetDataScopeDepts(ctx, uint(1), gomock.Nil()).Return(nil).AnyTimes()
+
+	assert.ErrorIs(t, roleService.Delete(ctx, 1), v1.ErrRoleProtected)
+	_, err := roleService.Update(ctx, 1, &v1.UpdateRoleRequest{RoleLabel: "member", RoleName: "成员"})
+	assert.ErrorIs(t, err, v1.ErrRoleProtected)
+
+	// 只修改名称
+	mockRoleRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	data, err := roleService.Update(ctx, 1, &v1.UpdateRoleRequest{RoleLabel: model.DefaultRoleLabel, RoleName: "成员"})
+	assert.NoError(t, err)
+	assert.Equal(t, "成员", data.RoleName)
+}
+
+func TestRoleService_DeleteAndRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	srv := service.NewService(mock_repository.NewMockTransaction(ctrl), logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mockCache)
+
+	ctx := context.Background()
+	mockRoleRepo.EXPECT().GetById(ctx, uint(9), false).Return(nil, v1.ErrNotFound)
+	assert.ErrorIs(t, roleService.Delete(ctx, 9), v1.ErrNotFound)
+
+	// 删除和恢复后清除权限缓存
+	mockRoleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2, RoleLabel: "ops"}, nil)
+	mockRoleRepo.EXPECT().Delete(ctx, uint(2)).Return(nil)
+	mockCache.EXPECT().InvalidateAll(ctx)
+	assert.NoError(t, roleService.Delete(ctx, 2))
+
+	deleted := &model.Role{Id: 2, RoleLabel: "ops"}
+	deleted.DeletedAt.Valid = true
+	mockRoleRepo.EXPECT().GetById(ctx, uint(2), true).Return(deleted, nil)
+	mockRoleRepo.EXPECT().Restore(ctx, uint(2)).Return(nil)
+	mockCache.EXPECT().InvalidateAll(ctx)
+	assert.NoError(t, roleService.Restore(ctx, 2))
+
+	// 未删除的角色无需恢复
+	mockRoleRepo.EXPECT().GetById(ctx, uint(3), true).Return(&model.Role{Id: 3}, nil)
+	assert.NoError(t, roleService.Restore(ctx, 3))
+}
+
+func TestRoleService_AttachPermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mockCache)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(2)
+	mockRoleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2}, nil).Times(2)
+
+	mockRoleRepo.EXPECT().AttachPermissions(ctx, uint(2), []uint{5}, "deny").Return(v1.ErrPermissionNotFound)
+	assert.ErrorIs(t, roleService.AttachPermissions(ctx, 2, []uint{5}, "deny"), v1.ErrPermissionNotFound)
+
+	// 未指定effect时按allow处理
+	mockRoleRepo.EXPECT().AttachPermissions(ctx, uint(2), []uint{1, 3}, "allow").Return(nil)
+	mockCache.EXPECT().InvalidateAll(ctx)
+	assert.NoError(t, roleService.AttachPermissions(ctx, 2, []uint{1, 3, 1}, ""))
+}
+
+func TestRoleService_SetUserRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mockUserRepo, mockCache)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(3)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil).Times(3)
+
+	// 角色不存在或已删除时不修改
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{1, 2}).Return([]model.Role{{Id: 1}}, nil)
+	assert.ErrorIs(t, roleService.SetUserRoles(ctx, "123", []uint{1, 2, 1}), v1.ErrRoleNotFound)
+
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{1, 2}).Return([]model.Role{{Id: 1}, {Id: 2}}, nil)
+	gomock.InOrder(
+		mockRoleRepo.EXPECT().ClearUserRoles(ctx, "123").Return(nil),
+		mockRoleRepo.EXPECT().AddUserRoles(ctx, []string{"123"}, []uint{1, 2}).Return(nil),
+	)
+	mockCache.EXPECT().Invalidate(ctx, "123")
+	assert.NoError(t, roleService.SetUserRoles(ctx, "123", []uint{1, 2}))
+
+	// 空数组移除全部角色
+	mockRoleRepo.EXPECT().ClearUserRoles(ctx, "123").Return(nil)
+	mockRoleRepo.EXPECT().AddUserRoles(ctx, []string{"123"}, []uint{}).Return(nil)
+	mockCache.EXPECT().Invalidate(ctx, "123")
+	assert.NoError(t, roleService.SetUserRoles(ctx, "123", []uint{}))
+}
+
+func TestRoleService_AddAndRemoveUserRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mockUserRepo, mockCache)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(3)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "gone").Return(nil, v1.ErrNotFound)
+	assert.ErrorIs(t, roleService.AddUserRoles(ctx, "gone", []uint{1}), v1.ErrNotFound)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil).Times(2)
+	mockRoleRepo.EXPECT().GetByIds(ctx, []uint{3}).Return([]model.Role{{Id: 3}}, nil)
+	mockRoleRepo.EXPECT().AddUserRoles(ctx, []string{"123"}, []uint{3}).Return(nil)
+	mockCache.EXPECT().Invalidate(ctx, "123").Times(2)
+	assert.NoError(t, roleService.AddUserRoles(ctx, "123", []uint{3}))
+
+	mockRoleRepo.EXPECT().RemoveUserRoles(ctx, "123", []uint{3}).Return(nil)
+	assert.NoError(t, roleService.RemoveUserRoles(ctx, "123", []uint{3, 3}))
+}
+
+func TestRoleService_AssignUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mockUserRepo, mockCache)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(2)
+	mockRoleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2}, nil).Times(2)
+
+	// 任一用户不存在时全部不关联
+	mockUserRepo.EXPECT().GetByIDs(ctx, []string{"a", "b"}).Return([]model.User{{UserId: "a"}}, nil)
+	assert.ErrorIs(t, roleService.AssignUsers(ctx, 2, []string{"a", "b", "a"}), v1.ErrUserNotFound)
+
+	mockUserRepo.EXPECT().GetByIDs(ctx, []string{"a", "b"}).Return([]model.User{{UserId: "a"}, {UserId: "b"}}, nil)
+	mockRoleRepo.EXPECT().AddUserRoles(ctx, []string{"a", "b"}, []uint{2}).Return(nil)
+	mockCache.EXPECT().Invalidate(ctx, "a", "b")
+	assert.NoError(t, roleService.AssignUsers(ctx, 2, []string{"a", "b"}))
+}
+
+func TestRoleService_UpdateParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockCache := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mockCache)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	// admin(3) -> ops(2) -> auditor(1)
+	mockRoleRepo.EXPECT().GetParents(ctx).Return(map[uint]uint{1: 0, 2: 1, 3: 2}, nil).AnyTimes()
+	mockRoleRepo.EXPECT().GetById(ctx, uint(1), false).Return(&model.Role{Id: 1, RoleLabel: "auditor"}, nil).AnyTimes()
+	mockRoleRepo.EXPECT().SetDataScopeDepts(ctx, gomock.Any(), gomock.Nil()).Return(nil).AnyTimes()
+
+	// 不能继承自身或继承自该角色的角色
+	for _, parentId := range []uint{1, 2, 3} {
+		_, err := roleService.Update(ctx, 1, &v1.UpdateRoleRequest{RoleLabel: "auditor", RoleName: "审计", ParentId: parentId})
+		assert.ErrorIs(t, err, v1.ErrRoleCycle, parentId)
+	}
+
+	// 修改父角色后清除权限缓存
+	mockRoleRepo.EXPECT().GetById(ctx, uint(3), false).Return(&model.Role{Id: 3, RoleLabel: "admin", ParentId: 2}, nil)
+	mockRoleRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	mockCache.EXPECT().InvalidateAll(ctx)
+	data, err := roleService.Update(ctx, 3, &v1.UpdateRoleRequest{RoleLabel: "admin", RoleName: "管理员", ParentId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), data.ParentId)
+
+	// 父角色未变化时不校验也不清除缓存
+	mockRoleRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	_, err = roleService.Update(ctx, 1, &v1.UpdateRoleRequest{RoleLabel: "auditor", RoleName: "审计员"})
+	assert.NoError(t, err)
+}
+
+func TestRoleService_DataScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mock_repository.NewMockRoleRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	roleService := service.NewRoleService(srv, mockRoleRepo, mock_repository.NewMockUserRepository(ctrl), mock_repository.NewMockPermissionCacheRepository(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+
+	// 未指定时为全部数据，不保存自定义部门
+	mockRoleRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	data, err := roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "ops", RoleName: "运维", DataScopeDeptIds: []uint{1}})
+	assert.NoError(t, err)
+	assert.Equal(t, model.DataScopeAll, data.DataScope)
+
+	// 自定义部门去重后保存，部门不存在时整体回滚
+	mockRoleRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, role *model.Role) error {
+		role.Id = 2
+		return nil
+	}).Times(2)
+	mockRoleRepo.EXPECT().SetDataScopeDepts(ctx, uint(2), []uint{3, 4}).Return(nil)
+	_, err = roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "hr", RoleName: "人事", DataScope: model.DataScopeCustom, DataScopeDeptIds: []uint{3, 4, 3}})
+	assert.NoError(t, err)
+	mockRoleRepo.EXPECT().SetDataScopeDepts(ctx, uint(2), []uint{9}).Return(v1.ErrDeptNotFound)
+	_, err = roleService.Create(ctx, &v1.CreateRoleRequest{RoleLabel: "hr", RoleName: "人事", DataScope: model.DataScopeCustom, DataScopeDeptIds: []uint{9}})
+	assert.ErrorIs(t, err, v1.ErrDeptNotFound)
+
+	// 改为其他范围时清空自定义部门
+	mockRoleRepo.EXPECT().GetById(ctx, uint(2), false).Return(&model.Role{Id: 2, RoleLabel: "hr", DataScope: model.DataScopeCustom}, nil)
+	mockRoleRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	mockRoleRepo.EXPECT().SetDataScopeDepts(ctx, uint(2), gomock.Nil()).Return(nil)
+	data, err = roleService.Update(ctx, 2, &v1.UpdateRoleRequest{RoleLabel: "hr", RoleName: "人事", DataScope: model.DataScopeSelf, DataScopeDeptIds: []uint{3}})
+	assert.NoError(t, err)
+	assert.Equal(t, model.DataScopeSelf, data.DataScope)
+}
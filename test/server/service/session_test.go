@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionService_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	sessionService := service.NewSessionService(srv, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl), mockTokenService)
+
+	ctx := context.Background()
+	mockSessionRepo.EXPECT().ListActiveByUser(ctx, "123").Return([]model.Session{
+		{SessionId: "a", UserId: "123", Device: "laptop", LastSeenAt: time.Now()},
+		{SessionId: "b", UserId: "123", Device: "phone", LastSeenAt: time.Now()},
+	}, nil)
+
+	sessions, err := sessionService.ListSessions(ctx, "123", "b")
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+}
+
+func TestSessionService_RevokeOwnSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	sessionService := service.NewSessionService(srv, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl), mockTokenService)
+
+	ctx := context.Background()
+	mockSessionRepo.EXPECT().GetBySessionId(ctx, "a").Return(&model.Session{SessionId: "a", UserId: "123"}, nil)
+	mockTokenService.EXPECT().RevokeSession(ctx, "a").Return(nil)
+
+	err := sessionService.RevokeOwnSession(ctx, "123", "a")
+
+	assert.NoError(t, err)
+}
+
+func TestSessionService_RevokeOwnSession_OtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	sessionService := service.NewSessionService(srv, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl), mockTokenService)
+
+	ctx := context.Background()
+	mockSessionRepo.EXPECT().GetBySessionId(ctx, "a").Return(&model.Session{SessionId: "a", UserId: "456"}, nil)
+
+	err := sessionService.RevokeOwnSession(ctx, "123", "a")
+
+	assert.ErrorIs(t, err, v1.ErrNotFound)
+}
+
+func TestSessionService_ForceRevokeUserSessions_OtherTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	sessionService := service.NewSessionService(srv, mock_repository.NewMockSessionRepository(ctrl), mockUserRepo, mockTokenService)
+
+	ctx := context.Background()
+	// 其他租户的用户按租户查询不到，不能吊销其会话
+	mockUserRepo.EXPECT().GetByID(ctx, "456").Return(nil, v1.ErrNotFound)
+	assert.ErrorIs(t, sessionService.ForceRevokeUserSessions(ctx, "456"), v1.ErrNotFound)
+
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil)
+	mockTokenService.EXPECT().LogoutAll(ctx, "123").Return(nil)
+	assert.NoError(t, sessionService.ForceRevokeUserSessions(ctx, "123"))
+}
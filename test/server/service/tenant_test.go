@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantService_CreateAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTenantRepo := mock_repository.NewMockTenantRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tenantService := service.NewTenantService(srv, mockTenantRepo, mock_repository.NewMockUserRepository(ctrl),
+		mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+
+	// 创建租户时同时创建默认角色
+	mockTenantRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, tenant *model.Tenant) error {
+		tenant.Id = 2
+		return nil
+	})
+	mockTenantRepo.EXPECT().CreateDefaultRole(gomock.Any(), uint(2)).Return(&model.Role{Id: 5, TenantId: 2}, nil)
+	data, err := tenantService.Create(ctx, &v1.CreateTenantRequest{Code: "acme", Name: "Acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), data.Id)
+
+	// 还有用户时不能删除
+	mockTenantRepo.EXPECT().GetById(gomock.Any(), uint(2)).Return(&model.Tenant{Id: 2}, nil).Times(2)
+	mockTenantRepo.EXPECT().CountUsers(gomock.Any(), uint(2)).Return(int64(1), nil)
+	assert.ErrorIs(t, tenantService.Delete(ctx, 2), v1.ErrTenantNotEmpty)
+
+	mockTenantRepo.EXPECT().CountUsers(gomock.Any(), uint(2)).Return(int64(0), nil)
+	mockTenantRepo.EXPECT().Delete(gomock.Any(), uint(2)).Return(nil)
+	assert.NoError(t, tenantService.Delete(ctx, 2))
+}
+
+func TestTenantService_MoveUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTenantRepo := mock_repository.NewMockTenantRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockCacheRepo := mock_repository.NewMockPermissionCacheRepository(ctrl)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tenantService := service.NewTenantService(srv, mockTenantRepo, mockUserRepo, mockCacheRepo, mockTokenService)
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	mockTenantRepo.EXPECT().GetById(gomock.Any(), uint(2)).Return(&model.Tenant{Id: 2}, nil).AnyTimes()
+
+	// 任一用户不存在时全部不移动
+	mockUserRepo.EXPECT().GetByIDs(gomock.Any(), []string{"u1", "gone"}).Return([]model.User{{UserId: "u1"}}, nil)
+	assert.ErrorIs(t, tenantService.MoveUsers(ctx, 2, []string{"u1", "gone", "u1"}), v1.ErrUserNotFound)
+
+	// 注销失败时整体回滚，不清除缓存
+	mockUserRepo.EXPECT().GetByIDs(gomock.Any(), []string{"u1", "u2"}).Return([]model.User{{UserId: "u1"}, {UserId: "u2"}}, nil).Times(2)
+	mockTenantRepo.EXPECT().GetDefaultRole(gomock.Any(), uint(2)).Return(&model.Role{Id: 5}, nil).Times(2)
+	mockTenantRepo.EXPECT().MoveUsers(gomock.Any(), []string{"u1", "u2"}, uint(2), uint(5)).Return(nil).Times(2)
+	mockTokenService.EXPECT().LogoutAll(gomock.Any(), "u1").Return(errors.New("redis down"))
+	assert.Error(t, tenantService.MoveUsers(ctx, 2, []string{"u1", "u2"}))
+
+	mockTokenService.EXPECT().LogoutAll(gomock.Any(), "u1").Return(nil)
+	mockTokenService.EXPECT().LogoutAll(gomock.Any(), "u2").Return(nil)
+	mockCacheRepo.EXPECT().Invalidate(gomock.Any(), "u1", "u2")
+	assert.NoError(t, tenantService.MoveUsers(ctx, 2, []string{"u1", "u2"}))
+}
@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/jwt"
+	"admin-webrtc-go/test/mocks/repository"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func runTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestTokenService_IssueTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mock_repository.NewMockTokenRepository(ctrl)
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	tokenService := service.NewTokenService(srv, mockTokenRepo, mockRevocationRepo, mockSessionRepo, mockUserRepo)
+
+	ctx := context.Background()
+	client := service.ClientInfo{Device: "laptop", Ip: "127.0.0.1", UserAgent: "curl"}
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123", TenantId: 7}, nil)
+	var sessionId string
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockSessionRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, session *model.Session) error {
+		assert.Equal(t, "123", session.UserId)
+		assert.Equal(t, "laptop", session.Device)
+		assert.Equal(t, "127.0.0.1", session.Ip)
+		// 登录时ctx中没有租户信息，会话的租户取自用户
+		assert.Equal(t, uint(7), session.TenantId)
+		sessionId = session.SessionId
+		return nil
+	})
+	mockTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, token *model.RefreshToken) error {
+		assert.Equal(t, sessionId, token.FamilyId)
+		return nil
+	})
+
+	data, err := tokenService.IssueTokens(ctx, "123", client)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, data.RefreshToken)
+	assert.Equal(t, int64(j.AccessExpire().Seconds()), data.ExpiresIn)
+	claims, err := j.ParseToken(data.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, sessionId, claims.SessionId)
+	// 令牌中带有用户所属的租户
+	assert.Equal(t, uint(7), claims.TenantId)
+}
+
+func TestTokenService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mock_repository.NewMockTokenRepository(ctrl)
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	tokenService := service.NewTokenService(srv, mockTokenRepo, mockRevocationRepo, mockSessionRepo, mockUserRepo)
+
+	ctx := context.Background()
+	stored := &model.RefreshToken{
+		Id:        1,
+		FamilyId:  "family",
+		UserId:    "123",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, jwt.HashToken("refresh")).Return(stored, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockTokenRepo.EXPECT().MarkRefreshTokenUsed(ctx, uint(1), gomock.Any()).Return(true, nil)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123"}, nil)
+	mockTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, token *model.RefreshToken) error {
+		assert.Equal(t, "family", token.FamilyId)
+		assert.Equal(t, "123", token.UserId)
+		return nil
+	})
+	mockSessionRepo.EXPECT().Touch(ctx, "family", "127.0.0.1", gomock.Any(), gomock.Any()).Return(nil)
+
+	data, err := tokenService.Refresh(ctx, "refresh", service.ClientInfo{Ip: "127.0.0.1"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, data.AccessToken)
+	assert.NotEqual(t, "refresh", data.RefreshToken)
+}
+
+func TestTokenService_Refresh_Reused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mock_repository.NewMockTokenRepository(ctrl)
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tokenService := service.NewTokenService(srv, mockTokenRepo, mockRevocationRepo, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	usedAt := time.Now().Add(-time.Minute)
+	stored := &model.RefreshToken{
+		Id:        1,
+		FamilyId:  "family",
+		UserId:    "123",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+
+	mockTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, jwt.HashToken("refresh")).Return(stored, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockRevocationRepo.EXPECT().RevokeSession(ctx, "family", gomock.Any()).Return(nil)
+	mockTokenRepo.EXPECT().RevokeRefreshTokenFamily(ctx, "family", gomock.Any()).Return(nil)
+
+	_, err := tokenService.Refresh(ctx, "refresh", service.ClientInfo{})
+
+	assert.ErrorIs(t, err, v1.ErrRefreshTokenReused)
+}
+
+func TestTokenService_Refresh_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mock_repository.NewMockTokenRepository(ctrl)
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tokenService := service.NewTokenService(srv, mockTokenRepo, mockRevocationRepo, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	mockTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, jwt.HashToken("refresh")).Return(&model.RefreshToken{
+		Id:        1,
+		FamilyId:  "family",
+		UserId:    "123",
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}, nil)
+
+	_, err := tokenService.Refresh(ctx, "refresh", service.ClientInfo{})
+
+	assert.ErrorIs(t, err, v1.ErrRefreshTokenInvalid)
+}
+
+func TestTokenService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mock_repository.NewMockTokenRepository(ctrl)
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tokenService := service.NewTokenService(srv, mockTokenRepo, mockRevocationRepo, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	token, _, err := j.GenAccessToken(jwt.MyCustomClaims{UserId: "123", SessionId: "session"})
+	assert.NoError(t, err)
+	claims, err := j.ParseToken(token)
+	assert.NoError(t, err)
+
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction).Times(2)
+	mockRevocationRepo.EXPECT().RevokeToken(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, revoked *model.RevokedToken) error {
+		assert.Equal(t, claims.ID, revoked.Jti)
+		assert.Equal(t, "123", revoked.UserId)
+		return nil
+	})
+	mockRevocationRepo.EXPECT().RevokeSession(ctx, "session", gomock.Any()).Return(nil)
+	mockTokenRepo.EXPECT().RevokeRefreshTokenFamily(ctx, "session", gomock.Any()).Return(nil)
+
+	err = tokenService.Logout(ctx, claims)
+
+	assert.NoError(t, err)
+}
+
+func TestTokenService_LogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenRepo := mock_repository.NewMockTokenRepository(ctrl)
+	mockRevocationRepo := mock_repository.NewMockRevocationRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockSessionRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	tokenService := service.NewTokenService(srv, mockTokenRepo, mockRevocationRepo, mockSessionRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	ctx := context.Background()
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockRevocationRepo.EXPECT().RevokeUserTokens(ctx, "123", gomock.Any()).Return(nil)
+	mockTokenRepo.EXPECT().RevokeUserRefreshTokens(ctx, "123", gomock.Any()).Return(nil)
+
+	err := tokenService.LogoutAll(ctx, "123")
+
+	assert.NoError(t, err)
+}
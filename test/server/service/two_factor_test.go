@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+	v1 "admin-webrtc-go/api/v1"
+	"admin-webrtc-go/internal/model"
+	"admin-webrtc-go/internal/service"
+	"admin-webrtc-go/pkg/totp"
+	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotp_RFC6238(t *testing.T) {
+	// RFC 6238附录B的SHA1测试向量
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	code, err := totp.Code(secret, totp.Step(time.Unix(59, 0)))
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+	code, err = totp.Code(secret, totp.Step(time.Unix(1111111109, 0)))
+	assert.NoError(t, err)
+	assert.Equal(t, "081804", code)
+}
+
+func TestTwoFactorService_Setup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_repository.NewMockTwoFactorRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	twoFactorService := service.NewTwoFactorService(srv, viper.New(), mockTwoFactorRepo, mockUserRepo, mock_service.NewMockLoginAttemptService(ctrl))
+
+	ctx := context.Background()
+	mockTwoFactorRepo.EXPECT().Get(ctx, "123").Return(nil, v1.ErrNotFound)
+	mockUserRepo.EXPECT().GetByID(ctx, "123").Return(&model.User{UserId: "123", Email: "xxx@gmail.com"}, nil)
+	mockTwoFactorRepo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, twoFactor *model.UserTwoFactor) error {
+		assert.Nil(t, twoFactor.EnabledAt)
+		return nil
+	})
+
+	data, err := twoFactorService.Setup(ctx, "123")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, data.Secret)
+	assert.True(t, strings.HasPrefix(data.OtpauthUri, "otpauth://totp/"))
+	assert.Contains(t, data.OtpauthUri, "secret="+data.Secret)
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_repository.NewMockTwoFactorRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	twoFactorService := service.NewTwoFactorService(srv, viper.New(), mockTwoFactorRepo, mockUserRepo, mock_service.NewMockLoginAttemptService(ctrl))
+
+	ctx := context.Background()
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+
+	mockTwoFactorRepo.EXPECT().Get(ctx, "123").Return(&model.UserTwoFactor{UserId: "123", Secret: secret}, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockTwoFactorRepo.EXPECT().UseStep(ctx, "123", gomock.Any()).Return(true, nil)
+	mockTwoFactorRepo.EXPECT().Enable(ctx, "123", gomock.Any()).Return(nil)
+	mockTwoFactorRepo.EXPECT().ReplaceRecoveryCodes(ctx, "123", gomock.Len(10)).Return(nil)
+
+	codes, err := twoFactorService.Confirm(ctx, "123", code)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+}
+
+func TestTwoFactorService_Verify_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_repository.NewMockTwoFactorRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	twoFactorService := service.NewTwoFactorService(srv, viper.New(), mockTwoFactorRepo, mockUserRepo, mock_service.NewMockLoginAttemptService(ctrl))
+
+	ctx := context.Background()
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	enabledAt := time.Now()
+
+	mockTwoFactorRepo.EXPECT().Get(ctx, "123").Return(&model.UserTwoFactor{UserId: "123", Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockTwoFactorRepo.EXPECT().UseStep(ctx, "123", gomock.Any()).Return(false, nil)
+
+	err := twoFactorService.Verify(ctx, "123", code)
+
+	assert.ErrorIs(t, err, v1.ErrTwoFactorCodeInvalid)
+}
+
+func TestTwoFactorService_Verify_RecoveryCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_repository.NewMockTwoFactorRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	twoFactorService := service.NewTwoFactorService(srv, viper.New(), mockTwoFactorRepo, mockUserRepo, mock_service.NewMockLoginAttemptService(ctrl))
+
+	ctx := context.Background()
+	enabledAt := time.Now()
+	mockTwoFactorRepo.EXPECT().Get(ctx, "123").Return(&model.UserTwoFactor{UserId: "123", Secret: "secret", EnabledAt: &enabledAt}, nil).Times(2)
+	gomock.InOrder(
+		mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "123", gomock.Any(), gomock.Any()).Return(true, nil),
+		mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, "123", gomock.Any(), gomock.Any()).Return(false, nil),
+	)
+
+	assert.NoError(t, twoFactorService.Verify(ctx, "123", "ABCDE-FGHIJ"))
+	assert.ErrorIs(t, twoFactorService.Verify(ctx, "123", "abcde-fghij"), v1.ErrTwoFactorCodeInvalid)
+}
+
+func TestTwoFactorService_Disable_RequiredByRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_repository.NewMockTwoFactorRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	twoFactorService := service.NewTwoFactorService(srv, viper.New(), mockTwoFactorRepo, mockUserRepo, mock_service.NewMockLoginAttemptService(ctrl))
+
+	ctx := context.Background()
+	mockTwoFactorRepo.EXPECT().RequiredByRole(ctx, "123").Return(true, nil)
+
+	err := twoFactorService.Disable(ctx, "123", "123456")
+
+	assert.ErrorIs(t, err, v1.ErrTwoFactorRequired)
+}
+
+func TestTwoFactorService_Disable_Lockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorRepo := mock_repository.NewMockTwoFactorRepository(ctrl)
+	mockLoginAttemptRepo := mock_repository.NewMockLoginAttemptRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	mockTm.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(runTransaction).AnyTimes()
+	srv := service.NewService(mockTm, logger, sf, j)
+	loginAttemptService := service.NewLoginAttemptService(srv, newLoginConf(), mockLoginAttemptRepo)
+	twoFactorService := service.NewTwoFactorService(srv, viper.New(), mockTwoFactorRepo, mock_repository.NewMockUserRepository(ctrl), loginAttemptService)
+
+	ctx := context.Background()
+	enabledAt := time.Now()
+	mockTwoFactorRepo.EXPECT().RequiredByRole(ctx, "123").Return(false, nil).AnyTimes()
+	mockTwoFactorRepo.EXPECT().Get(ctx, "123").Return(&model.UserTwoFactor{UserId: "123", Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+
+	// 错误的验证码计入账号的失败次数，达到上限后锁定
+	mockLoginAttemptRepo.EXPECT().Get(ctx, "account:123").Return(nil, nil)
+	mockLoginAttemptRepo.EXPECT().IncrFailures(ctx, "account:123", gomock.Any()).Return(3, nil)
+	mockLoginAttemptRepo.EXPECT().Lock(ctx, "account:123", gomock.Any()).Return(nil)
+	code, err := totp.Code("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", totp.Step(time.Now().Add(-time.Hour)))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, twoFactorService.Disable(ctx, "123", code), v1.ErrTwoFactorCodeInvalid)
+
+	// 锁定期间不再校验验证码
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	mockLoginAttemptRepo.EXPECT().Get(ctx, "account:123").Return(&model.LoginAttempt{Subject: "account:123", Failures: 3, LastFailedAt: time.Now(), LockedUntil: &lockedUntil}, nil).Times(2)
+	assert.ErrorIs(t, twoFactorService.Disable(ctx, "123", code), v1.ErrAccountLocked)
+	_, err = twoFactorService.RegenerateRecoveryCodes(ctx, "123", code)
+	assert.ErrorIs(t, err, v1.ErrAccountLocked)
+}
@@ -8,8 +8,10 @@ import (
 	v1 "admin-webrtc-go/api/v1"
 	"admin-webrtc-go/pkg/jwt"
 	"admin-webrtc-go/test/mocks/repository"
+	"admin-webrtc-go/test/mocks/service"
 	"os"
 	"testing"
+	"time"
 
 	"admin-webrtc-go/internal/model"
 	"admin-webrtc-go/internal/service"
@@ -57,7 +59,8 @@ func TestUserService_Register(t *testing.T) {
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
 
-	userService := service.NewUserService(srv, mockUserRepo)
+	mockAccountService := mock_service.NewMockAccountService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mockAccountService, mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	req := &v1.RegisterRequest{
@@ -67,12 +70,39 @@ func TestUserService_Register(t *testing.T) {
 
 	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(nil, nil)
 	mockTm.EXPECT().Transaction(ctx, gomock.Any()).Return(nil)
+	mockAccountService.EXPECT().SendVerifyEmail(ctx, gomock.Any()).Return(nil)
 
 	err := userService.Register(ctx, req)
 
 	assert.NoError(t, err)
 }
 
+func TestUserService_Register_WeakPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockPasswordService := mock_service.NewMockPasswordService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mockPasswordService)
+
+	ctx := context.Background()
+	req := &v1.RegisterRequest{
+		Password: "1",
+		Email:    "test@example.com",
+	}
+
+	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(nil, nil)
+	mockTm.EXPECT().Transaction(ctx, gomock.Any()).DoAndReturn(runTransaction)
+	mockPasswordService.EXPECT().SetPassword(ctx, gomock.Any(), "1").Return(&service.PasswordPolicyError{})
+
+	// 不符合密码策略时不创建用户
+	err := userService.Register(ctx, req)
+
+	assert.ErrorIs(t, err, v1.ErrPasswordPolicy)
+}
+
 func TestUserService_Register_UsernameExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -80,7 +110,7 @@ func TestUserService_Register_UsernameExists(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
-	userService := service.NewUserService(srv, mockUserRepo)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	req := &v1.RegisterRequest{
@@ -102,7 +132,10 @@ func TestUserService_Login(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
-	userService := service.NewUserService(srv, mockUserRepo)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTwoFactorService := mock_service.NewMockTwoFactorService(ctrl)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mockTokenService, mockTwoFactorService, mock_service.NewMockAccountService(ctrl), mockLoginAttemptService, mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	req := &v1.LoginRequest{
@@ -115,13 +148,120 @@ func TestUserService_Login(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(&model.User{
+		UserId:   "123",
 		Password: string(hashedPassword),
 	}, nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockTwoFactorService.EXPECT().Status(ctx, "123").Return(false, false, nil)
+	mockLoginAttemptService.EXPECT().Succeed(ctx, "123").Return(nil)
+	mockTokenService.EXPECT().IssueTokens(ctx, "123", service.ClientInfo{}).Return(&v1.LoginResponseData{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+	}, nil)
+
+	data, err := userService.Login(ctx, req, service.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, data.AccessToken)
+}
+
+func TestUserService_Login_TwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTwoFactorService := mock_service.NewMockTwoFactorService(ctrl)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mockTokenService, mockTwoFactorService, mock_service.NewMockAccountService(ctrl), mockLoginAttemptService, mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	req := &v1.LoginRequest{
+		Email:    "xxx@gmail.com",
+		Password: "password",
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		t.Error("failed to hash password")
+	}
+
+	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(&model.User{
+		UserId:   "123",
+		Password: string(hashedPassword),
+	}, nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockTwoFactorService.EXPECT().Status(ctx, "123").Return(true, false, nil)
+
+	data, err := userService.Login(ctx, req, service.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.True(t, data.TwoFactorRequired)
+	assert.Empty(t, data.AccessToken)
+	// 质询令牌不能当作访问令牌使用
+	_, err = j.ParseToken(data.ChallengeToken)
+	assert.Error(t, err)
+	claims, err := j.ParsePurposeToken(data.ChallengeToken, jwt.PurposeTwoFactor)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", claims.UserId)
+}
+
+func TestUserService_LoginTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockTokenService := mock_service.NewMockTokenService(ctrl)
+	mockTwoFactorService := mock_service.NewMockTwoFactorService(ctrl)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mockTokenService, mockTwoFactorService, mock_service.NewMockAccountService(ctrl), mockLoginAttemptService, mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	challenge, err := j.GenChallengeToken("123", jwt.PurposeTwoFactorSetup)
+	assert.NoError(t, err)
+
+	mockTokenService.EXPECT().IsRevoked(ctx, gomock.Any()).Return(false, nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, "123", "").Return(nil)
+	mockTwoFactorService.EXPECT().Confirm(ctx, "123", "123456").Return([]string{"aaaaa-bbbbb"}, nil)
+	mockTokenService.EXPECT().Logout(ctx, gomock.Any()).Return(nil)
+	mockLoginAttemptService.EXPECT().Succeed(ctx, "123").Return(nil)
+	mockTokenService.EXPECT().IssueTokens(ctx, "123", service.ClientInfo{}).Return(&v1.LoginResponseData{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+	}, nil)
+
+	data, err := userService.LoginTwoFactor(ctx, &v1.TwoFactorLoginRequest{
+		ChallengeToken: challenge,
+		Code:           "123456",
+	}, service.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access", data.AccessToken)
+	assert.Equal(t, []string{"aaaaa-bbbbb"}, data.RecoveryCodes)
+}
+
+func TestUserService_LoginTwoFactor_InvalidChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	token, err := userService.Login(ctx, req)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
 
+	// 访问令牌不能当作质询令牌使用
+	token, _, err := j.GenAccessToken(jwt.MyCustomClaims{UserId: "123"})
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+
+	_, err = userService.LoginTwoFactor(context.Background(), &v1.TwoFactorLoginRequest{
+		ChallengeToken: token,
+		Code:           "123456",
+	}, service.ClientInfo{})
+
+	assert.ErrorIs(t, err, v1.ErrChallengeInvalid)
 }
 
 func TestUserService_Login_UserNotFound(t *testing.T) {
@@ -131,7 +271,8 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
-	userService := service.NewUserService(srv, mockUserRepo)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mockLoginAttemptService, mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	req := &v1.LoginRequest{
@@ -139,13 +280,71 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 		Password: "password",
 	}
 
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "").Return(nil)
 	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(nil, errors.New("user not found"))
+	mockLoginAttemptService.EXPECT().Fail(ctx, "", "").Return(nil)
 
-	_, err := userService.Login(ctx, req)
+	_, err := userService.Login(ctx, req, service.ClientInfo{})
 
 	assert.Error(t, err)
 }
 
+func TestUserService_Login_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mockLoginAttemptService, mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	req := &v1.LoginRequest{
+		Email:    "xxx@gmail.com",
+		Password: "wrong",
+	}
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "127.0.0.1").Return(nil)
+	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(&model.User{
+		UserId:   "123",
+		Password: string(hashedPassword),
+	}, nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, "123", "").Return(nil)
+	mockLoginAttemptService.EXPECT().Fail(ctx, "123", "127.0.0.1").Return(nil)
+
+	_, err := userService.Login(ctx, req, service.ClientInfo{Ip: "127.0.0.1"})
+
+	assert.ErrorIs(t, err, v1.ErrUnauthorized)
+}
+
+func TestUserService_Login_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockTm := mock_repository.NewMockTransaction(ctrl)
+	srv := service.NewService(mockTm, logger, sf, j)
+	mockLoginAttemptService := mock_service.NewMockLoginAttemptService(ctrl)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mockLoginAttemptService, mock_service.NewMockPasswordService(ctrl))
+
+	ctx := context.Background()
+	req := &v1.LoginRequest{
+		Email:    "xxx@gmail.com",
+		Password: "password",
+	}
+
+	mockLoginAttemptService.EXPECT().Check(ctx, "", "").Return(nil)
+	mockUserRepo.EXPECT().GetByEmail(ctx, req.Email).Return(&model.User{UserId: "123"}, nil)
+	mockLoginAttemptService.EXPECT().Check(ctx, "123", "").Return(&service.RetryAfterError{Err: v1.ErrAccountLocked, RetryAfter: time.Minute})
+
+	_, err := userService.Login(ctx, req, service.ClientInfo{})
+
+	// 锁定期间不再校验密码
+	assert.ErrorIs(t, err, v1.ErrAccountLocked)
+}
+
 func TestUserService_GetProfile(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -153,7 +352,7 @@ func TestUserService_GetProfile(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
-	userService := service.NewUserService(srv, mockUserRepo)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	userId := "123"
@@ -176,7 +375,7 @@ func TestUserService_UpdateProfile(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
-	userService := service.NewUserService(srv, mockUserRepo)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	userId := "123"
@@ -203,7 +402,7 @@ func TestUserService_UpdateProfile_UserNotFound(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
 	mockTm := mock_repository.NewMockTransaction(ctrl)
 	srv := service.NewService(mockTm, logger, sf, j)
-	userService := service.NewUserService(srv, mockUserRepo)
+	userService := service.NewUserService(srv, mockUserRepo, mock_repository.NewMockPermissionCacheRepository(ctrl), mock_service.NewMockTokenService(ctrl), mock_service.NewMockTwoFactorService(ctrl), mock_service.NewMockAccountService(ctrl), mock_service.NewMockLoginAttemptService(ctrl), mock_service.NewMockPasswordService(ctrl))
 
 	ctx := context.Background()
 	userId := "123"